@@ -0,0 +1,171 @@
+package lexorank
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// Alphabet is the ordered set of characters used as rank digits. Ranks are
+// numbers in base Len(), so the characters must sort in the same order under
+// the collation the keys are stored with.
+type Alphabet struct {
+	chars []byte
+	index [256]int
+	base  *big.Int
+}
+
+var (
+	// Base75Alphabet is the default alphabet, every character from '0' to
+	// 'z'. It requires a binary, case-sensitive collation.
+	Base75Alphabet = mustAlphabet(string(defaultAlphabet))
+
+	// Base36Alphabet holds the digits and lowercase letters only. Keys built
+	// from it sort correctly under case-insensitive collations such as MySQL's
+	// utf8mb4_general_ci, which treat 'A' and 'a' as equal.
+	Base36Alphabet = mustAlphabet("0123456789abcdefghijklmnopqrstuvwxyz")
+
+	// Base62Alphabet holds the digits and both cases of letters, the digits
+	// of the fractional-indexing scheme. See FractionalKeyBetween.
+	Base62Alphabet = mustAlphabet("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz")
+)
+
+// NewAlphabet creates an alphabet from characters in ascending order. At least
+// three characters are required so that the minimum, middle and maximum are
+// distinct, and every character must be between Minimum and Maximum so keys
+// remain parseable.
+func NewAlphabet(chars string) (*Alphabet, error) {
+	if len(chars) < 3 {
+		return nil, errors.Errorf("alphabet needs at least 3 characters, got %d", len(chars))
+	}
+
+	a := &Alphabet{
+		chars: []byte(chars),
+		base:  big.NewInt(int64(len(chars))),
+	}
+	for i := range a.index {
+		a.index[i] = -1
+	}
+	for i, c := range a.chars {
+		if c < Minimum || c > Maximum {
+			return nil, errors.Errorf("invalid alphabet character: %c", c)
+		}
+		if i > 0 && c <= a.chars[i-1] {
+			return nil, errors.Errorf("alphabet must be strictly ascending, %c follows %c", c, a.chars[i-1])
+		}
+		a.index[c] = i
+	}
+
+	return a, nil
+}
+
+func mustAlphabet(chars string) *Alphabet {
+	a, err := NewAlphabet(chars)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// String returns the characters of the alphabet.
+func (a *Alphabet) String() string { return string(a.chars) }
+
+// Len returns the number of characters, which is the numeric base of ranks.
+func (a *Alphabet) Len() int { return len(a.chars) }
+
+// Min returns the lowest character.
+func (a *Alphabet) Min() byte { return a.chars[0] }
+
+// Mid returns the character in the middle of the alphabet.
+func (a *Alphabet) Mid() byte { return a.chars[len(a.chars)/2] }
+
+// Max returns the highest character.
+func (a *Alphabet) Max() byte { return a.chars[len(a.chars)-1] }
+
+// Contains reports whether every character of rank is part of the alphabet.
+func (a *Alphabet) Contains(rank []byte) bool {
+	for _, c := range rank {
+		if a.index[c] == -1 {
+			return false
+		}
+	}
+	return true
+}
+
+// digits returns the digit values of rank. Characters outside the alphabet are
+// treated as zero.
+func (a *Alphabet) digits(rank []byte) []int {
+	digits := make([]int, len(rank))
+	for i, c := range rank {
+		digits[i] = max(a.index[c], 0)
+	}
+	return digits
+}
+
+// value returns the numeric value of rank.
+func (a *Alphabet) value(rank []byte) *big.Int {
+	result := big.NewInt(0)
+	for _, d := range a.digits(rank) {
+		result.Mul(result, a.base)
+		result.Add(result, big.NewInt(int64(d)))
+	}
+	return result
+}
+
+// scale returns the numeric value of a rank as if it had been right-padded
+// with the minimum character up to the given length.
+func (a *Alphabet) scale(rank []byte, length int) *big.Int {
+	v := a.value(rank)
+	if length > len(rank) {
+		v.Mul(v, a.space(length-len(rank)))
+	}
+	return v
+}
+
+// space returns the number of distinct ranks at the given length.
+func (a *Alphabet) space(length int) *big.Int {
+	return new(big.Int).Exp(a.base, big.NewInt(int64(length)), nil)
+}
+
+// encode encodes val as a rank of exactly the given length.
+func (a *Alphabet) encode(val *big.Int, length int) []byte {
+	out := make([]byte, length)
+	temp := new(big.Int).Set(val)
+	rem := new(big.Int)
+	for i := length - 1; i >= 0; i-- {
+		temp.DivMod(temp, a.base, rem)
+		out[i] = a.chars[min(int(rem.Int64()), len(a.chars)-1)]
+	}
+	return out
+}
+
+// encodeMinimal encodes val as the shortest rank with that value.
+func (a *Alphabet) encodeMinimal(val *big.Int) []byte {
+	if val.Sign() == 0 {
+		return []byte{a.Min()}
+	}
+
+	var out []byte
+	temp := new(big.Int).Set(val)
+	rem := new(big.Int)
+	for temp.Sign() > 0 {
+		temp.DivMod(temp, a.base, rem)
+		out = append(out, a.chars[rem.Int64()])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+// trimTrailingMinimum removes trailing minimum characters from a rank. The
+// trimmed rank has the same numeric value at any scale, so it keeps its place
+// strictly between the inputs of Between while being shorter.
+func (a *Alphabet) trimTrailingMinimum(rank []byte) []byte {
+	trimmed := bytes.TrimRight(rank, string(a.Min()))
+	if len(trimmed) == 0 {
+		return rank[:1]
+	}
+	return trimmed
+}
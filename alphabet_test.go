@@ -0,0 +1,83 @@
+package lexorank
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAlphabet(t *testing.T) {
+	a := assert.New(t)
+
+	_, err := NewAlphabet("01")
+	a.Error(err)
+	_, err = NewAlphabet("021")
+	a.Error(err)
+	_, err = NewAlphabet("01|")
+	a.Error(err)
+
+	a.Equal(75, Base75Alphabet.Len())
+	a.Equal(byte(Midpoint), Base75Alphabet.Mid())
+	a.Equal(36, Base36Alphabet.Len())
+	a.Equal(byte('0'), Base36Alphabet.Min())
+	a.Equal(byte('i'), Base36Alphabet.Mid())
+	a.Equal(byte('z'), Base36Alphabet.Max())
+}
+
+func TestCaseInsensitiveConfig(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := CaseInsensitiveConfig()
+
+	lhs, err := config.ParseKey("0|a")
+	r.NoError(err)
+	rhs, err := config.ParseKey("0|b")
+	r.NoError(err)
+
+	// 'a' and 'b' are adjacent in base 36, so a digit is added
+	mid, err := Between(*lhs, *rhs, config)
+	r.NoError(err)
+	a.Equal("0|ai", mid.String())
+
+	_, err = config.ParseKey("0|A")
+	a.Error(err)
+	_, err = Between(MiddleOf(0), TopOf(0), config)
+	a.Error(err, "'U' is not part of the base-36 alphabet")
+
+	next, err := config.WithAppendStrategy(AppendStrategyStep).WithStepSize(1).ParseKey("0|9")
+	r.NoError(err)
+	after, err := next.After(1)
+	r.NoError(err)
+	a.Equal("0|a", after.String())
+}
+
+func TestCaseInsensitiveConfig_List(t *testing.T) {
+	r := require.New(t)
+
+	config := CaseInsensitiveConfig()
+	rnd := rand.New(rand.NewSource(1))
+
+	list := ReorderableList{}
+	for i := range 200 {
+		position := uint(rnd.Intn(len(list) + 1))
+		k, err := list.Insert(position, config)
+		r.NoError(err)
+
+		it := item(i, k.String())
+		list = append(list[:position], append(ReorderableList{it}, list[position:]...)...)
+	}
+
+	r.True(list.StrictlySorted())
+	for _, it := range list {
+		r.True(Base36Alphabet.Contains(it.GetKey().rank), it.GetKey().String())
+	}
+
+	res, err := ReserveRange(list[0].GetKey(), nil, 10, config)
+	r.NoError(err)
+	for _, k := range res.Keys() {
+		r.True(Base36Alphabet.Contains(k.rank), k.String())
+	}
+}
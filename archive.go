@@ -0,0 +1,162 @@
+package lexorank
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// ArchiveVersion is the current version of the archive format.
+const ArchiveVersion = 1
+
+// ArchivedConfig is the serialisable part of a Config. Every setting that
+// affects which keys are generated is spelled out, so an archive restores the
+// exact semantics it was written with even if the defaults of DefaultConfig
+// change. Function fields such as TieBreaker and OnLongKey, and the Metrics,
+// Logger and Audit hooks are not archived.
+type ArchivedConfig struct {
+	AutoNormalize       bool              `json:"auto_normalize"`
+	NormalizeThreshold  int               `json:"normalize_threshold"`
+	MaxRankLength       int               `json:"max_rank_length"`
+	MinRankLength       int               `json:"min_rank_length"`
+	AppendStrategy      AppendStrategy    `json:"append_strategy"`
+	StepSize            int64             `json:"step_size"`
+	MaxStepSize         int64             `json:"max_step_size"`
+	PrependStrategy     *AppendStrategy   `json:"prepend_strategy,omitempty"`
+	PrependStepSize     int64             `json:"prepend_step_size,omitempty"`
+	TrimTrailingMinimum bool              `json:"trim_trailing_minimum"`
+	GrowthPolicy        GrowthPolicy      `json:"growth_policy"`
+	GrowthJump          int               `json:"growth_jump"`
+	StrictBuckets       bool              `json:"strict_buckets"`
+	MaxListSize         int               `json:"max_list_size"`
+	MaxKeysRewritten    int               `json:"max_keys_rewritten"`
+	MaxRebalanceWrites  int               `json:"max_rebalance_writes"`
+	NormalizeMinGap     int64             `json:"normalize_min_gap"`
+	RebalanceStrategy   RebalanceStrategy `json:"rebalance_strategy"`
+	MinimalRebalance    bool              `json:"minimal_rebalance"`
+	WarnRankLength      int               `json:"warn_rank_length"`
+	Separator           string            `json:"separator"`
+	Alphabet            string            `json:"alphabet"`
+	Watermark           string            `json:"watermark,omitempty"`
+}
+
+// Archive returns the serialisable settings of the config. The separator and
+// alphabet are always recorded explicitly, even when left at their defaults.
+func (c *Config) Archive() ArchivedConfig {
+	a := ArchivedConfig{
+		AutoNormalize:       c.AutoNormalize,
+		NormalizeThreshold:  c.NormalizeThreshold,
+		MaxRankLength:       c.MaxRankLength,
+		MinRankLength:       c.MinRankLength,
+		AppendStrategy:      c.AppendStrategy,
+		StepSize:            c.StepSize,
+		MaxStepSize:         c.MaxStepSize,
+		PrependStrategy:     c.PrependStrategy,
+		PrependStepSize:     c.PrependStepSize,
+		TrimTrailingMinimum: c.TrimTrailingMinimum,
+		GrowthPolicy:        c.GrowthPolicy,
+		GrowthJump:          c.GrowthJump,
+		StrictBuckets:       c.StrictBuckets,
+		MaxListSize:         c.MaxListSize,
+		MaxKeysRewritten:    c.MaxKeysRewritten,
+		MaxRebalanceWrites:  c.MaxRebalanceWrites,
+		NormalizeMinGap:     c.NormalizeMinGap,
+		RebalanceStrategy:   c.RebalanceStrategy,
+		MinimalRebalance:    c.MinimalRebalance,
+		WarnRankLength:      c.WarnRankLength,
+		Separator:           string(c.separator()),
+		Alphabet:            c.alphabet().String(),
+	}
+	if c.Watermark != 0 {
+		a.Watermark = string(c.Watermark)
+	}
+	return a
+}
+
+// Config rebuilds the configuration recorded by Archive.
+func (a ArchivedConfig) Config() (*Config, error) {
+	if len(a.Separator) != 1 {
+		return nil, errors.Errorf("invalid archived separator %q", a.Separator)
+	}
+	if len(a.Watermark) > 1 {
+		return nil, errors.Errorf("invalid archived watermark %q", a.Watermark)
+	}
+
+	alphabet, err := NewAlphabet(a.Alphabet)
+	if err != nil {
+		return nil, errors.Wrap(err, "archived alphabet")
+	}
+
+	c := &Config{
+		AutoNormalize:       a.AutoNormalize,
+		NormalizeThreshold:  a.NormalizeThreshold,
+		MaxRankLength:       a.MaxRankLength,
+		MinRankLength:       a.MinRankLength,
+		AppendStrategy:      a.AppendStrategy,
+		StepSize:            a.StepSize,
+		MaxStepSize:         a.MaxStepSize,
+		PrependStrategy:     a.PrependStrategy,
+		PrependStepSize:     a.PrependStepSize,
+		TrimTrailingMinimum: a.TrimTrailingMinimum,
+		GrowthPolicy:        a.GrowthPolicy,
+		GrowthJump:          a.GrowthJump,
+		StrictBuckets:       a.StrictBuckets,
+		MaxListSize:         a.MaxListSize,
+		MaxKeysRewritten:    a.MaxKeysRewritten,
+		MaxRebalanceWrites:  a.MaxRebalanceWrites,
+		NormalizeMinGap:     a.NormalizeMinGap,
+		RebalanceStrategy:   a.RebalanceStrategy,
+		MinimalRebalance:    a.MinimalRebalance,
+		WarnRankLength:      a.WarnRankLength,
+		Separator:           a.Separator[0],
+		Alphabet:            alphabet,
+	}
+	if a.Watermark != "" {
+		c.Watermark = a.Watermark[0]
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type archive struct {
+	Version int             `json:"version"`
+	Config  ArchivedConfig  `json:"config"`
+	List    ReorderableList `json:"list"`
+}
+
+// MarshalArchive encodes a list snapshot, as produced by MarshalJSON, together
+// with the configuration its keys were generated under, in a versioned JSON
+// envelope meant for long-term storage. Items must implement Identifiable.
+func MarshalArchive(l ReorderableList, config *Config) ([]byte, error) {
+	return json.Marshal(archive{Version: ArchiveVersion, Config: config.Archive(), List: l})
+}
+
+// UnmarshalArchive restores a list and its configuration from an archive
+// produced by MarshalArchive. Every key is checked against the archived
+// separator and alphabet. The restored list is made of *SnapshotItem values.
+func UnmarshalArchive(data []byte) (ReorderableList, *Config, error) {
+	var a archive
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, nil, err
+	}
+	if a.Version != ArchiveVersion {
+		return nil, nil, errors.Errorf("unsupported archive version: %d", a.Version)
+	}
+
+	config, err := a.Config.Config()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, it := range a.List {
+		k, err := config.ParseKey(it.GetKey().String())
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "key of item %d", i)
+		}
+		it.SetKey(*k)
+	}
+
+	return a.List, config, nil
+}
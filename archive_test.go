@@ -0,0 +1,59 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive_RoundTrip(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := CaseInsensitiveConfig().
+		WithSeparator(':').
+		WithMaxRankLength(10).
+		WithAppendStrategy(AppendStrategyStep).
+		WithStepSize(36).
+		WithPrependStrategy(AppendStrategyDefault).
+		WithWatermark('z')
+
+	list := ReorderableList{item(0, "0:a"), item(1, "0:m"), item(2, "1:0")}
+	data, err := MarshalArchive(list, config)
+	r.NoError(err)
+
+	restored, rc, err := UnmarshalArchive(data)
+	r.NoError(err)
+	r.Len(restored, len(list))
+	for i := range list {
+		a.Equal(list[i].GetKey().String(), restored[i].GetKey().String())
+	}
+	a.Equal(config.Archive(), rc.Archive())
+	a.Equal(Base36Alphabet.String(), restored[0].GetKey().Alphabet().String())
+
+	k, err := restored.Append(rc)
+	r.NoError(err)
+	want, err := list.Append(config)
+	r.NoError(err)
+	a.Equal(want.String(), k.String(), "keys generated after restoring match the original config")
+}
+
+func TestArchive_Defaults(t *testing.T) {
+	a := assert.New(t)
+
+	ac := DefaultConfig().Archive()
+	a.Equal("|", ac.Separator)
+	a.Equal(Base75Alphabet.String(), ac.Alphabet)
+	a.Empty(ac.Watermark)
+}
+
+func TestUnmarshalArchive_Invalid(t *testing.T) {
+	a := assert.New(t)
+
+	_, _, err := UnmarshalArchive([]byte(`{"version":2}`))
+	a.ErrorContains(err, "unsupported archive version")
+
+	_, _, err = UnmarshalArchive([]byte(`{"version":1,"config":{"separator":"|","alphabet":"0123456789abcdefghijklmnopqrstuvwxyz"},"list":{"version":1,"items":[{"id":"0","key":"0|A"}]}}`))
+	a.Error(err, "A is not part of the archived alphabet")
+}
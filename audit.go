@@ -0,0 +1,98 @@
+package lexorank
+
+import "sync"
+
+// AuditReason describes why a list operation rewrote the key of an item.
+type AuditReason string
+
+const (
+	// AuditRebalance means the key was shifted by a local rebalance or
+	// OpenGap to make room next to it.
+	AuditRebalance AuditReason = "rebalance"
+
+	// AuditNormalize means the key was respaced by Normalize or
+	// NormalizeRange, including a normalization an insert fell back to.
+	AuditNormalize AuditReason = "normalize"
+
+	// AuditMove means the item was the one moved by Move.
+	AuditMove AuditReason = "move"
+
+	// AuditRepair means Repair gave a duplicate key a new one.
+	AuditRepair AuditReason = "repair"
+
+	// AuditEqualize means EqualizeSmallestGaps widened a gap next to the key.
+	AuditEqualize AuditReason = "equalize"
+
+	// AuditShuffle means ShuffleRange gave the item a random position.
+	AuditShuffle AuditReason = "shuffle"
+
+	// AuditMigrateBucket means MigrateToBucket moved the item to a new bucket.
+	AuditMigrateBucket AuditReason = "migrate-bucket"
+
+	// AuditReassignBuckets means ReassignBuckets moved the item to the bucket
+	// chosen for it, or respaced the bucket it arrived in.
+	AuditReassignBuckets AuditReason = "reassign-buckets"
+
+	// AuditChangeSet means the key was assigned by ApplyChangeSet or SetKeys.
+	AuditChangeSet AuditReason = "change-set"
+)
+
+// AuditEntry records a single key rewritten by a list operation.
+type AuditEntry struct {
+	// Index is the index of the item in the list. Inserts rewrite neighbours
+	// before the caller adds the new item, so their indices don't count it;
+	// for Move it is the item's final index.
+	Index int `json:"index"`
+
+	// ID is the item's ID if it implements Identifiable, and empty otherwise
+	ID string `json:"id,omitempty"`
+
+	Old    Key         `json:"old"`
+	New    Key         `json:"new"`
+	Reason AuditReason `json:"reason"`
+}
+
+// AuditLog collects an AuditEntry for every existing key that list operations
+// rewrite, from Insert, Move and Normalize to Repair and ApplyChangeSet, for
+// compliance records of who changed an ordering or to find out why a list
+// was rebalanced. Set it as Config.Audit and read it back once the operation
+// returns. Keys given to new items are returned to the caller rather than
+// set, so they are not recorded. An AuditLog is safe for concurrent use.
+type AuditLog struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+// Entries returns a copy of the recorded entries, oldest first.
+func (a *AuditLog) Entries() []AuditEntry {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]AuditEntry(nil), a.entries...)
+}
+
+// Reset discards the recorded entries.
+func (a *AuditLog) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.entries = nil
+}
+
+func (a *AuditLog) add(e AuditEntry) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.entries = append(a.entries, e)
+}
+
+// setKey sets the key of the item at index i, recording the change in
+// config.Audit if set and counting it in config.Metrics.
+func (l ReorderableList) setKey(i int, k Key, reason AuditReason, config *Config) {
+	config.metrics().KeysRewritten(1)
+	if config.Audit != nil {
+		e := AuditEntry{Index: i, Old: l[i].GetKey(), New: k, Reason: reason}
+		if id, ok := l[i].(Identifiable); ok {
+			e.ID = id.GetID()
+		}
+		config.Audit.add(e)
+	}
+	l[i].SetKey(k)
+}
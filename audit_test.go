@@ -0,0 +1,145 @@
+package lexorank
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// auditedIndices checks every entry against the list and the keys it had
+// before, and returns the recorded indices.
+func auditedIndices(t *testing.T, l ReorderableList, before Keys, entries []AuditEntry) []int {
+	a := assert.New(t)
+
+	var indices []int
+	for _, e := range entries {
+		a.Equal(strconv.Itoa(e.Index), e.ID)
+		a.Equal(l[e.Index].GetKey(), e.New)
+		a.Equal(before[e.Index], e.Old)
+		indices = append(indices, e.Index)
+	}
+	return indices
+}
+
+func TestAuditLog_Insert(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	log := &AuditLog{}
+	config := DefaultConfig().WithMaxRankLength(2).WithMinimalRebalance(true).WithAudit(log)
+
+	list := ReorderableList{item(0, "0|a"), item(1, "0|ba"), item(2, "0|bb"), item(3, "0|c")}
+	before := list.Keys()
+	changed, err := list.Track(func() error {
+		_, err := list.Insert(2, config)
+		return err
+	})
+	r.NoError(err)
+	r.NotEmpty(changed)
+
+	entries := log.Entries()
+	a.Equal(changed, auditedIndices(t, list, before, entries))
+	for _, e := range entries {
+		a.Equal(AuditRebalance, e.Reason)
+	}
+
+	// A fast path insert rewrites nothing
+	log.Reset()
+	_, err = list.Insert(1, config)
+	r.NoError(err)
+	a.Empty(log.Entries())
+}
+
+func TestAuditLog_Normalize(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	log := &AuditLog{}
+	config := DefaultConfig().WithAudit(log)
+
+	list := ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|c")}
+	before := list.Keys()
+	changed, err := list.Normalize(config)
+	r.NoError(err)
+
+	entries := log.Entries()
+	a.Equal(changed, auditedIndices(t, list, before, entries))
+	for _, e := range entries {
+		a.Equal(AuditNormalize, e.Reason)
+	}
+
+	// A full gap in a list with no room left falls back to a normalize
+	log.Reset()
+	list = ReorderableList{item(0, "0|a"), item(1, "0|a0"), item(2, "0|a1")}
+	before = list.Keys()
+	changed, err = list.Track(func() error {
+		_, err := list.Insert(1, config.WithRebalanceStrategy(RebalanceFullNormalize).WithMaxRankLength(2))
+		return err
+	})
+	r.NoError(err)
+	a.Equal(changed, auditedIndices(t, list, before, log.Entries()))
+	a.Equal(AuditNormalize, log.Entries()[0].Reason)
+}
+
+func TestAuditLog_Move(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	log := &AuditLog{}
+	config := DefaultConfig().WithMaxRankLength(2).WithMinimalRebalance(true).WithAudit(log)
+
+	list := ReorderableList{item(0, "0|a"), item(1, "0|ba"), item(2, "0|bb"), item(3, "0|c"), item(4, "0|d")}
+	touched, err := list.Move(4, 2, config)
+	r.NoError(err)
+	r.Greater(len(touched), 1)
+
+	entries := log.Entries()
+	var indices []int
+	for _, e := range entries {
+		a.Equal(list[e.Index].(Identifiable).GetID(), e.ID)
+		a.Equal(list[e.Index].GetKey(), e.New)
+		indices = append(indices, e.Index)
+	}
+	a.ElementsMatch(touched, indices)
+
+	last := entries[len(entries)-1]
+	a.Equal(AuditMove, last.Reason)
+	a.Equal("4", last.ID)
+	a.Equal(2, last.Index)
+	a.Equal(mustKey("0|d"), last.Old)
+}
+
+func TestAuditLog_Maintenance(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	log := &AuditLog{}
+	config := DefaultConfig().WithAudit(log)
+
+	list := ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|b"), item(3, "0|c")}
+	r.NoError(list.Repair(config))
+	entries := log.Entries()
+	r.Len(entries, 1)
+	a.Equal(AuditEntry{Index: 2, ID: "2", Old: mustKey("0|b"), New: list[2].GetKey(), Reason: AuditRepair}, entries[0])
+
+	log.Reset()
+	before := list.Keys()
+	r.NoError(list.ApplyChangeSet(ChangeSet{{Index: 0, Key: mustKey("0|A")}, {Index: 3, Key: mustKey("0|x")}}, config))
+	a.Equal([]int{0, 3}, auditedIndices(t, list, before, log.Entries()))
+	for _, e := range log.Entries() {
+		a.Equal(AuditChangeSet, e.Reason)
+	}
+
+	// A rejected change set records nothing
+	log.Reset()
+	a.ErrorIs(list.ApplyChangeSet(ChangeSet{{Index: 0, Key: mustKey("0|z")}}, config), ErrNotSorted)
+	a.Empty(log.Entries())
+
+	before = list.Keys()
+	changed, err := list.MigrateToBucket(1, config)
+	r.NoError(err)
+	a.Equal(changed, auditedIndices(t, list, before, log.Entries()))
+	a.Equal(AuditMigrateBucket, log.Entries()[0].Reason)
+}
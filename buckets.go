@@ -0,0 +1,135 @@
+package lexorank
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// ReassignBuckets moves items into the bucket chosen by the predicate, for
+// lists that use buckets as coarse lanes such as active and archived. Within
+// each bucket items keep the order they have in the list, so moved items are
+// placed among the ones already there by their current position. Items that
+// stay in their bucket keep their keys unless there is no room around the
+// moved ones, in which case that bucket is spread out again.
+//
+// The list is re-sorted in place and the indices of the items with new keys
+// are returned, in ascending order, for use with ChangeSet.
+func (l ReorderableList) ReassignBuckets(predicate func(Reorderable) uint8, config *Config) ([]int, error) {
+	targets := make(map[uint8][]int)
+	var order []uint8
+	for i, it := range l {
+		b := predicate(it)
+		if b > MaxBucket {
+			return nil, fmt.Errorf("invalid bucket: %d (maximum %d)", b, MaxBucket)
+		}
+		if _, ok := targets[b]; !ok {
+			order = append(order, b)
+		}
+		targets[b] = append(targets[b], i)
+	}
+
+	changed := make([]bool, len(l))
+	for _, b := range order {
+		if err := l.rekeyBucket(targets[b], b, changed, config); err != nil {
+			return nil, errors.Wrapf(err, "bucket %d", b)
+		}
+	}
+
+	perm := make([]int, len(l))
+	for i := range perm {
+		perm[i] = i
+	}
+	sort.SliceStable(perm, func(i, j int) bool {
+		return l[perm[i]].GetKey().Compare(l[perm[j]].GetKey()) < 0
+	})
+
+	sorted := make(ReorderableList, len(l))
+	var indices []int
+	for i, p := range perm {
+		sorted[i] = l[p]
+		if changed[p] {
+			indices = append(indices, i)
+		}
+	}
+	copy(l, sorted)
+
+	return indices, nil
+}
+
+// rekeyBucket gives the items at the given indices that arrive in bucket b
+// keys between the ones already there, falling back to spreading the whole
+// bucket.
+func (l ReorderableList) rekeyBucket(indices []int, b uint8, changed []bool, config *Config) error {
+	staying := ReorderableList{}
+	for _, i := range indices {
+		if l[i].GetKey().bucket == b {
+			staying = append(staying, l[i])
+		}
+	}
+	if len(staying) == len(indices) {
+		return nil
+	}
+
+	if staying.StrictlySorted() {
+		if keys, ok := l.fillRuns(indices, b, config); ok {
+			for i, k := range keys {
+				l.setKey(i, k, AuditReassignBuckets, config)
+				changed[i] = true
+			}
+			return nil
+		}
+	}
+
+	keys, err := Spread(b, len(indices), config)
+	if err != nil {
+		return err
+	}
+	for n, i := range indices {
+		if l[i].GetKey().Compare(keys[n]) != 0 {
+			l.setKey(i, keys[n], AuditReassignBuckets, config)
+			changed[i] = true
+		}
+	}
+	return nil
+}
+
+// fillRuns computes keys for each run of moved items between the surrounding
+// items that stay in the bucket. It reports false if a run doesn't fit.
+func (l ReorderableList) fillRuns(indices []int, b uint8, config *Config) (map[int]Key, bool) {
+	keys := make(map[int]Key)
+	prev := MinKey(b, config)
+	var run []int
+
+	flush := func(next Key) bool {
+		if len(run) == 0 {
+			return true
+		}
+		ks, err := BetweenN(prev, next, len(run), config)
+		if err != nil {
+			return false
+		}
+		for n, i := range run {
+			keys[i] = ks[n]
+		}
+		run = run[:0]
+		return true
+	}
+
+	for _, i := range indices {
+		k := l[i].GetKey()
+		if k.bucket != b {
+			run = append(run, i)
+			continue
+		}
+		if !flush(k) {
+			return nil, false
+		}
+		prev = k
+	}
+	if !flush(MaxKey(b, config)) {
+		return nil, false
+	}
+	return keys, true
+}
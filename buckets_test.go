@@ -0,0 +1,59 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorderableList_ReassignBuckets(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	l := ReorderableList{
+		item(1, "0|a"), item(2, "0|b"), item(3, "0|c"),
+		item(4, "1|a"), item(5, "1|b"),
+	}
+
+	// Archive items 1 and 3
+	archived := map[string]bool{"1": true, "3": true, "4": true, "5": true}
+	changed, err := l.ReassignBuckets(func(it Reorderable) uint8 {
+		if archived[it.(Identifiable).GetID()] {
+			return 1
+		}
+		return 0
+	}, DefaultConfig())
+	r.NoError(err)
+
+	ids := make([]string, len(l))
+	for i, it := range l {
+		ids[i] = it.(Identifiable).GetID()
+	}
+	a.Equal([]string{"2", "1", "3", "4", "5"}, ids)
+	a.True(l.StrictlySorted())
+
+	a.Equal("0|b", l[0].GetKey().String(), "staying items keep their keys")
+	a.Equal("1|a", l[3].GetKey().String())
+	a.Equal("1|b", l[4].GetKey().String())
+	a.Equal([]int{1, 2}, changed)
+	for _, i := range changed {
+		a.Equal(uint8(1), l[i].GetKey().Bucket())
+	}
+}
+
+func TestReorderableList_ReassignBuckets_Spread(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// No room before 1|0, so bucket 1 is spread out again
+	l := ReorderableList{item(1, "0|a"), item(2, "1|0")}
+	changed, err := l.ReassignBuckets(func(Reorderable) uint8 { return 1 }, DefaultConfig())
+	r.NoError(err)
+	a.Equal([]int{0, 1}, changed)
+	a.True(l.StrictlySorted())
+	a.Equal("1", l[0].(Identifiable).GetID())
+
+	_, err = l.ReassignBuckets(func(Reorderable) uint8 { return 10 }, DefaultConfig())
+	a.Error(err)
+}
@@ -0,0 +1,77 @@
+package lexorank
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Capacity describes how much room is left between two keys.
+type Capacity struct {
+	// Keys is the number of distinct keys of up to MaxRankLength characters
+	// strictly between the two keys. No insertion pattern can place more.
+	Keys *big.Int
+
+	// Midpoints is the number of Between calls that fit in the worst case,
+	// where every insert goes next to the previous one and halves the same
+	// gap, as when an item keeps being dropped at the top of a list.
+	Midpoints int
+
+	// Steps is the number of AppendStrategyStep appends, using StepSize,
+	// that can follow the lower key before reaching the upper one.
+	Steps *big.Int
+}
+
+// CapacityTo reports how much room is left between k and other before
+// ErrRebalanceRequired is hit, so rebalances can be scheduled ahead of time.
+// The keys must be in the same bucket with k sorting first, and the
+// configuration must have a MaxRankLength.
+func (k Key) CapacityTo(other Key, config *Config) (Capacity, error) {
+	if k.bucket != other.bucket {
+		return Capacity{}, fmt.Errorf("keys must be in the same bucket")
+	}
+	if k.Compare(other) >= 0 {
+		return Capacity{}, fmt.Errorf("left key must be less than right key")
+	}
+	if config.MaxRankLength <= 0 {
+		return Capacity{}, fmt.Errorf("capacity is unbounded without a MaxRankLength")
+	}
+
+	alphabet := config.alphabet()
+	L := max(config.MaxRankLength, len(k.rank), len(other.rank))
+	gap := new(big.Int).Sub(alphabet.scale(other.rank, L), alphabet.scale(k.rank, L))
+
+	c := Capacity{
+		Keys:      new(big.Int).Sub(gap, big.NewInt(1)),
+		Midpoints: max(gap.BitLen()-1, 0),
+		Steps:     stepCapacity(alphabet, k, other, config.StepSize),
+	}
+	return c, nil
+}
+
+// stepCapacity counts the values lo+i*step, i >= 1, whose minimal encoding
+// sorts before hi. Appends only keep their order while the encoding does not
+// grow, so values are capped at the length of lo's encoding.
+func stepCapacity(alphabet *Alphabet, lo, hi Key, step int64) *big.Int {
+	if step <= 0 {
+		return new(big.Int)
+	}
+
+	v := alphabet.value(lo.rank)
+	n := len(alphabet.encodeMinimal(v))
+
+	// The largest value that still sorts before hi at that length: a prefix of
+	// hi sorts before it, unless hi only pads it with minimum characters
+	bound := truncateRank(alphabet, hi.rank, n)
+	if !(len(hi.rank) > n && alphabet.value(hi.rank[n:]).Sign() > 0) {
+		bound.Sub(bound, big.NewInt(1))
+	}
+	if top := new(big.Int).Sub(alphabet.space(n), big.NewInt(1)); bound.Cmp(top) > 0 {
+		bound = top
+	}
+
+	steps := bound.Sub(bound, v)
+	if steps.Sign() <= 0 {
+		return new(big.Int)
+	}
+	return steps.Div(steps, big.NewInt(step))
+}
@@ -0,0 +1,60 @@
+package lexorank
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey_CapacityTo(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig().WithMaxRankLength(2)
+
+	c, err := mustKey("0|a").CapacityTo(mustKey("0|b"), config)
+	r.NoError(err)
+	a.Equal(big.NewInt(74), c.Keys, "a0 is excluded as it equals a")
+	a.Equal(6, c.Midpoints)
+	a.Equal(big.NewInt(0), c.Steps, "a+1 is b")
+
+	c, err = mustKey("0|a").CapacityTo(mustKey("0|e"), config.WithStepSize(1))
+	r.NoError(err)
+	a.Equal(big.NewInt(3), c.Steps, "b, c and d")
+
+	c, err = mustKey("0|a").CapacityTo(mustKey("0|e5"), config.WithStepSize(2))
+	r.NoError(err)
+	a.Equal(big.NewInt(2), c.Steps, "c and e, which is a prefix of e5")
+
+	_, err = mustKey("0|b").CapacityTo(mustKey("0|a"), config)
+	a.Error(err)
+	_, err = mustKey("0|a").CapacityTo(mustKey("1|b"), config)
+	a.Error(err)
+	_, err = mustKey("0|a").CapacityTo(mustKey("0|b"), config.WithMaxRankLength(0))
+	a.Error(err)
+}
+
+func TestKey_CapacityTo_Midpoints(t *testing.T) {
+	r := require.New(t)
+
+	// Repeatedly inserting right after lo succeeds exactly Midpoints times
+	config := DefaultConfig().WithMaxRankLength(4)
+	lo, hi := mustKey("0|a"), mustKey("0|b")
+
+	c, err := lo.CapacityTo(hi, config)
+	r.NoError(err)
+
+	n := 0
+	for {
+		k, err := Between(lo, hi, config)
+		if err != nil {
+			r.ErrorIs(err, ErrRebalanceRequired)
+			break
+		}
+		hi = *k
+		n++
+	}
+	r.Equal(c.Midpoints, n)
+}
@@ -0,0 +1,80 @@
+package lexorank
+
+import "github.com/pkg/errors"
+
+// Change is a single key assignment for the item at Index. ID identifies the
+// item in storage and is only required when persisting the change.
+type Change struct {
+	Index int
+	ID    string
+	Key   Key
+}
+
+// ChangeSet is a batch of key assignments, for example the result of a
+// rebalance computed by another service or a CLI run.
+type ChangeSet []Change
+
+// SetKeys assigns many keys at once. See ApplyChangeSet.
+func (l ReorderableList) SetKeys(keys map[int]Key, config *Config) error {
+	cs := make(ChangeSet, 0, len(keys))
+	for i, k := range keys {
+		cs = append(cs, Change{Index: i, Key: k})
+	}
+	return l.ApplyChangeSet(cs, config)
+}
+
+// ApplyChangeSet assigns all keys in the change set, but only after validating
+// that every index is in range, every rank respects MaxRankLength and that the
+// resulting list is strictly sorted. If any check fails no item is modified.
+func (l ReorderableList) ApplyChangeSet(cs ChangeSet, config *Config) error {
+	final := make([]Key, len(l))
+	for i := range l {
+		final[i] = l[i].GetKey()
+	}
+
+	seen := make(map[int]bool, len(cs))
+	for _, c := range cs {
+		if c.Index < 0 || c.Index >= len(l) {
+			return errors.Wrapf(ErrOutOfBounds, "change for index %d", c.Index)
+		}
+		if seen[c.Index] {
+			return errors.Errorf("duplicate change for index %d", c.Index)
+		}
+		if config.MaxRankLength > 0 && len(c.Key.rank) > config.MaxRankLength {
+			return errors.Wrapf(ErrRankTooLong, "change for index %d has length %d", c.Index, len(c.Key.rank))
+		}
+		seen[c.Index] = true
+		final[c.Index] = c.Key
+	}
+
+	for i := 1; i < len(final); i++ {
+		if final[i-1].Compare(final[i]) >= 0 {
+			return errors.Wrapf(ErrNotSorted, "index %d (%s) and %d (%s)", i-1, final[i-1], i, final[i])
+		}
+	}
+
+	for _, c := range cs {
+		l.setKey(c.Index, c.Key, AuditChangeSet, config)
+	}
+
+	return nil
+}
+
+// ChangeSet builds a change set from the current keys of the items at the given
+// indices, for example those reported by a rebalance, so that they can be
+// persisted. Items implementing Identifiable have their ID filled in.
+func (l ReorderableList) ChangeSet(indices []int) (ChangeSet, error) {
+	cs := make(ChangeSet, 0, len(indices))
+	for _, i := range indices {
+		if i < 0 || i >= len(l) {
+			return nil, errors.Wrapf(ErrOutOfBounds, "index %d", i)
+		}
+
+		c := Change{Index: i, Key: l[i].GetKey()}
+		if id, ok := l[i].(Identifiable); ok {
+			c.ID = id.GetID()
+		}
+		cs = append(cs, c)
+	}
+	return cs, nil
+}
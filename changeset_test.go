@@ -0,0 +1,59 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorderableList_SetKeys(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{
+		item(0, "1|aaa"),
+		item(1, "1|aaa"),
+		item(2, "1|aac"),
+	}
+
+	err := list.SetKeys(map[int]Key{1: mustKey("1|aab")}, DefaultConfig())
+	r.NoError(err)
+	a.Equal("1|aab", list[1].GetKey().String())
+	a.True(list.StrictlySorted())
+}
+
+func TestReorderableList_ApplyChangeSet_Validation(t *testing.T) {
+	a := assert.New(t)
+
+	newList := func() ReorderableList {
+		return ReorderableList{
+			item(0, "1|aaa"),
+			item(1, "1|aab"),
+			item(2, "1|aac"),
+		}
+	}
+
+	list := newList()
+	err := list.ApplyChangeSet(ChangeSet{{Index: 0, Key: mustKey("1|a")}, {Index: 1, Key: mustKey("1|z")}}, DefaultConfig())
+	a.ErrorIs(err, ErrNotSorted)
+	a.Equal(newList(), list, "no changes applied on failure")
+
+	err = list.ApplyChangeSet(ChangeSet{{Index: 3, Key: mustKey("1|z")}}, DefaultConfig())
+	a.ErrorIs(err, ErrOutOfBounds)
+
+	err = list.ApplyChangeSet(ChangeSet{{Index: 2, Key: mustKey("1|aacaaaa")}}, DefaultConfig())
+	a.ErrorIs(err, ErrRankTooLong)
+
+	err = list.ApplyChangeSet(ChangeSet{{Index: 2, Key: mustKey("1|b")}, {Index: 2, Key: mustKey("1|c")}}, DefaultConfig())
+	a.Error(err)
+	a.Equal(newList(), list, "no changes applied on failure")
+}
+
+func mustKey(s string) Key {
+	k, err := ParseKey(s)
+	if err != nil {
+		panic(err)
+	}
+	return *k
+}
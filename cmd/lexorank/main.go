@@ -0,0 +1,264 @@
+// Command lexorank generates and inspects lexorank keys from the shell, for
+// debugging ordering problems without writing Go.
+//
+// Usage:
+//
+//	lexorank [flags] between <a> <b>
+//	lexorank [flags] keyat <f>
+//	lexorank [flags] validate <key>...
+//	lexorank [flags] spread <n>
+//	lexorank [flags] inspect <key>...
+//
+// An empty key ("") leaves that side of between open. The flags choose the
+// configuration:
+//
+//	-preset      default, production, case-insensitive, postgres-c, sqlite or mysql
+//	-max-length  override the maximum rank length, which must be positive
+//	-bucket      bucket for keyat and spread
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/ntauth/lexorank"
+)
+
+var presets = map[string]func() *lexorank.Config{
+	"default":          lexorank.DefaultConfig,
+	"production":       lexorank.ProductionConfig,
+	"case-insensitive": lexorank.CaseInsensitiveConfig,
+	"postgres-c":       lexorank.PostgresCConfig,
+	"sqlite":           lexorank.SQLiteBinaryConfig,
+	"mysql":            lexorank.MySQLUtf8mb4Config,
+}
+
+// errUsage reports a malformed command line; run prints the usage after it.
+var errUsage = errors.New("usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line args and returns the exit status: 0 on
+// success, 1 if a key is invalid and 2 for usage errors.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("lexorank", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	preset := fs.String("preset", "default", "configuration preset")
+	maxLength := fs.Int("max-length", -1, "maximum rank length, positive (default from preset)")
+	bucket := fs.Uint("bucket", 0, "bucket for keyat and spread")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: lexorank [flags] between <a> <b> | keyat <f> | validate <key>... | spread <n> | inspect <key>...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	newConfig, ok := presets[*preset]
+	if !ok {
+		fmt.Fprintf(stderr, "lexorank: unknown preset %q\n", *preset)
+		return 2
+	}
+	config := newConfig()
+	switch {
+	case *maxLength > 0:
+		config = config.WithMaxRankLength(*maxLength)
+	case *maxLength != -1:
+		fmt.Fprintf(stderr, "lexorank: max-length %d must be positive\n", *maxLength)
+		return 2
+	}
+	if *bucket > lexorank.MaxBucket {
+		fmt.Fprintf(stderr, "lexorank: bucket %d out of range\n", *bucket)
+		return 2
+	}
+
+	c := &cli{config: config, bucket: uint8(*bucket), out: stdout}
+	cmd, rest := fs.Arg(0), fs.Args()[min(1, fs.NArg()):]
+	var err error
+	switch cmd {
+	case "between":
+		err = c.between(rest)
+	case "keyat":
+		err = c.keyAt(rest)
+	case "validate":
+		err = c.validate(rest)
+	case "spread":
+		err = c.spread(rest)
+	case "inspect":
+		err = c.inspect(rest)
+	default:
+		err = errUsage
+	}
+
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage):
+		fs.Usage()
+		return 2
+	default:
+		fmt.Fprintf(stderr, "lexorank: %v\n", err)
+		return 1
+	}
+}
+
+type cli struct {
+	config *lexorank.Config
+	bucket uint8
+	out    io.Writer
+}
+
+// parse parses a key argument, where the empty string is the zero key.
+func (c *cli) parse(raw string) (lexorank.Key, error) {
+	if raw == "" {
+		return lexorank.Key{}, nil
+	}
+	k, err := c.config.ParseKey(raw)
+	if err != nil {
+		return lexorank.Key{}, fmt.Errorf("%q: %w", raw, err)
+	}
+	return *k, nil
+}
+
+func (c *cli) between(args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	a, err := c.parse(args[0])
+	if err != nil {
+		return err
+	}
+	b, err := c.parse(args[1])
+	if err != nil {
+		return err
+	}
+
+	keys, err := lexorank.BetweenN(a, b, 1, c.config)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(c.out, keys[0])
+	return nil
+}
+
+func (c *cli) keyAt(args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	f, err := strconv.ParseFloat(args[0], 64)
+	if err != nil {
+		return errUsage
+	}
+
+	k, err := lexorank.KeyAt(c.bucket, f, c.config)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(c.out, k)
+	return nil
+}
+
+func (c *cli) spread(args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 {
+		return errUsage
+	}
+
+	keys, err := lexorank.Spread(c.bucket, n, c.config)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		fmt.Fprintln(c.out, k)
+	}
+	return nil
+}
+
+// validate prints every rule each key breaks and fails if any key is invalid.
+func (c *cli) validate(args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	invalid := 0
+	for _, raw := range args {
+		k, err := c.parse(raw)
+		if err == nil {
+			err = lexorank.ValidateProposedKey(k, nil, nil, nil, c.config)
+		}
+
+		var verr *lexorank.KeyValidationError
+		switch {
+		case err == nil:
+			fmt.Fprintf(c.out, "%s: ok\n", raw)
+		case errors.As(err, &verr):
+			invalid++
+			for _, v := range verr.Violations {
+				fmt.Fprintf(c.out, "%s: %s: %s\n", raw, v.Code, v.Message)
+			}
+		default:
+			invalid++
+			fmt.Fprintf(c.out, "%s: %v\n", raw, err)
+		}
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d keys invalid", invalid, len(args))
+	}
+	return nil
+}
+
+// inspect prints the parts of each key, its numeric value and how much room
+// is left on either side of it within its bucket.
+func (c *cli) inspect(args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	for i, raw := range args {
+		k, err := c.parse(raw)
+		if err != nil {
+			return err
+		}
+		if k.IsZero() {
+			return fmt.Errorf("empty key")
+		}
+
+		if i > 0 {
+			fmt.Fprintln(c.out)
+		}
+		fmt.Fprintf(c.out, "key:       %s\n", k)
+		fmt.Fprintf(c.out, "bucket:    %d\n", k.Bucket())
+		fmt.Fprintf(c.out, "separator: %q\n", k.Separator())
+		fmt.Fprintf(c.out, "rank:      %s (length %d)\n", k.RankString(), len(k.RankBytes()))
+		fmt.Fprintf(c.out, "value:     %s\n", k.ToBigInt())
+
+		lo, hi := lexorank.MinKey(k.Bucket(), c.config), lexorank.MaxKey(k.Bucket(), c.config)
+		c.headroom("below", lo, k)
+		c.headroom("above", k, hi)
+	}
+	return nil
+}
+
+// headroom prints the capacity between lo and hi, or why there is none.
+func (c *cli) headroom(side string, lo, hi lexorank.Key) {
+	if lo.Compare(hi) >= 0 {
+		fmt.Fprintf(c.out, "%s:     none (bucket boundary)\n", side)
+		return
+	}
+	capacity, err := lo.CapacityTo(hi, c.config)
+	if err != nil {
+		fmt.Fprintf(c.out, "%s:     %v\n", side, err)
+		return
+	}
+	fmt.Fprintf(c.out, "%s:     %s keys, %d worst-case midpoints\n", side, capacity.Keys, capacity.Midpoints)
+}
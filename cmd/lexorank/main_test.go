@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func runArgs(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	a := assert.New(t)
+
+	code, out, _ := runArgs("between", "0|a", "0|c")
+	a.Equal(0, code)
+	a.Equal("0|b\n", out)
+
+	code, out, _ = runArgs("between", "", "0|1")
+	a.Equal(0, code)
+	a.Equal("0|0U\n", out)
+
+	code, out, _ = runArgs("-bucket", "1", "spread", "3")
+	a.Equal(0, code)
+	a.Equal("1|B\n1|T\n1|f\n", out)
+
+	code, out, _ = runArgs("keyat", "0.5")
+	a.Equal(0, code)
+	a.Equal("0|UUUUUU\n", out)
+
+	code, out, _ = runArgs("-preset", "case-insensitive", "inspect", "0|hzzzzz")
+	a.Equal(0, code)
+	a.Contains(out, "bucket:    0\n")
+	a.Contains(out, "rank:      hzzzzz (length 6)\n")
+	a.Contains(out, "value:     1088391167\n")
+	a.Contains(out, "above:     1088391167 keys")
+}
+
+func TestRun_Validate(t *testing.T) {
+	a := assert.New(t)
+
+	code, out, _ := runArgs("validate", "0|a")
+	a.Equal(0, code)
+	a.Equal("0|a: ok\n", out)
+
+	code, out, errOut := runArgs("-max-length", "3", "validate", "0|a", "0|abcd", "nope")
+	a.Equal(1, code)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	a.Len(lines, 3)
+	a.Equal("0|abcd: too_long: rank length 4 exceeds 3", lines[1])
+	a.Contains(errOut, "2 of 3 keys invalid")
+}
+
+func TestRun_Usage(t *testing.T) {
+	a := assert.New(t)
+
+	for _, args := range [][]string{
+		{},
+		{"between", "0|a"},
+		{"spread", "zero"},
+		{"-preset", "nope", "spread", "1"},
+		{"-bucket", "10", "spread", "1"},
+		{"-max-length", "0", "spread", "1"},
+		{"-max-length", "-5", "spread", "1"},
+	} {
+		code, _, errOut := runArgs(args...)
+		a.Equal(2, code, args)
+		a.NotEmpty(errOut, args)
+	}
+}
@@ -0,0 +1,136 @@
+package lexorank
+
+import "slices"
+
+// Collation presets return configurations whose keys sort the same in Go, by
+// Key.Compare, and in the named database collation, so ORDER BY on the rank
+// column returns items in list order. The column itself must be declared with
+// that collation, e.g. with Config.PostgresColumn or Config.MySQLColumn.
+
+// PostgresCConfig returns a configuration for Postgres columns declared with
+// COLLATE "C", which compares bytes like Key.Compare. Every character of the
+// default alphabet is safe.
+func PostgresCConfig() *Config {
+	return DefaultConfig().WithAlphabet(Base75Alphabet)
+}
+
+// MySQLUtf8mb4Config returns a configuration for MySQL columns using the
+// default case-insensitive utf8mb4 collations, utf8mb4_0900_ai_ci and
+// utf8mb4_general_ci. They treat 'A' and 'a' as equal and sort the
+// punctuation of the default alphabet differently from its byte order, so
+// ranks use digits and lowercase letters only.
+func MySQLUtf8mb4Config() *Config {
+	return DefaultConfig().WithAlphabet(Base36Alphabet)
+}
+
+// SQLiteBinaryConfig returns a configuration for SQLite columns using the
+// default BINARY collation, which compares bytes with memcmp. Every character
+// of the default alphabet is safe. Columns declared COLLATE NOCASE need
+// MySQLUtf8mb4Config's alphabet instead, as NOCASE folds uppercase letters.
+func SQLiteBinaryConfig() *Config {
+	return DefaultConfig().WithAlphabet(Base75Alphabet)
+}
+
+// CollationMismatch is a pair of strings that a collation orders differently
+// from Key.Compare. Before sorts first in the database, or the two compare as
+// equal there if Equal is set.
+type CollationMismatch struct {
+	Before, After string
+	Equal         bool
+}
+
+// CollationReport is the result of checking a collation against a
+// configuration.
+type CollationReport struct {
+	// Mismatches holds the pairs ordered differently, in database order.
+	Mismatches []CollationMismatch
+
+	// Chars holds, in ascending order, the characters at the first position
+	// where the strings of a mismatch differ. Keys using any of them can sort
+	// differently in the database than in Go.
+	Chars []byte
+}
+
+// OK reports whether the collation agreed with Key.Compare on every pair.
+func (r CollationReport) OK() bool {
+	return len(r.Mismatches) == 0
+}
+
+// CollationProbe returns strings to check a database collation with: every
+// key of bucket 0 with a rank of one or two characters, in Key.Compare order.
+// Insert them into a column with the collation under test, read them back
+// with ORDER BY and pass the result to CheckCollation.
+func (c *Config) CollationProbe() []string {
+	chars := c.alphabet().String()
+	prefix := "0" + string(c.separator())
+
+	probe := make([]string, 0, len(chars)*(len(chars)+1))
+	for i := range len(chars) {
+		probe = append(probe, prefix+chars[i:i+1])
+		for j := range len(chars) {
+			probe = append(probe, prefix+chars[i:i+1]+chars[j:j+1])
+		}
+	}
+	return probe
+}
+
+// CheckCollation verifies that strings sorted by a database, for example the
+// CollationProbe of this configuration read back with ORDER BY, are in byte
+// order, which is the order of Key.Compare. ICU and locale collations such as
+// en_US.UTF-8 ignore or reorder punctuation and fold case, which breaks the
+// ordering silently; the report names the characters involved so the
+// alphabet can be changed before data is corrupted.
+//
+// Strings the database considers equal may come back in either order, so
+// only CheckCollationFunc can detect them reliably.
+func (c *Config) CheckCollation(sorted []string) CollationReport {
+	var r CollationReport
+
+	// Compare each string with the highest one before it, not just its
+	// predecessor, so a string sorted far too late is blamed once rather than
+	// hiding the strings after it
+	highest := -1
+	for i, s := range sorted {
+		if highest >= 0 && s < sorted[highest] {
+			r.add(CollationMismatch{Before: sorted[highest], After: s})
+			continue
+		}
+		highest = i
+	}
+	return r
+}
+
+// CheckCollationFunc is like CheckCollation but takes the collation's
+// comparison function, such as CompareString of a golang.org/x/text/collate
+// Collator, and checks it on the CollationProbe. Unlike CheckCollation it
+// also reports distinct strings that compare as equal.
+func (c *Config) CheckCollationFunc(compare func(a, b string) int) CollationReport {
+	probe := c.CollationProbe()
+	sorted := slices.Clone(probe)
+	slices.SortStableFunc(sorted, compare)
+
+	// Equal strings end up next to each other
+	r := c.CheckCollation(sorted)
+	for i := 1; i < len(sorted); i++ {
+		if compare(sorted[i-1], sorted[i]) == 0 {
+			r.add(CollationMismatch{Before: sorted[i-1], After: sorted[i], Equal: true})
+		}
+	}
+	return r
+}
+
+// add records m and the characters it blames.
+func (r *CollationReport) add(m CollationMismatch) {
+	r.Mismatches = append(r.Mismatches, m)
+	for i := range min(len(m.Before), len(m.After)) {
+		if m.Before[i] == m.After[i] {
+			continue
+		}
+		for _, ch := range []byte{m.Before[i], m.After[i]} {
+			if j, found := slices.BinarySearch(r.Chars, ch); !found {
+				r.Chars = slices.Insert(r.Chars, j, ch)
+			}
+		}
+		return
+	}
+}
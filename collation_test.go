@@ -0,0 +1,112 @@
+package lexorank
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// foldCompare approximates a case-insensitive collation such as MySQL's
+// utf8mb4_general_ci or SQLite's NOCASE by folding letters to uppercase.
+func foldCompare(a, b string) int {
+	return bytes.Compare(bytes.ToUpper([]byte(a)), bytes.ToUpper([]byte(b)))
+}
+
+func TestMySQLUtf8mb4Config_SortsCaseInsensitively(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := MySQLUtf8mb4Config()
+	r.NoError(config.Validate())
+
+	keys, err := Spread(0, 200, config)
+	r.NoError(err)
+	for i := 1; i < 200; i++ {
+		k, err := Between(keys[i-1], keys[i], config)
+		r.NoError(err)
+		keys = append(keys, *k)
+	}
+
+	strs := make([]string, len(keys))
+	for i, k := range keys {
+		strs[i] = k.String()
+	}
+	sort.Slice(strs, func(i, j int) bool { return foldCompare(strs[i], strs[j]) < 0 })
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Compare(keys[j]) < 0 })
+	for i, k := range keys {
+		a.Equal(k.String(), strs[i])
+	}
+}
+
+func TestBase75_BreaksCaseInsensitiveCollations(t *testing.T) {
+	a := assert.New(t)
+
+	// The reason the MySQL preset doesn't use the default alphabet
+	lhs, rhs := mustKey("0|Z"), mustKey("0|a")
+	a.Less(lhs.Compare(rhs), 0)
+	a.Greater(foldCompare(lhs.String(), rhs.String()), 0)
+}
+
+func TestCollationPresets_Validate(t *testing.T) {
+	a := assert.New(t)
+
+	a.NoError(PostgresCConfig().Validate())
+	a.NoError(SQLiteBinaryConfig().Validate())
+	a.Equal(Base36Alphabet, MySQLUtf8mb4Config().Alphabet)
+}
+
+func TestCheckCollation(t *testing.T) {
+	a := assert.New(t)
+
+	config := DefaultConfig()
+	probe := config.CollationProbe()
+	a.Len(probe, 75*76)
+	a.True(sort.StringsAreSorted(probe))
+	a.True(config.CheckCollation(probe).OK())
+
+	// A collation that ignores punctuation, like ICU's alternate=shifted
+	ignorePunct := func(s string) string {
+		var b []byte
+		for _, c := range []byte(s[2:]) {
+			if c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' {
+				b = append(b, c)
+			}
+		}
+		return s[:2] + string(b)
+	}
+	sorted := append([]string(nil), probe...)
+	sort.SliceStable(sorted, func(i, j int) bool { return ignorePunct(sorted[i]) < ignorePunct(sorted[j]) })
+
+	r := config.CheckCollation(sorted)
+	a.False(r.OK())
+	for _, c := range []byte(":;<=>?@[\\]^_`") {
+		a.Contains(r.Chars, c, string(c))
+	}
+}
+
+func TestCheckCollationFunc(t *testing.T) {
+	a := assert.New(t)
+
+	a.True(DefaultConfig().CheckCollationFunc(strings.Compare).OK())
+	a.True(MySQLUtf8mb4Config().CheckCollationFunc(foldCompare).OK())
+
+	// Case folding makes 'A' and 'a' equal, and moves lowercase letters
+	// before the punctuation between 'Z' and 'a'
+	r := DefaultConfig().CheckCollationFunc(foldCompare)
+	a.False(r.OK())
+	a.Contains(r.Chars, byte('a'))
+	a.Contains(r.Chars, byte('A'))
+	a.Contains(r.Chars, byte('_'))
+	a.NotContains(r.Chars, byte('0'))
+
+	var equal bool
+	for _, m := range r.Mismatches {
+		equal = equal || m.Equal
+	}
+	a.True(equal)
+}
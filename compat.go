@@ -0,0 +1,123 @@
+package lexorank
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+//go:embed compat_vectors.json
+var compatVectors []byte
+
+// compatVector is a canonical input and the output every release must keep
+// producing for it.
+type compatVector struct {
+	Op     string       `json:"op"`
+	Config compatConfig `json:"config"`
+
+	// Between
+	LHS string `json:"lhs,omitempty"`
+	RHS string `json:"rhs,omitempty"`
+
+	// Normalize
+	Count int `json:"count,omitempty"`
+
+	Want []string `json:"want"`
+}
+
+type compatConfig struct {
+	MaxRankLength       int    `json:"max_rank_length"`
+	TrimTrailingMinimum bool   `json:"trim_trailing_minimum,omitempty"`
+	GrowthJump          int    `json:"growth_jump,omitempty"`
+	Alphabet            string `json:"alphabet,omitempty"`
+}
+
+func (c compatConfig) config() (*Config, error) {
+	config := DefaultConfig().
+		WithMaxRankLength(c.MaxRankLength).
+		WithTrimTrailingMinimum(c.TrimTrailingMinimum)
+	if c.GrowthJump > 0 {
+		config = config.WithGrowthPolicy(GrowthPolicyJump).WithGrowthJump(c.GrowthJump)
+	}
+	if c.Alphabet != "" {
+		alphabet, err := NewAlphabet(c.Alphabet)
+		if err != nil {
+			return nil, err
+		}
+		config = config.WithAlphabet(alphabet)
+	}
+	return config, nil
+}
+
+// VerifyCompatibility checks that Between and Normalize still produce the
+// exact keys recorded in the canonical vectors embedded in the package.
+// Services can run it at startup to catch a library upgrade that would
+// silently change how new keys sort against stored ones. It returns a
+// *CompatibilityError listing every mismatch.
+func VerifyCompatibility() error {
+	var vectors []compatVector
+	if err := json.Unmarshal(compatVectors, &vectors); err != nil {
+		return errors.Wrap(err, "decoding compatibility vectors")
+	}
+
+	var mismatches []string
+	for i, v := range vectors {
+		got, err := v.run()
+		if err != nil {
+			mismatches = append(mismatches, fmt.Sprintf("vector %d (%s): %v", i, v.Op, err))
+			continue
+		}
+		if strings.Join(got, ",") != strings.Join(v.Want, ",") {
+			mismatches = append(mismatches, fmt.Sprintf("vector %d (%s): expected %v, got %v", i, v.Op, v.Want, got))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return &CompatibilityError{Mismatches: mismatches}
+	}
+	return nil
+}
+
+func (v compatVector) run() ([]string, error) {
+	config, err := v.Config.config()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v.Op {
+	case "between":
+		lhs, err := config.ParseKey(v.LHS)
+		if err != nil {
+			return nil, err
+		}
+		rhs, err := config.ParseKey(v.RHS)
+		if err != nil {
+			return nil, err
+		}
+		k, err := Between(*lhs, *rhs, config)
+		if err != nil {
+			return nil, err
+		}
+		return []string{k.String()}, nil
+
+	case "normalize":
+		l := make(ReorderableList, v.Count)
+		for i := range l {
+			l[i] = &Item[struct{}]{Rank: BottomOf(0)}
+		}
+		if _, err := l.Normalize(config); err != nil {
+			return nil, err
+		}
+		out := make([]string, len(l))
+		for i, it := range l {
+			out[i] = it.GetKey().String()
+		}
+		return out, nil
+
+	default:
+		return nil, errors.Errorf("unknown operation %q", v.Op)
+	}
+}
@@ -0,0 +1,34 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyCompatibility(t *testing.T) {
+	require.NoError(t, VerifyCompatibility())
+}
+
+func TestVerifyCompatibility_Mismatch(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	saved := compatVectors
+	t.Cleanup(func() { compatVectors = saved })
+
+	compatVectors = []byte(`[
+		{"op": "between", "config": {"max_rank_length": 6}, "lhs": "0|a", "rhs": "0|c", "want": ["0|b"]},
+		{"op": "between", "config": {"max_rank_length": 6}, "lhs": "0|a", "rhs": "0|b", "want": ["0|aV"]},
+		{"op": "normalize", "config": {"max_rank_length": 6}, "count": 2, "want": ["0|a"]}
+	]`)
+
+	err := VerifyCompatibility()
+	a.ErrorIs(err, ErrIncompatible)
+
+	var incompatible *CompatibilityError
+	r.True(errors.As(err, &incompatible))
+	a.Len(incompatible.Mismatches, 2)
+}
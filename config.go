@@ -1,5 +1,11 @@
 package lexorank
 
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
 // AppendStrategy defines how new keys should be generated when appending
 type AppendStrategy int
 
@@ -9,22 +15,192 @@ const (
 
 	// AppendStrategyStep uses After(step) for append and Before(step) for prepend.
 	AppendStrategyStep
+
+	// AppendStrategyAdaptive steps by StepSize like AppendStrategyStep, but
+	// never by more than half of the room left between the key and the top
+	// (or bottom) of the bucket at its current length. Appends slow down as
+	// they approach the ceiling instead of running into it, and only extend
+	// the rank once no room is left at that length.
+	AppendStrategyAdaptive
+
+	// AppendStrategyGeometric doubles the gap between the last two keys with
+	// every append, starting from StepSize and capped at MaxStepSize, so bulk
+	// imports keep early keys short while leaving ever larger gaps near the
+	// tail. Like AppendStrategyAdaptive, it never takes more than half of the
+	// room left before the top of the bucket. Without a previous key, as in
+	// SmartAppend, it steps by StepSize.
+	AppendStrategyGeometric
+)
+
+// GrowthPolicy defines how Between extends the rank length when there is no
+// room left between two keys at their current length
+type GrowthPolicy int
+
+const (
+	// GrowthPolicyMinimal adds a single digit at a time, keeping keys as short
+	// as possible.
+	GrowthPolicyMinimal GrowthPolicy = iota
+
+	// GrowthPolicyJump adds GrowthJump digits at once, producing longer keys
+	// but leaving far more room around them for subsequent inserts.
+	GrowthPolicyJump
+)
+
+// RebalanceStrategy defines what Insert, Append and Prepend do when there is
+// no room left for a new key
+type RebalanceStrategy int
+
+const (
+	// RebalanceInlineNeighbors rewrites neighbouring keys to make room, with
+	// OpenGap if MinimalRebalance is set and by shifting them one at a time
+	// otherwise, and normalises the list if that is not enough.
+	RebalanceInlineNeighbors RebalanceStrategy = iota
+
+	// RebalanceFullNormalize normalises the whole list straight away.
+	RebalanceFullNormalize
+
+	// RebalanceErrorOnly leaves every key untouched and returns
+	// ErrRebalanceRequired, so the caller can schedule the rebalance itself.
+	RebalanceErrorOnly
 )
 
 // Config holds configuration for the lexorank system
 type Config struct {
 	// AutoNormalize determines if the list should be normalized automatically
-	// if local rebalancing fails.
+	// if local rebalancing fails. When unset, Normalize refuses to run and list
+	// operations that need it return ErrNormalizationRequired.
 	AutoNormalize bool
 
+	// NormalizeThreshold, if positive, limits the automatic normalisation to
+	// lists with fewer items. Insert, Append, Prepend and Move on larger lists
+	// return ErrNormalizationRequired instead of rewriting every key inline,
+	// leaving it to a background job calling Normalize, which is not limited.
+	NormalizeThreshold int
+
 	// MaxRankLength is the maximum allowed length for ranks (default: 6)
 	MaxRankLength int
 
+	// MinRankLength, if positive, pads every generated rank shorter than it
+	// with trailing minimum characters, for fixed-width CHAR columns or
+	// aligned output in admin tools. Padding on the right doesn't change
+	// where a key sorts. It can't be combined with TrimTrailingMinimum or a
+	// Watermark.
+	MinRankLength int
+
 	// AppendStrategy determines how new keys are generated when appending
 	AppendStrategy AppendStrategy
 
-	// StepSize is the distance to use when using AppendStrategyStep
+	// StepSize is the distance to use when using AppendStrategyStep, and the
+	// largest one AppendStrategyAdaptive takes
 	StepSize int64
+
+	// MaxStepSize caps the step AppendStrategyGeometric grows to. Zero means
+	// no cap other than the room left in the bucket.
+	MaxStepSize int64
+
+	// PrependStrategy, if set, determines how new keys are generated when
+	// prepending, so both ends of a list can be tuned independently. Nil means
+	// prepends follow AppendStrategy.
+	PrependStrategy *AppendStrategy
+
+	// PrependStepSize is the distance to use when prepending with
+	// AppendStrategyStep. Zero means StepSize.
+	PrependStepSize int64
+
+	// TrimTrailingMinimum makes Between strip trailing minimum characters from
+	// its results, e.g. "a0" becomes "a". The shorter key sorts in the same
+	// place but leaves more room for future inserts around it.
+	TrimTrailingMinimum bool
+
+	// GrowthPolicy determines how Between extends precision when a gap is
+	// exhausted
+	GrowthPolicy GrowthPolicy
+
+	// GrowthJump is the number of digits to add when using GrowthPolicyJump
+	GrowthJump int
+
+	// StrictBuckets makes list operations check that every key belongs to the
+	// same bucket before doing anything, returning a *MixedBucketsError if not.
+	StrictBuckets bool
+
+	// MaxListSize, if positive, makes Insert, Append, Prepend, Move, OpenGap
+	// and Normalize refuse lists with more items with a *ListTooLargeError,
+	// so a huge list isn't accidentally loaded and rewritten in one request.
+	MaxListSize int
+
+	// MaxKeysRewritten caps how many existing keys a single Insert, Append or
+	// Prepend may rewrite. Operations that would exceed it fail with a
+	// *RewriteLimitError instead. Zero means no limit.
+	MaxKeysRewritten int
+
+	// MaxRebalanceWrites caps how many neighbouring keys a local rebalance may
+	// shift before giving up with a *RebalanceBudgetError, instead of
+	// cascading through the list and falling back to Normalize. Zero means no
+	// limit.
+	MaxRebalanceWrites int
+
+	// NormalizeMinGap is the smallest distance Normalize leaves between
+	// adjacent keys, counted in steps of the last digit at MaxRankLength, so
+	// that AppendStrategyStep appends right after a normalize don't run into
+	// the next key. When set, keys are spread with exact integer steps rather
+	// than by KeyAt. It is ignored if the list is too long for that much room.
+	NormalizeMinGap int64
+
+	// RebalanceStrategy determines what Insert, Append and Prepend do when a
+	// gap is exhausted
+	RebalanceStrategy RebalanceStrategy
+
+	// MinimalRebalance makes Insert, Append and Prepend make room with
+	// OpenGap, which rewrites the smallest run of keys next to the position,
+	// instead of shifting neighbours one at a time.
+	MinimalRebalance bool
+
+	// TieBreaker orders items with equal keys, giving lists that tolerate
+	// transient duplicates a total, deterministic order. It is used by
+	// SortedBy, Sort and Repair. Typically it compares item IDs.
+	TieBreaker func(a, b Reorderable) int
+
+	// Separator is the character placed between the bucket and the rank of
+	// generated keys, e.g. ':' for "0:aaaaaa". Zero means DefaultSeparator.
+	Separator byte
+
+	// Alphabet is the set of characters ranks are built from. Nil means
+	// Base75Alphabet. Use Base36Alphabet for storage with case-insensitive
+	// collations.
+	Alphabet *Alphabet
+
+	// Watermark, if set, is appended to every key generated by Between as a
+	// reserved lowest-order digit, identifying the code path or version that
+	// produced it. It must be part of the alphabet and takes up one digit of
+	// MaxRankLength. See ReorderableList.ScanWatermarks.
+	Watermark byte
+
+	// WarnRankLength, if positive, is a soft limit below MaxRankLength. Every
+	// key generated by Between or an append strategy whose rank is at least
+	// this long is passed to OnLongKey, giving operators lead time to schedule
+	// a normalize before inserts start failing with ErrRebalanceRequired.
+	WarnRankLength int
+
+	// OnLongKey is called with generated keys that reach WarnRankLength. It
+	// must not modify the list being operated on, and may be called more than
+	// once for the same key.
+	OnLongKey func(Key)
+
+	// Metrics, if set, receives counters and observations from key generation
+	// and list operations. See Metrics.
+	Metrics Metrics
+
+	// Logger, if set, is warned about rebalances that reach MaxRankLength,
+	// fall back to Normalize, and lists whose keys are not strictly ordered.
+	Logger Logger
+
+	// Audit, if set, records every existing key rewritten by list
+	// operations. See AuditLog.
+	Audit *AuditLog
+
+	// explain, if set, records the decisions taken by list operations. See
+	// ReorderableList.InsertExplain.
+	explain *Explanation
 }
 
 // DefaultConfig returns the default configuration
@@ -34,9 +210,20 @@ func DefaultConfig() *Config {
 		MaxRankLength:  6,
 		AppendStrategy: AppendStrategyDefault,
 		StepSize:       1,
+		GrowthPolicy:   GrowthPolicyMinimal,
+		GrowthJump:     2,
 	}
 }
 
+// WithAutoNormalize enables or disables automatic normalisation, limited to
+// lists with fewer than threshold items if threshold is positive
+func (c *Config) WithAutoNormalize(enabled bool, threshold int) *Config {
+	newConfig := *c
+	newConfig.AutoNormalize = enabled
+	newConfig.NormalizeThreshold = threshold
+	return &newConfig
+}
+
 // WithMaxRankLength sets the maximum rank length
 func (c *Config) WithMaxRankLength(length int) *Config {
 	newConfig := *c
@@ -44,6 +231,13 @@ func (c *Config) WithMaxRankLength(length int) *Config {
 	return &newConfig
 }
 
+// WithMinRankLength sets the length generated ranks are padded to
+func (c *Config) WithMinRankLength(length int) *Config {
+	newConfig := *c
+	newConfig.MinRankLength = length
+	return &newConfig
+}
+
 // WithAppendStrategy sets the append strategy
 func (c *Config) WithAppendStrategy(strategy AppendStrategy) *Config {
 	newConfig := *c
@@ -58,6 +252,268 @@ func (c *Config) WithStepSize(step int64) *Config {
 	return &newConfig
 }
 
+// WithMaxStepSize sets the largest step AppendStrategyGeometric grows to
+func (c *Config) WithMaxStepSize(step int64) *Config {
+	newConfig := *c
+	newConfig.MaxStepSize = step
+	return &newConfig
+}
+
+// WithPrependStrategy sets the strategy used for prepends independently of
+// the append strategy
+func (c *Config) WithPrependStrategy(strategy AppendStrategy) *Config {
+	newConfig := *c
+	newConfig.PrependStrategy = &strategy
+	return &newConfig
+}
+
+// WithPrependStepSize sets the step size for step-based prepends
+func (c *Config) WithPrependStepSize(step int64) *Config {
+	newConfig := *c
+	newConfig.PrependStepSize = step
+	return &newConfig
+}
+
+// WithTrimTrailingMinimum enables or disables trimming of trailing minimum
+// characters from Between results
+func (c *Config) WithTrimTrailingMinimum(trim bool) *Config {
+	newConfig := *c
+	newConfig.TrimTrailingMinimum = trim
+	return &newConfig
+}
+
+// WithGrowthPolicy sets the precision growth policy
+func (c *Config) WithGrowthPolicy(policy GrowthPolicy) *Config {
+	newConfig := *c
+	newConfig.GrowthPolicy = policy
+	return &newConfig
+}
+
+// WithGrowthJump sets the number of digits added by GrowthPolicyJump
+func (c *Config) WithGrowthJump(digits int) *Config {
+	newConfig := *c
+	newConfig.GrowthJump = digits
+	return &newConfig
+}
+
+// WithStrictBuckets enables or disables bucket homogeneity checks on list
+// operations
+func (c *Config) WithStrictBuckets(strict bool) *Config {
+	newConfig := *c
+	newConfig.StrictBuckets = strict
+	return &newConfig
+}
+
+// WithMaxListSize sets the largest list in-memory operations accept
+func (c *Config) WithMaxListSize(n int) *Config {
+	newConfig := *c
+	newConfig.MaxListSize = n
+	return &newConfig
+}
+
+// WithMaxKeysRewritten sets the maximum number of keys a single operation may
+// rewrite
+func (c *Config) WithMaxKeysRewritten(n int) *Config {
+	newConfig := *c
+	newConfig.MaxKeysRewritten = n
+	return &newConfig
+}
+
+// WithMaxRebalanceWrites sets the maximum number of keys a local rebalance may
+// shift
+func (c *Config) WithMaxRebalanceWrites(n int) *Config {
+	newConfig := *c
+	newConfig.MaxRebalanceWrites = n
+	return &newConfig
+}
+
+// WithNormalizeMinGap sets the smallest distance Normalize leaves between
+// adjacent keys
+func (c *Config) WithNormalizeMinGap(gap int64) *Config {
+	newConfig := *c
+	newConfig.NormalizeMinGap = gap
+	return &newConfig
+}
+
+// WithRebalanceStrategy sets what happens when a gap is exhausted
+func (c *Config) WithRebalanceStrategy(strategy RebalanceStrategy) *Config {
+	newConfig := *c
+	newConfig.RebalanceStrategy = strategy
+	return &newConfig
+}
+
+// WithMinimalRebalance enables or disables making room with OpenGap
+func (c *Config) WithMinimalRebalance(minimal bool) *Config {
+	newConfig := *c
+	newConfig.MinimalRebalance = minimal
+	return &newConfig
+}
+
+// WithTieBreaker sets the function ordering items with equal keys
+func (c *Config) WithTieBreaker(tb func(a, b Reorderable) int) *Config {
+	newConfig := *c
+	newConfig.TieBreaker = tb
+	return &newConfig
+}
+
+// WithSeparator sets the character placed between the bucket and the rank of
+// generated keys
+func (c *Config) WithSeparator(sep byte) *Config {
+	newConfig := *c
+	newConfig.Separator = sep
+	return &newConfig
+}
+
+// WithAlphabet sets the alphabet ranks are built from
+func (c *Config) WithAlphabet(alphabet *Alphabet) *Config {
+	newConfig := *c
+	newConfig.Alphabet = alphabet
+	return &newConfig
+}
+
+// WithWatermark sets the marker digit appended to keys generated by Between.
+// Zero disables watermarking.
+func (c *Config) WithWatermark(marker byte) *Config {
+	newConfig := *c
+	newConfig.Watermark = marker
+	return &newConfig
+}
+
+// WithWarnRankLength sets the soft rank length limit and the function notified
+// of keys that reach it
+func (c *Config) WithWarnRankLength(length int, onLongKey func(Key)) *Config {
+	newConfig := *c
+	newConfig.WarnRankLength = length
+	newConfig.OnLongKey = onLongKey
+	return &newConfig
+}
+
+// WithMetrics sets the receiver of counters and observations
+func (c *Config) WithMetrics(m Metrics) *Config {
+	newConfig := *c
+	newConfig.Metrics = m
+	return &newConfig
+}
+
+// WithLogger sets the logger warned about notable events
+func (c *Config) WithLogger(logger Logger) *Config {
+	newConfig := *c
+	newConfig.Logger = logger
+	return &newConfig
+}
+
+// WithAudit sets the log recording the keys rewritten by list operations
+func (c *Config) WithAudit(log *AuditLog) *Config {
+	newConfig := *c
+	newConfig.Audit = log
+	return &newConfig
+}
+
+// ParseKey parses a key and checks that it uses the configured separator and
+// alphabet. The returned key uses the configured alphabet for arithmetic.
+func (c *Config) ParseKey(s string) (*Key, error) {
+	k, err := ParseKey(s)
+	if err != nil {
+		return nil, err
+	}
+	if k.Separator() != c.separator() {
+		return nil, errors.Errorf("invalid separator %q in key %q (expected %q)", k.Separator(), s, c.separator())
+	}
+	if !c.alphabet().Contains(k.rank) {
+		return nil, errors.Errorf("key %q uses characters outside the alphabet %q", s, c.alphabet())
+	}
+	*k = c.adopt(*k)
+	return k, nil
+}
+
+// NewKey creates a key from a bucket and rank, checking the rank against the
+// configured alphabet. The key is formatted with the configured separator.
+func (c *Config) NewKey(bucket uint8, rank string) (*Key, error) {
+	if bucket > MaxBucket {
+		return nil, errors.Errorf("invalid bucket: %d (maximum %d)", bucket, MaxBucket)
+	}
+	if !c.alphabet().Contains([]byte(rank)) {
+		return nil, errors.Errorf("rank %q uses characters outside the alphabet %q", rank, c.alphabet())
+	}
+	k, err := parseRaw(bucket, []byte(rank))
+	if err != nil {
+		return nil, err
+	}
+	*k = c.adopt(*k)
+	return k, nil
+}
+
+// alphabet returns the configured alphabet, defaulting to Base75Alphabet.
+func (c *Config) alphabet() *Alphabet {
+	if c.Alphabet == nil {
+		return Base75Alphabet
+	}
+	return c.Alphabet
+}
+
+// adopt returns k formatted with the configured separator and using the
+// configured alphabet for arithmetic.
+func (c *Config) adopt(k Key) Key {
+	return k.WithSeparator(c.separator()).withAlphabet(c.alphabet())
+}
+
+// separator returns the configured separator, defaulting to DefaultSeparator.
+func (c *Config) separator() byte {
+	if c.Separator == 0 {
+		return DefaultSeparator
+	}
+	return c.Separator
+}
+
+// prependStrategy returns the strategy for prepends, defaulting to
+// AppendStrategy.
+func (c *Config) prependStrategy() AppendStrategy {
+	if c.PrependStrategy == nil {
+		return c.AppendStrategy
+	}
+	return *c.PrependStrategy
+}
+
+// prependStepSize returns the step size for prepends, defaulting to StepSize.
+func (c *Config) prependStepSize() int64 {
+	if c.PrependStepSize == 0 {
+		return c.StepSize
+	}
+	return c.PrependStepSize
+}
+
+// padRank pads the rank of k to MinRankLength with the minimum character.
+func (c *Config) padRank(k *Key) {
+	if k == nil || len(k.rank) >= c.MinRankLength {
+		return
+	}
+	rank := append([]byte(nil), k.rank...)
+	for len(rank) < c.MinRankLength {
+		rank = append(rank, c.alphabet().Min())
+	}
+	*k = c.adopt(*makeKey(k.bucket, rank))
+}
+
+// checkRankLength reports the rank length of a generated key to Metrics and
+// passes k to OnLongKey if it reaches WarnRankLength.
+func (c *Config) checkRankLength(k *Key) {
+	if k != nil {
+		c.metrics().RankLength(len(k.rank))
+	}
+	if c.WarnRankLength > 0 && c.OnLongKey != nil && k != nil && len(k.rank) >= c.WarnRankLength {
+		c.OnLongKey(*k)
+	}
+}
+
+// growthDigits returns how many digits Between should add when a gap at the
+// current length is exhausted.
+func (c *Config) growthDigits() int {
+	if c.GrowthPolicy == GrowthPolicyJump && c.GrowthJump > 1 {
+		return c.GrowthJump
+	}
+	return 1
+}
+
 // ProductionConfig returns a configuration optimized for production with
 // longer ranks and step-based strategies.
 func ProductionConfig() *Config {
@@ -66,5 +522,136 @@ func ProductionConfig() *Config {
 		MaxRankLength:  128,   // Allow for longer ranks
 		AppendStrategy: AppendStrategyStep,
 		StepSize:       1000, // Every new key is 1000 steps away from the previous key
+		GrowthPolicy:   GrowthPolicyMinimal,
+		GrowthJump:     2,
+	}
+}
+
+// CaseInsensitiveConfig returns the default configuration with ranks built
+// from Base36Alphabet, for databases whose collation compares letters without
+// regard to case, such as MySQL's utf8mb4_general_ci or the SQL Server
+// defaults. With the default alphabet those collations sort 'A' and 'a' as
+// equal and silently break the ordering.
+func CaseInsensitiveConfig() *Config {
+	return DefaultConfig().WithAlphabet(Base36Alphabet)
+}
+
+// Validate checks that the configuration can produce valid keys, so mistakes
+// surface when it is built rather than as corrupt keys from Between or KeyAt
+// later on. It returns a *ConfigError listing every problem, or nil.
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.MaxRankLength <= 0 {
+		add("MaxRankLength must be positive, got %d", c.MaxRankLength)
+	}
+
+	if c.MinRankLength < 0 {
+		add("MinRankLength must not be negative, got %d", c.MinRankLength)
+	} else if c.MinRankLength > 0 {
+		if c.MaxRankLength > 0 && c.MinRankLength > c.MaxRankLength {
+			add("MinRankLength %d exceeds MaxRankLength %d", c.MinRankLength, c.MaxRankLength)
+		}
+		if c.TrimTrailingMinimum {
+			add("MinRankLength can't be combined with TrimTrailingMinimum, which removes the padding")
+		}
+		if c.Watermark != 0 {
+			add("MinRankLength can't be combined with a Watermark, which must stay the last digit")
+		}
+	}
+
+	switch c.AppendStrategy {
+	case AppendStrategyDefault, AppendStrategyAdaptive:
+	case AppendStrategyStep, AppendStrategyGeometric:
+		if c.StepSize <= 0 {
+			add("StepSize must be positive with a step-based AppendStrategy, got %d", c.StepSize)
+		}
+	default:
+		add("unknown AppendStrategy %d", c.AppendStrategy)
+	}
+
+	if c.PrependStrategy != nil {
+		switch *c.PrependStrategy {
+		case AppendStrategyDefault, AppendStrategyAdaptive:
+		case AppendStrategyStep, AppendStrategyGeometric:
+			if c.prependStepSize() <= 0 {
+				add("PrependStepSize must be positive with a step-based PrependStrategy, got %d", c.prependStepSize())
+			}
+		default:
+			add("unknown PrependStrategy %d", *c.PrependStrategy)
+		}
+	}
+	if c.PrependStepSize < 0 {
+		add("PrependStepSize must not be negative, got %d", c.PrependStepSize)
+	}
+
+	switch c.GrowthPolicy {
+	case GrowthPolicyMinimal:
+	case GrowthPolicyJump:
+		if c.GrowthJump <= 0 {
+			add("GrowthJump must be positive with GrowthPolicyJump, got %d", c.GrowthJump)
+		}
+	default:
+		add("unknown GrowthPolicy %d", c.GrowthPolicy)
+	}
+
+	switch c.RebalanceStrategy {
+	case RebalanceInlineNeighbors, RebalanceFullNormalize, RebalanceErrorOnly:
+	default:
+		add("unknown RebalanceStrategy %d", c.RebalanceStrategy)
+	}
+
+	if c.NormalizeThreshold < 0 {
+		add("NormalizeThreshold must not be negative, got %d", c.NormalizeThreshold)
+	}
+	if c.MaxListSize < 0 {
+		add("MaxListSize must not be negative, got %d", c.MaxListSize)
+	}
+	if c.MaxKeysRewritten < 0 {
+		add("MaxKeysRewritten must not be negative, got %d", c.MaxKeysRewritten)
+	}
+	if c.MaxRebalanceWrites < 0 {
+		add("MaxRebalanceWrites must not be negative, got %d", c.MaxRebalanceWrites)
+	}
+	if c.MaxStepSize < 0 {
+		add("MaxStepSize must not be negative, got %d", c.MaxStepSize)
+	}
+	if c.NormalizeMinGap < 0 {
+		add("NormalizeMinGap must not be negative, got %d", c.NormalizeMinGap)
+	}
+
+	alphabet := c.alphabet()
+	if c.Alphabet != nil {
+		if _, err := NewAlphabet(c.Alphabet.String()); err != nil {
+			add("Alphabet is invalid: %v; build it with NewAlphabet", err)
+			alphabet = nil
+		}
+	}
+
+	if c.Separator != 0 && (c.Separator < '!' || c.Separator > '~' || (c.Separator >= '0' && c.Separator <= '9')) {
+		add("Separator must be a printable ASCII character other than a digit, got %q", c.Separator)
+	}
+
+	if c.Watermark != 0 {
+		if alphabet != nil && !alphabet.Contains([]byte{c.Watermark}) {
+			add("Watermark %q is not part of the alphabet", c.Watermark)
+		}
+		if c.MaxRankLength > 0 && c.MaxRankLength < 2 {
+			add("MaxRankLength must be at least 2 with a Watermark, got %d", c.MaxRankLength)
+		}
+	}
+
+	if c.WarnRankLength < 0 {
+		add("WarnRankLength must not be negative, got %d", c.WarnRankLength)
+	} else if c.WarnRankLength > c.MaxRankLength && c.MaxRankLength > 0 {
+		add("WarnRankLength %d exceeds MaxRankLength %d and would never trigger", c.WarnRankLength, c.MaxRankLength)
+	}
+
+	if len(problems) > 0 {
+		return &ConfigError{Problems: problems}
 	}
+	return nil
 }
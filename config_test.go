@@ -0,0 +1,52 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	a := assert.New(t)
+
+	a.NoError(DefaultConfig().Validate())
+	a.NoError(DefaultConfig().WithAlphabet(Base36Alphabet).WithSeparator(':').WithWatermark('z').Validate())
+
+	tests := []struct {
+		name   string
+		config *Config
+		want   string
+	}{
+		{"zero length", DefaultConfig().WithMaxRankLength(0), "MaxRankLength must be positive"},
+		{"step size", DefaultConfig().WithAppendStrategy(AppendStrategyStep).WithStepSize(0), "StepSize must be positive"},
+		{"strategy", DefaultConfig().WithAppendStrategy(AppendStrategy(9)), "unknown AppendStrategy"},
+		{"growth jump", DefaultConfig().WithGrowthPolicy(GrowthPolicyJump).WithGrowthJump(0), "GrowthJump must be positive"},
+		{"alphabet", DefaultConfig().WithAlphabet(&Alphabet{}), "Alphabet is invalid"},
+		{"separator", DefaultConfig().WithSeparator('5'), "Separator must be"},
+		{"watermark", DefaultConfig().WithAlphabet(Base36Alphabet).WithWatermark('A'), "not part of the alphabet"},
+		{"warn length", DefaultConfig().WithWarnRankLength(8, nil), "would never trigger"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+
+			err := tt.config.Validate()
+			r.ErrorIs(err, ErrInvalidConfig)
+			r.ErrorContains(err, tt.want)
+		})
+	}
+
+	err := DefaultConfig().WithMaxRankLength(-1).WithNormalizeMinGap(-1).Validate()
+	var ce *ConfigError
+	a.ErrorAs(err, &ce)
+	a.Len(ce.Problems, 2)
+}
+
+func TestConfig_Validate_PrependStrategy(t *testing.T) {
+	a := assert.New(t)
+
+	a.NoError(DefaultConfig().WithPrependStrategy(AppendStrategyStep).Validate())
+	a.ErrorContains(DefaultConfig().WithPrependStrategy(AppendStrategyStep).WithStepSize(0).Validate(), "PrependStepSize must be positive")
+	a.ErrorContains(DefaultConfig().WithPrependStrategy(AppendStrategy(9)).Validate(), "unknown PrependStrategy")
+}
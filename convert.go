@@ -0,0 +1,77 @@
+package lexorank
+
+import "github.com/pkg/errors"
+
+// ConvertAlphabet re-encodes every key of the list from the alphabet of one
+// configuration to the alphabet and separator of another, for example when
+// moving existing rows to CaseInsensitiveConfig. Each rank keeps its position
+// as a fraction of the key space, so the order and the relative spacing of the
+// items survive the conversion. Buckets are kept as they are.
+//
+// The list is not modified. The returned change set holds a change for every
+// item and is meant to be persisted and then applied with ApplyChangeSet under
+// the target configuration. If the converted keys would exceed the target's
+// MaxRankLength, ErrRebalanceRequired is returned and the list should be
+// normalized under the target configuration instead.
+func (l ReorderableList) ConvertAlphabet(from, to *Config) (ChangeSet, error) {
+	if !l.StrictlySorted() {
+		return nil, ErrNotSorted
+	}
+
+	src, dst := from.alphabet(), to.alphabet()
+	for i, it := range l {
+		if !src.Contains(it.GetKey().rank) {
+			return nil, errors.Errorf("key %s of item %d uses characters outside the alphabet %q", it.GetKey(), i, src)
+		}
+	}
+
+	for extra := 0; ; extra++ {
+		keys := make(Keys, len(l))
+		for i, it := range l {
+			k := it.GetKey()
+			n := convertedLength(src, dst, len(k.rank)) + extra
+			if to.MaxRankLength > 0 && n > to.MaxRankLength {
+				return nil, errors.Wrapf(ErrRebalanceRequired, "key %s needs %d characters in the target alphabet", k, n)
+			}
+
+			v := src.value(k.rank)
+			v.Mul(v, dst.space(n))
+			v.Quo(v, src.space(len(k.rank)))
+			keys[i] = to.adopt(*makeKey(k.bucket, dst.encode(v, n)))
+		}
+
+		if !keys.strictlySorted() {
+			// Neighbours that were close together rounded to the same key
+			continue
+		}
+
+		cs := make(ChangeSet, len(l))
+		for i, it := range l {
+			cs[i] = Change{Index: i, Key: keys[i]}
+			if id, ok := it.(Identifiable); ok {
+				cs[i].ID = id.GetID()
+			}
+		}
+		return cs, nil
+	}
+}
+
+// convertedLength returns the shortest rank length in the dst alphabet that
+// has at least as many values as a rank of the given length in src.
+func convertedLength(src, dst *Alphabet, length int) int {
+	need := src.space(length)
+	n := 1
+	for dst.space(n).Cmp(need) < 0 {
+		n++
+	}
+	return n
+}
+
+func (ks Keys) strictlySorted() bool {
+	for i := 1; i < len(ks); i++ {
+		if ks[i-1].Compare(ks[i]) >= 0 {
+			return false
+		}
+	}
+	return true
+}
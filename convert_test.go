@@ -0,0 +1,52 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorderableList_ConvertAlphabet(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{item(0, "0|0"), item(1, "0|A"), item(2, "0|U"), item(3, "0|UV"), item(4, "1|z")}
+	before := list.Keys()
+
+	to := CaseInsensitiveConfig().WithSeparator(':')
+	cs, err := list.ConvertAlphabet(DefaultConfig(), to)
+	r.NoError(err)
+	a.Equal(before, list.Keys(), "the list is not modified")
+	r.Len(cs, len(list))
+	a.Equal("3", cs[3].ID)
+
+	r.NoError(list.ApplyChangeSet(cs, to))
+	a.True(list.StrictlySorted())
+	for _, it := range list {
+		k := it.GetKey()
+		a.True(Base36Alphabet.Contains(k.rank), k.String())
+		a.Equal(byte(':'), k.Separator())
+	}
+	a.Equal("0:00", list[0].GetKey().String())
+	a.Equal(uint8(1), list[4].GetKey().Bucket())
+
+	// Spacing is preserved: U sits 37/75 of the way through the key space
+	a.Equal("0:hr", list[2].GetKey().String())
+}
+
+func TestReorderableList_ConvertAlphabet_Errors(t *testing.T) {
+	a := assert.New(t)
+
+	unsorted := ReorderableList{item(0, "0|b"), item(1, "0|a")}
+	_, err := unsorted.ConvertAlphabet(DefaultConfig(), CaseInsensitiveConfig())
+	a.ErrorIs(err, ErrNotSorted)
+
+	long := ReorderableList{item(0, "0|aaaaa"), item(1, "0|aaaab")}
+	_, err = long.ConvertAlphabet(DefaultConfig(), CaseInsensitiveConfig().WithMaxRankLength(5))
+	a.ErrorIs(err, ErrRebalanceRequired)
+
+	upper := ReorderableList{item(0, "0|A")}
+	_, err = upper.ConvertAlphabet(CaseInsensitiveConfig(), DefaultConfig())
+	a.Error(err)
+}
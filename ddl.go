@@ -0,0 +1,140 @@
+package lexorank
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RecommendedColumnSize returns the maximum serialised length of a key under
+// this configuration: the bucket, the separator and MaxRankLength rank
+// characters. It returns 0 if MaxRankLength is unlimited.
+//
+// Keys produced by AppendStrategyStep are not bounded by MaxRankLength, so
+// lists relying on it should leave extra room or use an unbounded column.
+func (c *Config) RecommendedColumnSize() int {
+	if c.MaxRankLength <= 0 {
+		return 0
+	}
+	return 2 + c.MaxRankLength
+}
+
+// PostgresColumn returns a Postgres column definition for storing keys, e.g.
+// `"rank" VARCHAR(8) COLLATE "C" NOT NULL`. The "C" collation makes the
+// database sort keys byte by byte, like Key.Compare.
+func (c *Config) PostgresColumn(name string) string {
+	typ := "TEXT"
+	if n := c.RecommendedColumnSize(); n > 0 {
+		typ = fmt.Sprintf("VARCHAR(%d)", n)
+	}
+	return fmt.Sprintf(`%s %s COLLATE "C" NOT NULL`, quotePostgres(name), typ)
+}
+
+// MySQLColumn returns a MySQL column definition for storing keys, e.g.
+// "`rank` VARCHAR(8) CHARACTER SET ascii COLLATE ascii_bin NOT NULL". The
+// binary collation is required because the default case-insensitive ones sort
+// 'A' and 'a' as equal.
+func (c *Config) MySQLColumn(name string) string {
+	typ := "TEXT"
+	if n := c.RecommendedColumnSize(); n > 0 {
+		typ = fmt.Sprintf("VARCHAR(%d)", n)
+	}
+	return fmt.Sprintf("%s %s CHARACTER SET ascii COLLATE ascii_bin NOT NULL", quoteMySQL(name), typ)
+}
+
+// PostgresCheck returns a CHECK constraint that rejects values which are not
+// keys of this configuration, e.g. `CHECK ("rank" ~ '^[0-9]\|[0-z]{1,6}$')`,
+// so rows written by other services or by hand cannot break the ordering.
+func (c *Config) PostgresCheck(column string) string {
+	var re strings.Builder
+	fmt.Fprintf(&re, `^[0-%d]%s[%s]`, MaxBucket, regexpEscape(c.separator()), regexpClass(c.alphabet()))
+
+	// Postgres rejects bounds above 255
+	lo := max(c.MinRankLength, 1)
+	if c.MaxRankLength > 0 && c.MaxRankLength <= 255 {
+		fmt.Fprintf(&re, "{%d,%d}$", lo, max(c.MaxRankLength, lo))
+	} else {
+		fmt.Fprintf(&re, "{%d,}$", lo)
+	}
+
+	return fmt.Sprintf("CHECK (%s ~ '%s')", quotePostgres(column), strings.ReplaceAll(re.String(), "'", "''"))
+}
+
+// PostgresIndex returns a CREATE INDEX statement for ordering table by column,
+// optionally prefixed by scope columns such as the id of the list an item
+// belongs to. The index is deliberately not unique: Postgres checks unique
+// indexes row by row, so a rebalance rewriting neighbouring keys in a single
+// UPDATE could fail on keys it is about to change.
+func (c *Config) PostgresIndex(table, column string, scope ...string) string {
+	columns := make([]string, 0, len(scope)+1)
+	for _, s := range scope {
+		columns = append(columns, quotePostgres(s))
+	}
+	columns = append(columns, quotePostgres(column))
+
+	name := table[strings.LastIndexByte(table, '.')+1:] + "_" + column + "_idx"
+	return fmt.Sprintf("CREATE INDEX %s ON %s (%s)", quotePostgres(name), quotePostgresTable(table), strings.Join(columns, ", "))
+}
+
+// PostgresDDL returns the statements adding a rank column to an existing
+// table: the column from PostgresColumn, the constraint from PostgresCheck and
+// the index from PostgresIndex, each terminated by a semicolon. Table may be
+// schema-qualified. The column is NOT NULL, so on a table with rows add it
+// without that clause first and assign keys, e.g. with MigratePositions.
+func (c *Config) PostgresDDL(table, column string, scope ...string) string {
+	t := quotePostgresTable(table)
+	check := quotePostgres(table[strings.LastIndexByte(table, '.')+1:] + "_" + column + "_check")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "ALTER TABLE %s ADD COLUMN %s;\n", t, c.PostgresColumn(column))
+	fmt.Fprintf(&b, "ALTER TABLE %s ADD CONSTRAINT %s %s;\n", t, check, c.PostgresCheck(column))
+	fmt.Fprintf(&b, "%s;\n", c.PostgresIndex(table, column, scope...))
+	return b.String()
+}
+
+// regexpClass returns the contents of a bracket expression matching every
+// character of a, with runs of consecutive characters written as ranges.
+func regexpClass(a *Alphabet) string {
+	chars := a.String()
+	var b strings.Builder
+	for i := 0; i < len(chars); {
+		j := i
+		for j+1 < len(chars) && chars[j+1] == chars[j]+1 {
+			j++
+		}
+		b.WriteString(regexpEscape(chars[i]))
+		if j > i+1 {
+			b.WriteByte('-')
+		}
+		if j > i {
+			b.WriteString(regexpEscape(chars[j]))
+		}
+		i = j + 1
+	}
+	return b.String()
+}
+
+// regexpEscape escapes c for a Postgres advanced regular expression, inside
+// or outside a bracket expression.
+func regexpEscape(c byte) string {
+	if c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' {
+		return string(c)
+	}
+	return `\` + string(c)
+}
+
+// quotePostgresTable quotes each part of a possibly schema-qualified name.
+func quotePostgresTable(name string) string {
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = quotePostgres(p)
+	}
+	return strings.Join(parts, ".")
+}
+
+func quotePostgres(ident string) string {
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
+
+func quoteMySQL(ident string) string {
+	return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
+}
@@ -0,0 +1,87 @@
+package lexorank
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_RecommendedColumnSize(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(8, DefaultConfig().RecommendedColumnSize())
+	a.Equal(130, ProductionConfig().RecommendedColumnSize())
+	a.Equal(0, DefaultConfig().WithMaxRankLength(0).RecommendedColumnSize())
+
+	// Every key the config can produce through Between fits
+	k := MaxKey(0, DefaultConfig())
+	a.Len(k.String(), DefaultConfig().RecommendedColumnSize())
+}
+
+func TestConfig_ColumnDDL(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(`"rank" VARCHAR(8) COLLATE "C" NOT NULL`, DefaultConfig().PostgresColumn("rank"))
+	a.Equal(`"we""ird" TEXT COLLATE "C" NOT NULL`, DefaultConfig().WithMaxRankLength(0).PostgresColumn(`we"ird`))
+	a.Equal("`rank` VARCHAR(130) CHARACTER SET ascii COLLATE ascii_bin NOT NULL", ProductionConfig().MySQLColumn("rank"))
+}
+
+func TestConfig_PostgresCheck(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(`CHECK ("rank" ~ '^[0-9]\|[0-z]{1,6}$')`, DefaultConfig().PostgresCheck("rank"))
+	a.Equal(`CHECK ("rank" ~ '^[0-9]\|[0-9a-z]{1,6}$')`, CaseInsensitiveConfig().PostgresCheck("rank"))
+	a.Equal(`CHECK ("rank" ~ '^[0-9]\:[0-9a-z]{6,128}$')`, JiraConfig().WithSeparator(':').PostgresCheck("rank"))
+	a.Equal(`CHECK ("rank" ~ '^[0-9]\|[0-z]{1,}$')`, DefaultConfig().WithMaxRankLength(0).PostgresCheck("rank"))
+	a.Equal(`CHECK ("rank" ~ '^[0-9]\|[0-z]{1,}$')`, DefaultConfig().WithMaxRankLength(300).PostgresCheck("rank"))
+
+	alphabet, err := NewAlphabet("01\\]^")
+	if a.NoError(err) {
+		a.Equal(`CHECK ("rank" ~ '^[0-9]\|[01\\-\^]{1,6}$')`, DefaultConfig().WithAlphabet(alphabet).PostgresCheck("rank"))
+	}
+}
+
+func TestConfig_PostgresCheck_Keys(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	for _, config := range []*Config{DefaultConfig(), CaseInsensitiveConfig(), JiraConfig().WithSeparator(':')} {
+		check := config.PostgresCheck("rank")
+		pattern := strings.TrimSuffix(strings.TrimPrefix(check, `CHECK ("rank" ~ '`), `')`)
+		re, err := regexp.Compile(pattern)
+		r.NoError(err, check)
+
+		// Keys in the lowest and highest bucket the package produces match
+		for _, bucket := range []uint8{0, MaxBucket} {
+			k, err := Between(MinKey(bucket, config), MaxKey(bucket, config), config)
+			r.NoError(err)
+			a.True(re.MatchString(k.String()), "%s does not match %s", k, check)
+		}
+		a.False(re.MatchString("x"+MinKey(0, config).String()[1:]), check)
+	}
+
+	// As do keys built from a separately stored bucket
+	k, err := NewKey(MaxBucket, "abc")
+	r.NoError(err)
+	a.Regexp(`^\^\[0-9\]`, strings.TrimPrefix(DefaultConfig().PostgresCheck("rank"), `CHECK ("rank" ~ '`))
+	_, err = NewKey(MaxBucket+1, "abc")
+	a.Error(err)
+
+	moved := ReorderableList{item(0, "0|a")}
+	_, err = moved.ReassignBuckets(func(Reorderable) uint8 { return MaxBucket }, DefaultConfig())
+	r.NoError(err)
+	a.Equal(k.Bucket(), moved[0].GetKey().Bucket())
+}
+
+func TestConfig_PostgresDDL(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(`CREATE INDEX "tasks_rank_idx" ON "tasks" ("board_id", "rank")`, DefaultConfig().PostgresIndex("tasks", "rank", "board_id"))
+	a.Equal(`ALTER TABLE "app"."tasks" ADD COLUMN "rank" VARCHAR(8) COLLATE "C" NOT NULL;
+ALTER TABLE "app"."tasks" ADD CONSTRAINT "tasks_rank_check" CHECK ("rank" ~ '^[0-9]\|[0-z]{1,6}$');
+CREATE INDEX "tasks_rank_idx" ON "app"."tasks" ("rank");
+`, DefaultConfig().PostgresDDL("app.tasks", "rank"))
+}
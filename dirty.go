@@ -0,0 +1,36 @@
+package lexorank
+
+// Keys returns the current key of every item in list order. Take it before an
+// operation and pass it to Changes afterwards to find the rows to write back.
+func (l ReorderableList) Keys() Keys {
+	keys := make(Keys, len(l))
+	for i, it := range l {
+		keys[i] = it.GetKey()
+	}
+	return keys
+}
+
+// Changes returns the indices of the items whose key differs from the one in
+// before, a result of Keys taken earlier, in ascending order. Items beyond the
+// end of before are always reported. The item order must not have changed in
+// between; use the indices returned by Move and ReassignBuckets for operations
+// that reorder the slice.
+func (l ReorderableList) Changes(before Keys) []int {
+	var changed []int
+	for i, it := range l {
+		if i >= len(before) || it.GetKey().Compare(before[i]) != 0 {
+			changed = append(changed, i)
+		}
+	}
+	return changed
+}
+
+// Track runs op, typically a call to Insert, Append, Prepend or Normalize on
+// the list, and returns the indices of the items it rewrote, for use with
+// ChangeSet. The indices are returned even if op fails, since a rebalance may
+// have modified some keys before the error.
+func (l ReorderableList) Track(op func() error) ([]int, error) {
+	before := l.Keys()
+	err := op()
+	return l.Changes(before), err
+}
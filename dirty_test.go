@@ -0,0 +1,54 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorderableList_Changes(t *testing.T) {
+	a := assert.New(t)
+
+	list := ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|c")}
+	before := list.Keys()
+	a.Empty(list.Changes(before))
+
+	list[1].SetKey(mustKey("0|bU"))
+	a.Equal([]int{1}, list.Changes(before))
+	a.Equal([]int{1, 2}, list.Changes(before[:2]))
+}
+
+func TestReorderableList_Track(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|c")}
+
+	changed, err := list.Track(func() error {
+		_, err := list.Insert(1, DefaultConfig())
+		return err
+	})
+	r.NoError(err)
+	a.Empty(changed, "a fast path insert rewrites nothing")
+
+	list[2].SetKey(mustKey("0|b0"))
+	changed, err = list.Track(func() error {
+		_, err := list.Insert(2, DefaultConfig().WithMaxRankLength(2))
+		return err
+	})
+	r.NoError(err)
+	a.Equal([]int{0, 1, 2}, changed, "the tight pair forces a normalize")
+	a.True(list.StrictlySorted())
+
+	config := DefaultConfig()
+	config.AutoNormalize = false
+	before := list.Keys()
+	changed, err = list.Track(func() error {
+		_, err := list.Normalize(config)
+		return err
+	})
+	a.ErrorIs(err, ErrNormalizationRequired)
+	a.Empty(changed)
+	a.Equal(before, list.Keys())
+}
@@ -0,0 +1,88 @@
+package lexorank
+
+import (
+	"math/big"
+	"sort"
+)
+
+// EqualizeSmallestGaps finds the k tightest gaps between adjacent keys and
+// spreads the two items around each of them evenly between their outer
+// neighbours. It is a cheap maintenance pass for mostly healthy lists, where a
+// full Normalize would rewrite far more keys than necessary.
+//
+// A neighbourhood is only rewritten if that widens its smallest gap, and each
+// item is rewritten at most once. It returns the indices of the rewritten
+// items in ascending order. The list must be strictly sorted.
+func (l ReorderableList) EqualizeSmallestGaps(k int, config *Config) ([]int, error) {
+	if len(l) < 2 || k <= 0 {
+		return nil, nil
+	}
+
+	// Gaps are only comparable when measured at the same length
+	scale := max(config.MaxRankLength, 1)
+	for _, it := range l {
+		scale = max(scale, len(it.GetKey().rank))
+	}
+
+	gaps := make([]int, 0, len(l)-1)
+	dist := make(map[int]*big.Int, len(l)-1)
+	for i := 1; i < len(l); i++ {
+		gaps = append(gaps, i)
+		dist[i] = distanceAt(config.alphabet(), l[i-1].GetKey(), l[i].GetKey(), scale)
+	}
+	sort.SliceStable(gaps, func(a, b int) bool {
+		return dist[gaps[a]].Cmp(dist[gaps[b]]) < 0
+	})
+
+	touched := make(map[int]bool)
+	var changed []int
+	for _, g := range gaps[:min(k, len(gaps))] {
+		if touched[g-1] || touched[g] {
+			continue
+		}
+
+		first := l[g-1].GetKey()
+		after := bottomOf(first.bucket, config)
+		if g >= 2 {
+			after = l[g-2].GetKey()
+		}
+		var next *Key
+		if g+1 < len(l) {
+			n := l[g+1].GetKey()
+			next = &n
+		}
+
+		res, err := ReserveRange(after, next, 2, config)
+		if err != nil {
+			continue // no room in this neighbourhood
+		}
+		keys := res.Keys()
+
+		before := minGap(config.alphabet(), after, next, l[g-1].GetKey(), l[g].GetKey(), scale)
+		if minGap(config.alphabet(), after, next, keys[0], keys[1], scale).Cmp(before) <= 0 {
+			continue
+		}
+
+		l.setKey(g-1, keys[0], AuditEqualize, config)
+		l.setKey(g, keys[1], AuditEqualize, config)
+		touched[g-1], touched[g] = true, true
+		changed = append(changed, g-1, g)
+	}
+
+	sort.Ints(changed)
+	return changed, nil
+}
+
+// minGap returns the smallest gap in the sequence after, a, b[, next].
+func minGap(alphabet *Alphabet, after Key, next *Key, a, b Key, scale int) *big.Int {
+	m := distanceAt(alphabet, after, a, scale)
+	if d := distanceAt(alphabet, a, b, scale); d.Cmp(m) < 0 {
+		m = d
+	}
+	if next != nil {
+		if d := distanceAt(alphabet, b, *next, scale); d.Cmp(m) < 0 {
+			m = d
+		}
+	}
+	return m
+}
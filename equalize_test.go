@@ -0,0 +1,44 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorderableList_EqualizeSmallestGaps(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{
+		item(0, "0|5"),
+		item(1, "0|a"),
+		item(2, "0|a0001"),
+		item(3, "0|k"),
+		item(4, "0|p"),
+		item(5, "0|paaaa"),
+		item(6, "0|paaab"),
+		item(7, "0|u"),
+	}
+	before := list.Stats(DefaultConfig())
+
+	changed, err := list.EqualizeSmallestGaps(2, DefaultConfig())
+	r.NoError(err)
+	a.Equal([]int{1, 2, 5, 6}, changed)
+	a.True(list.StrictlySorted())
+
+	after := list.Stats(DefaultConfig())
+	a.True(after.MinGap.Cmp(before.MinGap) > 0, "smallest gap has widened")
+	a.Equal("0|5", list[0].GetKey().String())
+	a.Equal("0|k", list[3].GetKey().String())
+	a.Equal("0|u", list[7].GetKey().String())
+}
+
+func TestReorderableList_EqualizeSmallestGaps_Healthy(t *testing.T) {
+	list := ReorderableList{item(0, "0|?"), item(1, "0|N"), item(2, "0|]")}
+
+	changed, err := list.EqualizeSmallestGaps(1, DefaultConfig())
+	assert.NoError(t, err)
+	assert.Empty(t, changed, "evenly spread lists are left alone")
+}
@@ -1,10 +1,231 @@
 package lexorank
 
-import "github.com/pkg/errors"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
 
 var (
 	ErrOutOfBounds                      = errors.New("out of bounds")
 	ErrRebalanceRequired                = errors.New("rebalance required")
 	ErrNormalizationRequired            = errors.New("normalization required")
 	ErrKeyInsertionFailedAfterRebalance = errors.New("failed to insert key after rebalance")
+	ErrNotSorted                        = errors.New("keys are not sorted")
+	ErrRankTooLong                      = errors.New("rank exceeds maximum length")
+	ErrMixedBuckets                     = errors.New("list contains keys from multiple buckets")
+	ErrRewriteLimitExceeded             = errors.New("operation would rewrite too many keys")
+	ErrKeyNotFound                      = errors.New("key not found in list")
+	ErrStaleView                        = errors.New("client view of the list is stale")
+	ErrReplayDiverged                   = errors.New("replay produced a different key than recorded")
+	ErrInvalidKey                       = errors.New("invalid key")
+	ErrIncompatible                     = errors.New("key generation differs from the canonical vectors")
+	ErrRebalanceBudgetExceeded          = errors.New("rebalance exceeded its write budget")
+	ErrInvalidConfig                    = errors.New("invalid configuration")
+	ErrListTooLarge                     = errors.New("list is too large for an in-memory operation")
 )
+
+// RebalanceWindowError is returned by NeighborInsert when there is no room
+// between the two neighbours. From and To are inclusive bounds of the rows the
+// caller should load into a ReorderableList to perform a local rebalance.
+type RebalanceWindowError struct {
+	From Key
+	To   Key
+}
+
+func (e *RebalanceWindowError) Error() string {
+	return fmt.Sprintf("rebalance required: load keys between %s and %s", e.From, e.To)
+}
+
+func (e *RebalanceWindowError) Unwrap() error {
+	return ErrRebalanceRequired
+}
+
+// MixedBucketsError is returned by list operations when Config.StrictBuckets
+// is set and the list is not homogeneous. Indices holds every item whose
+// bucket differs from Bucket, the bucket of the first item.
+type MixedBucketsError struct {
+	Bucket  uint8
+	Indices []int
+}
+
+func (e *MixedBucketsError) Error() string {
+	return fmt.Sprintf("list contains keys from multiple buckets: expected bucket %d, mismatches at indices %v", e.Bucket, e.Indices)
+}
+
+func (e *MixedBucketsError) Unwrap() error {
+	return ErrMixedBuckets
+}
+
+// RewriteLimitError is returned when an operation would rewrite more keys than
+// Config.MaxKeysRewritten allows. No key has been modified when it is returned;
+// the caller should schedule an asynchronous Normalize instead.
+type RewriteLimitError struct {
+	Limit    int
+	Required int
+}
+
+func (e *RewriteLimitError) Error() string {
+	return fmt.Sprintf("operation would rewrite %d keys (limit %d): schedule a normalize instead", e.Required, e.Limit)
+}
+
+func (e *RewriteLimitError) Unwrap() error {
+	return ErrRewriteLimitExceeded
+}
+
+// ListTooLargeError is returned by list operations when the list has more
+// items than Config.MaxListSize allows. Lists that large should be reordered
+// with the storage-backed APIs, such as NeighborInsert, MaintenanceScheduler
+// and BucketMigration, rather than loaded and rewritten in one go.
+type ListTooLargeError struct {
+	Limit int
+	Size  int
+}
+
+func (e *ListTooLargeError) Error() string {
+	return fmt.Sprintf("list has %d items (limit %d): use the storage-backed APIs instead", e.Size, e.Limit)
+}
+
+func (e *ListTooLargeError) Unwrap() error {
+	return ErrListTooLarge
+}
+
+// RebalanceBudgetError is returned when a local rebalance would shift more keys
+// than Config.MaxRebalanceWrites allows. Rewritten holds the indices of the
+// keys that were already shifted, in ascending order. The list is still in
+// order, so they should be persisted before scheduling a wider rebalance.
+type RebalanceBudgetError struct {
+	Limit     int
+	Rewritten []int
+}
+
+func (e *RebalanceBudgetError) Error() string {
+	return fmt.Sprintf("rebalance stopped after rewriting %d keys (limit %d)", len(e.Rewritten), e.Limit)
+}
+
+func (e *RebalanceBudgetError) Unwrap() error {
+	return ErrRebalanceBudgetExceeded
+}
+
+// StaleViewError is returned when the neighbour keys a client believed to
+// surround a position don't match the list. Nil keys mean there is no
+// neighbour on that side.
+type StaleViewError struct {
+	Position     uint
+	ExpectedPrev *Key
+	ExpectedNext *Key
+	ActualPrev   *Key
+	ActualNext   *Key
+}
+
+func (e *StaleViewError) Error() string {
+	return fmt.Sprintf("client view of the list is stale at position %d: expected neighbours %s and %s, found %s and %s",
+		e.Position, keyOrNone(e.ExpectedPrev), keyOrNone(e.ExpectedNext), keyOrNone(e.ActualPrev), keyOrNone(e.ActualNext))
+}
+
+func (e *StaleViewError) Unwrap() error {
+	return ErrStaleView
+}
+
+// ReplayDivergenceError is returned by Replay when an operation produces a
+// different key than the one recorded in the log.
+type ReplayDivergenceError struct {
+	Index    int
+	Op       Op
+	Expected Key
+	Actual   Key
+}
+
+func (e *ReplayDivergenceError) Error() string {
+	return fmt.Sprintf("replay diverged at operation %d (%s): expected key %s, got %s", e.Index, e.Op.Kind, e.Expected, e.Actual)
+}
+
+func (e *ReplayDivergenceError) Unwrap() error {
+	return ErrReplayDiverged
+}
+
+// KeyValidationError lists every rule a client-supplied key breaks. It wraps
+// ErrInvalidKey.
+type KeyValidationError struct {
+	Key        Key
+	Violations []Violation
+}
+
+func (e *KeyValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Message
+	}
+	return fmt.Sprintf("invalid key %s: %s", e.Key, strings.Join(msgs, "; "))
+}
+
+func (e *KeyValidationError) Unwrap() error {
+	return ErrInvalidKey
+}
+
+// Has reports whether the error contains a violation with the given code.
+func (e *KeyValidationError) Has(code ViolationCode) bool {
+	for _, v := range e.Violations {
+		if v.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// CompatibilityError is returned by VerifyCompatibility when this build of the
+// library produces different keys than the canonical vectors.
+type CompatibilityError struct {
+	Mismatches []string
+}
+
+func (e *CompatibilityError) Error() string {
+	return fmt.Sprintf("%d compatibility vectors failed: %s", len(e.Mismatches), strings.Join(e.Mismatches, "; "))
+}
+
+func (e *CompatibilityError) Unwrap() error {
+	return ErrIncompatible
+}
+
+// ConfigError is returned by Config.Validate and lists every problem found,
+// each naming the field to fix.
+type ConfigError struct {
+	Problems []string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("invalid configuration: %s", strings.Join(e.Problems, "; "))
+}
+
+func (e *ConfigError) Unwrap() error {
+	return ErrInvalidConfig
+}
+
+// IsRequestError reports whether err was caused by the keys or positions a
+// caller passed, such as a malformed or unsorted key, rather than by the state
+// of the list. Services answer these as bad requests.
+func IsRequestError(err error) bool {
+	return errors.Is(err, ErrInvalidKey) ||
+		errors.Is(err, ErrNotSorted) ||
+		errors.Is(err, ErrMixedBuckets) ||
+		errors.Is(err, ErrOutOfBounds)
+}
+
+// IsPreconditionError reports whether err means the stored list has to be
+// rebalanced or normalized before the operation can succeed. Services answer
+// these as conflicts.
+func IsPreconditionError(err error) bool {
+	return errors.Is(err, ErrRankTooLong) ||
+		errors.Is(err, ErrRebalanceRequired) ||
+		errors.Is(err, ErrNormalizationRequired) ||
+		errors.Is(err, ErrRewriteLimitExceeded) ||
+		errors.Is(err, ErrListTooLarge)
+}
+
+func keyOrNone(k *Key) string {
+	if k == nil {
+		return "none"
+	}
+	return k.String()
+}
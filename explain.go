@@ -0,0 +1,116 @@
+package lexorank
+
+// InsertPath describes how an operation found room for a new key.
+type InsertPath int
+
+const (
+	// PathDirect means the key was generated straight away, between the two
+	// neighbours or by the append strategy at an end, without touching any
+	// other item.
+	PathDirect InsertPath = iota
+
+	// PathLocalRebalance means neighbouring keys were shifted to make room.
+	PathLocalRebalance
+
+	// PathNormalize means the local rebalance could not make room and the
+	// whole list was normalized.
+	PathNormalize
+)
+
+func (p InsertPath) String() string {
+	switch p {
+	case PathDirect:
+		return "direct"
+	case PathLocalRebalance:
+		return "local rebalance"
+	case PathNormalize:
+		return "normalize"
+	default:
+		return "unknown"
+	}
+}
+
+// Explanation describes the decisions taken by an Insert, Append or Prepend,
+// as returned by the Explain variants of those methods.
+type Explanation struct {
+	// Operation is "insert", "append" or "prepend". Inserts at either end of
+	// the list are carried out as an append or prepend.
+	Operation string
+
+	// Path is the most expensive path taken to find room for the key.
+	Path InsertPath
+
+	// Strategy is the append strategy used when the key was generated at an
+	// end of the list.
+	Strategy AppendStrategy
+
+	// Attempts is the number of times a key was generated, one more than the
+	// number of rebalances unless the operation failed.
+	Attempts int
+
+	// Rewritten holds the indices of the other items whose keys were changed,
+	// in ascending order.
+	Rewritten []int
+}
+
+func (e *Explanation) attempt() {
+	if e != nil {
+		e.Attempts++
+	}
+}
+
+func (e *Explanation) edge(op string, strategy AppendStrategy) {
+	if e != nil {
+		e.Operation = op
+		e.Strategy = strategy
+	}
+}
+
+func (e *Explanation) took(path InsertPath) {
+	if e != nil && path > e.Path {
+		e.Path = path
+	}
+}
+
+// InsertExplain is like Insert but also explains how the key was found.
+func (l ReorderableList) InsertExplain(position uint, config *Config) (*Key, *Explanation, error) {
+	e := &Explanation{Operation: "insert"}
+	var k *Key
+	var err error
+	e.Rewritten, err = l.Track(func() error {
+		k, err = l.Insert(position, config.explaining(e))
+		return err
+	})
+	return k, e, err
+}
+
+// AppendExplain is like Append but also explains how the key was found.
+func (l ReorderableList) AppendExplain(config *Config) (Key, *Explanation, error) {
+	e := &Explanation{Operation: "append"}
+	var k Key
+	var err error
+	e.Rewritten, err = l.Track(func() error {
+		k, err = l.Append(config.explaining(e))
+		return err
+	})
+	return k, e, err
+}
+
+// PrependExplain is like Prepend but also explains how the key was found.
+func (l ReorderableList) PrependExplain(config *Config) (Key, *Explanation, error) {
+	e := &Explanation{Operation: "prepend"}
+	var k Key
+	var err error
+	e.Rewritten, err = l.Track(func() error {
+		k, err = l.Prepend(config.explaining(e))
+		return err
+	})
+	return k, e, err
+}
+
+// explaining returns a copy of the config that records decisions into e.
+func (c *Config) explaining(e *Explanation) *Config {
+	newConfig := *c
+	newConfig.explain = e
+	return &newConfig
+}
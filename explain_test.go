@@ -0,0 +1,51 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorderableList_InsertExplain(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|c")}
+	_, e, err := list.InsertExplain(1, DefaultConfig())
+	r.NoError(err)
+	a.Equal(&Explanation{Operation: "insert", Path: PathDirect, Attempts: 1}, e)
+
+	_, e, err = list.InsertExplain(3, DefaultConfig().WithAppendStrategy(AppendStrategyStep))
+	r.NoError(err)
+	a.Equal("append", e.Operation)
+	a.Equal(AppendStrategyStep, e.Strategy)
+	a.Equal(PathDirect, e.Path)
+
+	tight := ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|b0")}
+	_, e, err = tight.InsertExplain(2, DefaultConfig().WithMaxRankLength(2))
+	r.NoError(err)
+	a.Equal(PathNormalize, e.Path)
+	a.Equal(2, e.Attempts)
+	a.Equal([]int{0, 1, 2}, e.Rewritten)
+	a.Equal("normalize", e.Path.String())
+}
+
+func TestReorderableList_AppendExplain(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|z")}
+	_, e, err := list.AppendExplain(DefaultConfig().WithMaxRankLength(1))
+	r.NoError(err)
+	a.Equal("append", e.Operation)
+	a.Equal(PathLocalRebalance, e.Path)
+	a.Equal(2, e.Attempts)
+	a.Equal([]int{2}, e.Rewritten)
+
+	_, e, err = list.PrependExplain(DefaultConfig())
+	r.NoError(err)
+	a.Equal("prepend", e.Operation)
+	a.Equal(PathDirect, e.Path)
+	a.Empty(e.Rewritten)
+}
@@ -0,0 +1,115 @@
+package lexorank
+
+import "sort"
+
+// Action is the maintenance recommended for a list by SummarizeFleet.
+type Action int
+
+const (
+	// ActionNone means the list has enough room left.
+	ActionNone Action = iota
+
+	// ActionRebalance means the tightest gap is nearly exhausted but the list
+	// as a whole has room, so a local Rebalance around it is enough.
+	ActionRebalance
+
+	// ActionNormalize means the list is crowded overall, or has duplicate or
+	// unsorted keys, and should be normalized.
+	ActionNormalize
+)
+
+func (a Action) String() string {
+	switch a {
+	case ActionNone:
+		return "none"
+	case ActionRebalance:
+		return "rebalance"
+	case ActionNormalize:
+		return "normalize"
+	default:
+		return "unknown"
+	}
+}
+
+// ListHealth is the state of a single list in a FleetSummary.
+type ListHealth struct {
+	Name  string
+	Stats ListStats
+
+	// Midpoints is the number of worst case inserts left at the tightest gap,
+	// as in Capacity.Midpoints. It is -1 for lists with fewer than two items.
+	Midpoints int
+
+	Action Action
+}
+
+// FleetSummary aggregates the statistics of many lists, for example every
+// board hosted by a service, to find the ones that need maintenance.
+type FleetSummary struct {
+	// Lists and Items count the lists and the items across all of them
+	Lists int
+	Items int
+
+	// RankLengths counts items across all lists by the length of their rank
+	RankLengths map[int]int
+
+	// AtRisk holds the lists with fewer than the requested number of
+	// midpoints left at their tightest gap, the most crowded first
+	AtRisk []ListHealth
+}
+
+// SummarizeFleet aggregates the statistics of many lists, keyed by name as for
+// WritePrometheus. Lists with fewer than minMidpoints worst case inserts left
+// at their tightest gap are reported in AtRisk with a recommended action.
+func SummarizeFleet(lists map[string]ListStats, minMidpoints int) FleetSummary {
+	summary := FleetSummary{
+		Lists:       len(lists),
+		RankLengths: make(map[int]int),
+	}
+
+	for name, s := range lists {
+		summary.Items += s.Count
+		for length, n := range s.RankLengths {
+			summary.RankLengths[length] += n
+		}
+
+		h := s.health(name, minMidpoints)
+		if h.Action != ActionNone {
+			summary.AtRisk = append(summary.AtRisk, h)
+		}
+	}
+
+	sort.Slice(summary.AtRisk, func(i, j int) bool {
+		a, b := summary.AtRisk[i], summary.AtRisk[j]
+		if a.Midpoints != b.Midpoints {
+			return a.Midpoints < b.Midpoints
+		}
+		return a.Name < b.Name
+	})
+
+	return summary
+}
+
+func (s ListStats) health(name string, minMidpoints int) ListHealth {
+	h := ListHealth{Name: name, Stats: s, Midpoints: -1}
+	if s.MinGap == nil {
+		return h
+	}
+
+	if s.MinGap.Sign() <= 0 {
+		h.Midpoints = 0
+		h.Action = ActionNormalize
+		return h
+	}
+
+	h.Midpoints = gapMidpoints(s.MinGap)
+	switch {
+	case h.Midpoints >= minMidpoints:
+		h.Action = ActionNone
+	case gapMidpoints(s.AvgGap) < minMidpoints:
+		h.Action = ActionNormalize
+	default:
+		h.Action = ActionRebalance
+	}
+	return h
+}
@@ -0,0 +1,35 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeFleet(t *testing.T) {
+	a := assert.New(t)
+
+	config := DefaultConfig().WithMaxRankLength(2)
+	lists := map[string]ListStats{
+		"roomy":   ReorderableList{item(0, "0|a"), item(1, "0|m")}.Stats(config),
+		"tight":   ReorderableList{item(0, "0|0"), item(1, "0|a"), item(2, "0|a1"), item(3, "0|z")}.Stats(config),
+		"crowded": ReorderableList{item(0, "0|a"), item(1, "0|a1"), item(2, "0|a2")}.Stats(config),
+		"dupes":   ReorderableList{item(0, "0|a"), item(1, "0|a")}.Stats(config),
+		"single":  ReorderableList{item(0, "0|a")}.Stats(config),
+	}
+
+	s := SummarizeFleet(lists, 4)
+	a.Equal(5, s.Lists)
+	a.Equal(12, s.Items)
+	a.Equal(map[int]int{1: 9, 2: 3}, s.RankLengths)
+
+	names := make([]string, len(s.AtRisk))
+	actions := make([]Action, len(s.AtRisk))
+	for i, h := range s.AtRisk {
+		names[i] = h.Name
+		actions[i] = h.Action
+	}
+	a.Equal([]string{"crowded", "dupes", "tight"}, names)
+	a.Equal([]Action{ActionNormalize, ActionNormalize, ActionRebalance}, actions)
+	a.Equal("rebalance", ActionRebalance.String())
+}
@@ -0,0 +1,272 @@
+package lexorank
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The fractional-indexing scheme, used by Figma-style frontends and by the
+// fractional-indexing JavaScript package, orders items by base-62 strings
+// without a bucket prefix, such as "a0", "a0V" or "Zz". Each string starts
+// with an integer part whose first character encodes its length, 'a' to 'z'
+// for two to 27 characters and 'A' to 'Z' for the negative integers, followed
+// by an optional fraction without trailing zeros. The strings sort in byte
+// order, so they are valid Base62Alphabet ranks and compare the same way as
+// keys.
+//
+// ParseFractionalKey and FormatFractionalKey convert between the two forms,
+// and FractionalKeyBetween generates new strings exactly like the JavaScript
+// generateKeyBetween, so clients generating keys locally and this package can
+// share a column. Keys from Between are not valid fractional-indexing strings
+// in general; use FractionalKeyBetween for that column instead.
+
+var fractionalDigits = Base62Alphabet.String()
+
+// fractionalConfig is the configuration of keys holding fractional-indexing
+// strings.
+var fractionalConfig = DefaultConfig().WithAlphabet(Base62Alphabet).WithMaxRankLength(0)
+
+// smallestFractionalInteger is the lowest integer part, which has no valid
+// key below it.
+var smallestFractionalInteger = "A" + strings.Repeat("0", 26)
+
+// ParseFractionalKey validates a fractional-indexing string and returns it as
+// a key in bucket, with the string as its Base62Alphabet rank.
+func ParseFractionalKey(s string, bucket uint8) (*Key, error) {
+	if err := validateFractionalKey(s); err != nil {
+		return nil, err
+	}
+	return fractionalConfig.NewKey(bucket, s)
+}
+
+// FormatFractionalKey returns the rank of k as a fractional-indexing string,
+// dropping the bucket. It fails if the rank is not a valid string of the
+// scheme.
+func FormatFractionalKey(k Key) (string, error) {
+	s := k.RankString()
+	if err := validateFractionalKey(s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// FractionalKeyBetween returns a fractional-indexing string that sorts
+// strictly between a and b, where an empty string leaves that side open. It
+// produces the same result as generateKeyBetween of the JavaScript
+// fractional-indexing package with its default base-62 digits.
+func FractionalKeyBetween(a, b string) (string, error) {
+	if a != "" {
+		if err := validateFractionalKey(a); err != nil {
+			return "", err
+		}
+	}
+	if b != "" {
+		if err := validateFractionalKey(b); err != nil {
+			return "", err
+		}
+	}
+	if a != "" && b != "" && a >= b {
+		return "", errors.Errorf("%s is not below %s", a, b)
+	}
+
+	switch {
+	case a == "" && b == "":
+		return "a0", nil
+	case a == "":
+		ib, _ := fractionalIntegerPart(b)
+		fb := b[len(ib):]
+		if ib == smallestFractionalInteger {
+			return ib + fractionalMidpoint("", fb), nil
+		}
+		if ib < b {
+			return ib, nil
+		}
+		i, ok := decrementFractionalInteger(ib)
+		if !ok {
+			return "", errors.New("cannot decrement any more")
+		}
+		return i, nil
+	case b == "":
+		ia, _ := fractionalIntegerPart(a)
+		fa := a[len(ia):]
+		i, ok := incrementFractionalInteger(ia)
+		if !ok {
+			return ia + fractionalMidpoint(fa, ""), nil
+		}
+		return i, nil
+	}
+
+	ia, _ := fractionalIntegerPart(a)
+	fa := a[len(ia):]
+	ib, _ := fractionalIntegerPart(b)
+	fb := b[len(ib):]
+	if ia == ib {
+		return ia + fractionalMidpoint(fa, fb), nil
+	}
+	i, ok := incrementFractionalInteger(ia)
+	if !ok {
+		return "", errors.New("cannot increment any more")
+	}
+	if i < b {
+		return i, nil
+	}
+	return ia + fractionalMidpoint(fa, ""), nil
+}
+
+// fractionalMidpoint returns a fraction between a and b, where an empty b
+// means one. Neither may end in a zero digit.
+func fractionalMidpoint(a, b string) string {
+	zero := fractionalDigits[0]
+	if b != "" {
+		// Skip the common prefix, padding a with zeros as it may be shorter
+		n := 0
+		for n < len(b) && fractionalDigitAt(a, n, zero) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + fractionalMidpoint(a[min(n, len(a)):], b[n:])
+		}
+	}
+
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(fractionalDigits, a[0])
+	}
+	digitB := len(fractionalDigits)
+	if b != "" {
+		digitB = strings.IndexByte(fractionalDigits, b[0])
+	}
+
+	if digitB-digitA > 1 {
+		return string(fractionalDigits[(digitA+digitB+1)/2])
+	}
+	// The first digits are consecutive
+	if len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if a != "" {
+		rest = a[1:]
+	}
+	return string(fractionalDigits[digitA]) + fractionalMidpoint(rest, "")
+}
+
+func fractionalDigitAt(s string, i int, zero byte) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return zero
+}
+
+// fractionalIntegerLength returns the length of the integer part starting
+// with head.
+func fractionalIntegerLength(head byte) (int, bool) {
+	switch {
+	case head >= 'a' && head <= 'z':
+		return int(head-'a') + 2, true
+	case head >= 'A' && head <= 'Z':
+		return int('Z'-head) + 2, true
+	}
+	return 0, false
+}
+
+func fractionalIntegerPart(s string) (string, error) {
+	if s == "" {
+		return "", errors.New("empty fractional key")
+	}
+	n, ok := fractionalIntegerLength(s[0])
+	if !ok {
+		return "", errors.Errorf("invalid fractional key head %q in %q", s[0], s)
+	}
+	if n > len(s) {
+		return "", errors.Errorf("fractional key %q is shorter than its integer part", s)
+	}
+	return s[:n], nil
+}
+
+func validateFractionalKey(s string) error {
+	if s == smallestFractionalInteger {
+		return errors.Errorf("invalid fractional key %q", s)
+	}
+	i, err := fractionalIntegerPart(s)
+	if err != nil {
+		return err
+	}
+	for j := range len(s) {
+		if j > 0 && strings.IndexByte(fractionalDigits, s[j]) < 0 {
+			return errors.Errorf("fractional key %q uses characters outside the alphabet %q", s, fractionalDigits)
+		}
+	}
+	if f := s[len(i):]; strings.HasSuffix(f, fractionalDigits[:1]) {
+		return errors.Errorf("fractional key %q has a trailing zero", s)
+	}
+	return nil
+}
+
+// incrementFractionalInteger returns the integer part following x, or false
+// if x is the largest one.
+func incrementFractionalInteger(x string) (string, bool) {
+	head, digits := x[0], []byte(x[1:])
+	carry := true
+	for i := len(digits) - 1; carry && i >= 0; i-- {
+		d := strings.IndexByte(fractionalDigits, digits[i]) + 1
+		if d == len(fractionalDigits) {
+			digits[i] = fractionalDigits[0]
+		} else {
+			digits[i] = fractionalDigits[d]
+			carry = false
+		}
+	}
+	if !carry {
+		return string(head) + string(digits), true
+	}
+
+	switch head {
+	case 'Z':
+		return "a" + fractionalDigits[:1], true
+	case 'z':
+		return "", false
+	}
+	h := head + 1
+	if h > 'a' {
+		digits = append(digits, fractionalDigits[0])
+	} else {
+		digits = digits[:len(digits)-1]
+	}
+	return string(h) + string(digits), true
+}
+
+// decrementFractionalInteger returns the integer part before x, or false if
+// x is the smallest one.
+func decrementFractionalInteger(x string) (string, bool) {
+	head, digits := x[0], []byte(x[1:])
+	last := fractionalDigits[len(fractionalDigits)-1]
+	borrow := true
+	for i := len(digits) - 1; borrow && i >= 0; i-- {
+		d := strings.IndexByte(fractionalDigits, digits[i]) - 1
+		if d == -1 {
+			digits[i] = last
+		} else {
+			digits[i] = fractionalDigits[d]
+			borrow = false
+		}
+	}
+	if !borrow {
+		return string(head) + string(digits), true
+	}
+
+	switch head {
+	case 'a':
+		return "Z" + string(last), true
+	case 'A':
+		return "", false
+	}
+	h := head - 1
+	if h < 'Z' {
+		digits = append(digits, last)
+	} else {
+		digits = digits[:len(digits)-1]
+	}
+	return string(h) + string(digits), true
+}
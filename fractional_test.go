@@ -0,0 +1,91 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFractionalKeyBetween(t *testing.T) {
+	r := require.New(t)
+
+	// Vectors of the JavaScript fractional-indexing package
+	for _, tc := range []struct{ a, b, want string }{
+		{"", "", "a0"},
+		{"", "a0", "Zz"},
+		{"", "Zz", "Zy"},
+		{"a0", "", "a1"},
+		{"a1", "", "a2"},
+		{"a0", "a1", "a0V"},
+		{"a1", "a2", "a1V"},
+		{"a0V", "a1", "a0l"},
+		{"Zz", "a0", "ZzV"},
+		{"Zz", "a1", "a0"},
+		{"", "Y00", "Xzzz"},
+		{"bzz", "", "c000"},
+		{"a0", "a0V", "a0G"},
+		{"a0", "a0G", "a08"},
+		{"b125", "b129", "b127"},
+		{"a0", "a1V", "a1"},
+		{"Zz", "a01", "a0"},
+		{"", "a0V", "a0"},
+		{"", "b999", "b99"},
+		{"", smallestFractionalInteger + "1", smallestFractionalInteger + "0V"},
+		{"zzzzzzzzzzzzzzzzzzzzzzzzzzz", "", "zzzzzzzzzzzzzzzzzzzzzzzzzzzV"},
+	} {
+		got, err := FractionalKeyBetween(tc.a, tc.b)
+		r.NoError(err, "%q %q", tc.a, tc.b)
+		r.Equal(tc.want, got, "%q %q", tc.a, tc.b)
+	}
+
+	for _, tc := range []struct{ a, b string }{
+		{"a00", ""},
+		{"a00", "a1"},
+		{"0", "1"},
+		{"a1", "a0"},
+		{"", smallestFractionalInteger},
+	} {
+		_, err := FractionalKeyBetween(tc.a, tc.b)
+		r.Error(err, "%q %q", tc.a, tc.b)
+	}
+}
+
+func TestFractionalKey_Interop(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Keys appended and inserted like a client would sort the same as the
+	// strings themselves
+	var strs []string
+	prev := ""
+	for range 30 {
+		s, err := FractionalKeyBetween(prev, "")
+		r.NoError(err)
+		strs = append(strs, s)
+		prev = s
+	}
+	s, err := FractionalKeyBetween("", strs[0])
+	r.NoError(err)
+	strs = append([]string{s}, strs...)
+	s, err = FractionalKeyBetween(strs[3], strs[4])
+	r.NoError(err)
+	strs = append(strs[:4], append([]string{s}, strs[4:]...)...)
+
+	keys := make(Keys, len(strs))
+	for i, s := range strs {
+		k, err := ParseFractionalKey(s, 1)
+		r.NoError(err, s)
+		a.Equal(uint8(1), k.Bucket())
+		formatted, err := FormatFractionalKey(*k)
+		r.NoError(err)
+		a.Equal(s, formatted)
+		keys[i] = *k
+	}
+	a.True(keys.strictlySorted())
+
+	_, err = ParseFractionalKey("a00", 0)
+	a.Error(err)
+	_, err = ParseFractionalKey("a-", 0)
+	a.Error(err)
+}
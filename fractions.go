@@ -0,0 +1,97 @@
+package lexorank
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// FractionalItem is a row of a table ordered by a float64 rank column, the
+// fractional indexing scheme that KeyAt models.
+type FractionalItem struct {
+	ID   string
+	Rank float64
+}
+
+// MigrateFractions converts float ranks in [lo, hi] to keys in bucket. Each
+// rank is scaled to [0, 1] and converted with KeyAt, so a key depends on its
+// rank alone and migrating the same rows twice gives the same keys. Items are
+// ordered by rank, with equal ranks keeping their order in the slice.
+//
+// Ranks closer together than MaxRankLength characters can tell apart collide
+// on the same key, as do equal ranks and ranks on the edges of the bucket.
+// Every item of a collision gets a key spread between the neighbouring keys
+// instead; their indices are returned in ascending order, for review. A
+// collision that does not fit fails with ErrRebalanceRequired, and the list
+// should be seeded with Spread instead.
+//
+// The change set has one change per item, with Index referring to items.
+func MigrateFractions(items []FractionalItem, lo, hi float64, bucket uint8, config *Config) (ChangeSet, []int, error) {
+	if config.MaxRankLength <= 0 {
+		return nil, nil, errors.New("converting fractions requires a MaxRankLength")
+	}
+	if !(lo < hi) || math.IsInf(lo, 0) || math.IsInf(hi, 0) {
+		return nil, nil, errors.Errorf("invalid rank range [%v, %v]", lo, hi)
+	}
+
+	order := make([]int, len(items))
+	for i, it := range items {
+		if math.IsNaN(it.Rank) || it.Rank < lo || it.Rank > hi {
+			return nil, nil, errors.Wrapf(ErrOutOfBounds, "rank %v of %s is outside [%v, %v]", it.Rank, it.ID, lo, hi)
+		}
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return items[order[i]].Rank < items[order[j]].Rank
+	})
+
+	keys := make(Keys, len(order))
+	for i, index := range order {
+		k, err := KeyAt(bucket, (items[index].Rank-lo)/(hi-lo), config)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys[i] = k
+	}
+
+	// Walk runs of items whose keys do not increase. A run of one item with
+	// room below it keeps its key; any other run is spread between the last
+	// key assigned and the first key of the next run.
+	var collided []int
+	prev, top := MinKey(bucket, config), MaxKey(bucket, config)
+	for start := 0; start < len(keys); {
+		end := start + 1
+		for end < len(keys) && keys[end].Compare(keys[start]) <= 0 {
+			end++
+		}
+
+		next := top
+		if end < len(keys) {
+			next = keys[end]
+		}
+		if end-start == 1 && keys[start].Compare(prev) > 0 && keys[start].Compare(top) < 0 {
+			prev = keys[start]
+			start = end
+			continue
+		}
+
+		spread, err := BetweenN(prev, next, end-start, config)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "resolving %d colliding ranks near %v", end-start, items[order[start]].Rank)
+		}
+		copy(keys[start:end], spread)
+		for _, index := range order[start:end] {
+			collided = append(collided, index)
+		}
+		prev = keys[end-1]
+		start = end
+	}
+	sort.Ints(collided)
+
+	cs := make(ChangeSet, len(items))
+	for i, index := range order {
+		cs[index] = Change{Index: index, ID: items[index].ID, Key: keys[i]}
+	}
+	return cs, collided, nil
+}
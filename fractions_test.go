@@ -0,0 +1,80 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateFractions(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+	config := DefaultConfig()
+
+	items := []FractionalItem{
+		{ID: "c", Rank: 0.75},
+		{ID: "a", Rank: 0.25},
+		{ID: "b", Rank: 0.5},
+	}
+	cs, collided, err := MigrateFractions(items, 0, 1, 0, config)
+	r.NoError(err)
+	a.Empty(collided)
+	r.Len(cs, 3)
+	for i, c := range cs {
+		want, err := KeyAt(0, items[i].Rank, config)
+		r.NoError(err)
+		a.Equal(i, c.Index)
+		a.Equal(items[i].ID, c.ID)
+		a.Equal(want.String(), c.Key.String())
+	}
+
+	// Any range scales to the same keys
+	scaled := []FractionalItem{{ID: "c", Rank: 175}, {ID: "a", Rank: 125}, {ID: "b", Rank: 150}}
+	again, _, err := MigrateFractions(scaled, 100, 200, 0, config)
+	r.NoError(err)
+	a.Equal(cs, again)
+}
+
+func TestMigrateFractions_Collisions(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+	config := DefaultConfig()
+
+	items := []FractionalItem{
+		{ID: "min", Rank: 0},
+		{ID: "a", Rank: 0.5},
+		{ID: "b", Rank: 0.5},
+		{ID: "c", Rank: 0.5 + 1e-15},
+		{ID: "d", Rank: 0.6},
+		{ID: "max", Rank: 1},
+	}
+	cs, collided, err := MigrateFractions(items, 0, 1, 0, config)
+	r.NoError(err)
+	a.Equal([]int{0, 1, 2, 3, 5}, collided)
+
+	d, err := KeyAt(0, 0.6, config)
+	r.NoError(err)
+	a.Equal(d.String(), cs[4].Key.String())
+
+	// The keys follow the ranks and leave room at both edges of the bucket
+	keys := make(Keys, len(cs))
+	for i, c := range cs {
+		keys[i] = c.Key
+	}
+	a.True(keys.strictlySorted())
+	a.Positive(keys[0].Compare(MinKey(0, config)))
+	a.Negative(keys[5].Compare(MaxKey(0, config)))
+}
+
+func TestMigrateFractions_Invalid(t *testing.T) {
+	a := assert.New(t)
+	config := DefaultConfig()
+
+	_, _, err := MigrateFractions([]FractionalItem{{ID: "a", Rank: 2}}, 0, 1, 0, config)
+	a.ErrorIs(err, ErrOutOfBounds)
+	_, _, err = MigrateFractions(nil, 1, 1, 0, config)
+	a.Error(err)
+	_, _, err = MigrateFractions(nil, 0, 1, 0, config.WithMaxRankLength(0))
+	a.Error(err)
+}
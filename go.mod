@@ -1,13 +1,28 @@
 module github.com/ntauth/lexorank
 
-go 1.22.1
+go 1.23
 
-require github.com/stretchr/testify v1.9.0
+require (
+	entgo.io/ent v0.14.5
+	github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue v1.15.20
+	github.com/aws/aws-sdk-go-v2/service/dynamodb v1.38.0
+	github.com/jackc/pgx/v5 v5.7.1
+	github.com/pkg/errors v0.9.1
+	github.com/stretchr/testify v1.9.0
+	google.golang.org/grpc v1.68.0
+	google.golang.org/protobuf v1.35.2
+)
 
 require (
+	github.com/aws/aws-sdk-go-v2/service/dynamodbstreams v1.24.8 // indirect
+	github.com/aws/smithy-go v1.22.1 // indirect
+	github.com/google/uuid v1.6.0 // indirect
 	github.com/kr/text v0.2.0 // indirect
-	github.com/pkg/errors v0.9.1 // indirect
 	github.com/rogpeppe/go-internal v1.9.0 // indirect
+	golang.org/x/net v0.29.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240903143218-8af14fe29dc1 // indirect
 )
 
 require (
@@ -0,0 +1,62 @@
+package lexorank
+
+// rebalanceSoonMidpoints is the number of worst case inserts left on either
+// side of a key below which InsertHint.RebalanceSoon is set.
+const rebalanceSoonMidpoints = 2
+
+// InsertHint is a newly generated key together with how much room is left
+// around it, to be returned to client SDKs so they can refresh neighbour keys
+// or request a rebalance before an insert at the same spot fails.
+type InsertHint struct {
+	Key Key `json:"key"`
+
+	// Before and After are the number of worst case inserts, as counted by
+	// Capacity.Midpoints, left between the key and its previous and next
+	// neighbours. They are -1 when there is no MaxRankLength.
+	Before int `json:"before"`
+	After  int `json:"after"`
+
+	// RebalanceSoon is set when either side has fewer than two inserts left.
+	RebalanceSoon bool `json:"rebalanceSoon"`
+}
+
+// NeighborInsertHint is like NeighborInsert, but also reports how much room is
+// left around the new key. Missing neighbours are measured against the edges
+// of the bucket.
+func NeighborInsertHint(prev, next *Key, config *Config) (*InsertHint, error) {
+	k, err := NeighborInsert(prev, next, config)
+	if err != nil {
+		return nil, err
+	}
+
+	lo, hi := MinKey(k.bucket, config), MaxKey(k.bucket, config)
+	if prev != nil {
+		lo = *prev
+	}
+	if next != nil {
+		hi = *next
+	}
+
+	h := &InsertHint{
+		Key:    *k,
+		Before: midpointsBetween(lo, *k, config),
+		After:  midpointsBetween(*k, hi, config),
+	}
+	h.RebalanceSoon = (h.Before >= 0 && h.Before < rebalanceSoonMidpoints) ||
+		(h.After >= 0 && h.After < rebalanceSoonMidpoints)
+
+	return h, nil
+}
+
+// midpointsBetween returns Capacity.Midpoints between lo and hi, 0 if they are
+// not ordered and -1 if the capacity is unbounded.
+func midpointsBetween(lo, hi Key, config *Config) int {
+	if config.MaxRankLength <= 0 {
+		return -1
+	}
+	c, err := lo.CapacityTo(hi, config)
+	if err != nil {
+		return 0
+	}
+	return c.Midpoints
+}
@@ -0,0 +1,42 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNeighborInsertHint(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig().WithMaxRankLength(2)
+	prev, next := mustKey("0|a"), mustKey("0|c")
+
+	h, err := NeighborInsertHint(&prev, &next, config)
+	r.NoError(err)
+	a.Equal("0|b", h.Key.String())
+	a.Equal(6, h.Before)
+	a.Equal(6, h.After)
+	a.False(h.RebalanceSoon)
+
+	next = mustKey("0|a2")
+	h, err = NeighborInsertHint(&prev, &next, config)
+	r.NoError(err)
+	a.Equal("0|a1", h.Key.String())
+	a.Equal(0, h.Before)
+	a.True(h.RebalanceSoon)
+
+	h, err = NeighborInsertHint(&prev, nil, config)
+	r.NoError(err)
+	a.Greater(h.After, 0, "measured against the top of the bucket")
+
+	h, err = NeighborInsertHint(&prev, nil, DefaultConfig().WithMaxRankLength(0))
+	r.NoError(err)
+	a.Equal(-1, h.After)
+
+	next = mustKey("0|a1")
+	_, err = NeighborInsertHint(&prev, &next, config)
+	a.ErrorIs(err, ErrRebalanceRequired)
+}
@@ -0,0 +1,46 @@
+package lexorank
+
+// Item is a ready-made Reorderable that pairs an identifier and a key with an
+// arbitrary payload, so most applications don't need to write their own
+// adapter type just to use a ReorderableList. It marshals to JSON as
+// {"id": ..., "rank": "0|hzzzzz", "payload": ...} for API responses.
+type Item[T any] struct {
+	ID      string `json:"id"`
+	Rank    Key    `json:"rank"`
+	Payload T      `json:"payload"`
+}
+
+var (
+	_ Reorderable  = (*Item[any])(nil)
+	_ Identifiable = (*Item[any])(nil)
+)
+
+// Implements the Orderable interface.
+func (i Item[T]) GetKey() Key { return i.Rank }
+
+// Implements the Mutable interface.
+func (i *Item[T]) SetKey(k Key) { i.Rank = k }
+
+// Implements the Identifiable interface.
+func (i Item[T]) GetID() string { return i.ID }
+
+// funcAdapter is the Reorderable returned by AdaptFunc.
+type funcAdapter struct {
+	get func() Key
+	set func(Key)
+}
+
+func (f funcAdapter) GetKey() Key  { return f.get() }
+func (f funcAdapter) SetKey(k Key) { f.set(k) }
+
+// AdaptFunc returns a Reorderable that reads and writes its key through get
+// and set, typically closures over a field of a type you don't own, such as an
+// ORM model or generated code:
+//
+//	list[i] = lexorank.AdaptFunc(
+//		func() lexorank.Key { return row.Rank },
+//		func(k lexorank.Key) { row.Rank = k },
+//	)
+func AdaptFunc(get func() Key, set func(Key)) Reorderable {
+	return funcAdapter{get: get, set: set}
+}
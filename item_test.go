@@ -0,0 +1,82 @@
+package lexorank
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type task struct {
+	Title string
+}
+
+func TestItem_Payload(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	first := &Item[task]{ID: "a", Payload: task{Title: "write docs"}, Rank: BottomOf(0)}
+	second := &Item[task]{ID: "b", Payload: task{Title: "ship it"}, Rank: TopOf(0)}
+
+	list := DefaultReorderableList([]Reorderable{first, second})
+
+	k, err := list.Insert(1, DefaultConfig())
+	r.NoError(err)
+
+	third := &Item[task]{ID: "c", Payload: task{Title: "review"}}
+	third.SetKey(*k)
+
+	a.Equal(*k, third.GetKey())
+	a.Equal("c", third.GetID())
+	a.True(first.GetKey().Compare(third.GetKey()) < 0)
+	a.True(third.GetKey().Compare(second.GetKey()) < 0)
+	a.Equal("review", third.Payload.Title)
+}
+
+func TestAdaptFunc(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	type row struct{ Rank string }
+	rows := []*row{{Rank: "0|a"}, {Rank: "0|b"}, {Rank: "0|b0"}}
+
+	list := make(ReorderableList, len(rows))
+	for i, rw := range rows {
+		list[i] = AdaptFunc(
+			func() Key { return mustKey(rw.Rank) },
+			func(k Key) { rw.Rank = k.String() },
+		)
+	}
+
+	config := DefaultConfig()
+	config.MaxRankLength = 2
+
+	changed, err := list.Track(func() error {
+		_, err := list.Insert(2, config)
+		return err
+	})
+	r.NoError(err)
+	a.NotEmpty(changed)
+	for _, i := range changed {
+		a.Equal(list[i].GetKey().String(), rows[i].Rank)
+	}
+	a.True(list.StrictlySorted())
+}
+
+func TestItem_JSON(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	it := Item[task]{ID: "a", Rank: mustKey("0|hzzzzz"), Payload: task{Title: "write docs"}}
+
+	data, err := json.Marshal(it)
+	r.NoError(err)
+	a.JSONEq(`{"id":"a","rank":"0|hzzzzz","payload":{"Title":"write docs"}}`, string(data))
+
+	var back Item[task]
+	r.NoError(json.Unmarshal(data, &back))
+	a.Equal(it.ID, back.ID)
+	a.Equal(it.Rank.String(), back.Rank.String())
+	a.Equal(it.Payload, back.Payload)
+}
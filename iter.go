@@ -0,0 +1,53 @@
+package lexorank
+
+import (
+	"iter"
+	"sort"
+)
+
+// All returns an iterator over the index and item of every element of the
+// list, in order.
+func (l ReorderableList) All() iter.Seq2[int, Reorderable] {
+	return func(yield func(int, Reorderable) bool) {
+		for i, it := range l {
+			if !yield(i, it) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the index and item of every element of
+// the list, from the last to the first.
+func (l ReorderableList) Backward() iter.Seq2[int, Reorderable] {
+	return func(yield func(int, Reorderable) bool) {
+		for i := len(l) - 1; i >= 0; i-- {
+			if !yield(i, l[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Range returns an iterator over the index and value of the keys greater
+// than or equal to from and less than to. A zero from or to leaves that end
+// unbounded. The keys must be weakly sorted, as the start is found with a
+// binary search.
+func (ks Keys) Range(from, to Key) iter.Seq2[int, Key] {
+	return func(yield func(int, Key) bool) {
+		start := 0
+		if !from.IsZero() {
+			start = sort.Search(len(ks), func(i int) bool {
+				return ks[i].Compare(from) >= 0
+			})
+		}
+		for i := start; i < len(ks); i++ {
+			if !to.IsZero() && ks[i].Compare(to) >= 0 {
+				return
+			}
+			if !yield(i, ks[i]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReorderableList_All(t *testing.T) {
+	a := assert.New(t)
+
+	l := ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|c")}
+
+	var forward []int
+	for i, it := range l.All() {
+		a.Same(l[i], it)
+		forward = append(forward, i)
+	}
+	a.Equal([]int{0, 1, 2}, forward)
+
+	var backward []int
+	for i := range l.Backward() {
+		backward = append(backward, i)
+		if i == 1 {
+			break
+		}
+	}
+	a.Equal([]int{2, 1}, backward)
+}
+
+func TestKeys_Range(t *testing.T) {
+	a := assert.New(t)
+
+	ks := Keys{mustKey("0|a"), mustKey("0|c"), mustKey("0|e"), mustKey("0|g")}
+
+	collect := func(from, to Key) []string {
+		var got []string
+		for _, k := range ks.Range(from, to) {
+			got = append(got, k.String())
+		}
+		return got
+	}
+
+	a.Equal([]string{"0|c", "0|e"}, collect(mustKey("0|b"), mustKey("0|g")))
+	a.Equal([]string{"0|a", "0|c"}, collect(Key{}, mustKey("0|d")))
+	a.Equal([]string{"0|e", "0|g"}, collect(mustKey("0|e"), Key{}))
+	a.Empty(collect(mustKey("0|h"), Key{}))
+
+	for i, k := range ks.Range(mustKey("0|c"), Key{}) {
+		a.Equal(1, i)
+		a.Equal("0|c", k.String())
+		break
+	}
+}
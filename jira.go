@@ -0,0 +1,135 @@
+package lexorank
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// JiraIntegerLength is the number of rank characters before the ':' marker
+// in Jira's LexoRank format, e.g. "0|hzzzzz:" or "0|i0000f:i".
+const JiraIntegerLength = 6
+
+// JiraConfig returns a configuration producing keys that FormatJiraKey can
+// emit in Jira's format: base-36 ranks padded to JiraIntegerLength, as Jira's
+// integer part has a fixed width. Ranks may grow to 128 characters, so a Jira
+// string takes up to 131 bytes with the marker.
+func JiraConfig() *Config {
+	return DefaultConfig().
+		WithAlphabet(Base36Alphabet).
+		WithMinRankLength(JiraIntegerLength).
+		WithMaxRankLength(128)
+}
+
+// ParseJiraKey parses a rank exported from Jira, such as "0|hzzzzz:" or
+// "0|i0000f:i". The ':' marker is dropped, leaving the integer and decimal
+// parts as one rank that compares the same way, so the key can be used with
+// JiraConfig and turned back into the original string with FormatJiraKey.
+func ParseJiraKey(s string) (*Key, error) {
+	bucket, rank, ok := strings.Cut(s, "|")
+	if !ok {
+		return nil, errors.Errorf("invalid Jira rank %q: missing '|'", s)
+	}
+	integer, decimal, ok := strings.Cut(rank, ":")
+	if !ok || len(integer) != JiraIntegerLength {
+		return nil, errors.Errorf("invalid Jira rank %q: expected %d characters before ':'", s, JiraIntegerLength)
+	}
+	return JiraConfig().ParseKey(bucket + "|" + integer + decimal)
+}
+
+// FormatJiraKey formats k in Jira's format, inserting the ':' marker after
+// the first JiraIntegerLength rank characters. The rank must use
+// Base36Alphabet and be at least JiraIntegerLength characters long, as every
+// key of JiraConfig is.
+func FormatJiraKey(k Key) (string, error) {
+	if len(k.rank) < JiraIntegerLength {
+		return "", errors.Errorf("rank of %s is shorter than Jira's %d characters", k, JiraIntegerLength)
+	}
+	if !Base36Alphabet.Contains(k.rank) {
+		return "", errors.Errorf("rank of %s uses characters outside the alphabet %q", k, Base36Alphabet)
+	}
+	return string(k.raw[:2+JiraIntegerLength]) + ":" + string(k.rank[JiraIntegerLength:]), nil
+}
+
+var (
+	_ encoding.TextMarshaler   = (*JiraKey)(nil)
+	_ encoding.TextUnmarshaler = (*JiraKey)(nil)
+	_ json.Marshaler           = (*JiraKey)(nil)
+	_ json.Unmarshaler         = (*JiraKey)(nil)
+	_ driver.Valuer            = (*JiraKey)(nil)
+	_ sql.Scanner              = (*JiraKey)(nil)
+)
+
+// JiraKey is a Key that is read and written in Jira's format, for columns and
+// documents shared with Jira or tooling that expects its ranks.
+type JiraKey struct {
+	Key
+}
+
+// String returns the key in Jira's format, or the plain key if it cannot be
+// formatted.
+func (k JiraKey) String() string {
+	s, err := FormatJiraKey(k.Key)
+	if err != nil {
+		return k.Key.String()
+	}
+	return s
+}
+
+// TextMarshaler
+func (k JiraKey) MarshalText() ([]byte, error) {
+	s, err := FormatJiraKey(k.Key)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+// TextUnmarshaler
+func (k *JiraKey) UnmarshalText(text []byte) error {
+	parsed, err := ParseJiraKey(string(text))
+	if err != nil {
+		return err
+	}
+	k.Key = *parsed
+	return nil
+}
+
+// JSON Marshaler
+func (k JiraKey) MarshalJSON() ([]byte, error) {
+	s, err := FormatJiraKey(k.Key)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(s)
+}
+
+// JSON Unmarshaler
+func (k *JiraKey) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return k.UnmarshalText([]byte(s))
+}
+
+// SQL Valuer
+func (k JiraKey) Value() (driver.Value, error) {
+	return FormatJiraKey(k.Key)
+}
+
+// SQL Scanner
+func (k *JiraKey) Scan(value any) error {
+	switch v := value.(type) {
+	case string:
+		return k.UnmarshalText([]byte(v))
+	case []byte:
+		return k.UnmarshalText(v)
+	default:
+		return errors.Errorf("cannot scan type %T into JiraKey", value)
+	}
+}
@@ -0,0 +1,94 @@
+package lexorank
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJiraKey_RoundTrip(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	for _, s := range []string{"0|hzzzzz:", "1|i0000f:i", "2|000000:0001"} {
+		k, err := ParseJiraKey(s)
+		r.NoError(err, s)
+		formatted, err := FormatJiraKey(*k)
+		r.NoError(err)
+		a.Equal(s, formatted)
+	}
+
+	k, err := ParseJiraKey("0|i0000f:i")
+	r.NoError(err)
+	a.Equal("0|i0000fi", k.String())
+	a.Equal(uint8(0), k.Bucket())
+
+	for _, s := range []string{"0hzzzzz:", "0|hzzzzz", "0|hzzz:", "0|hzzzzzz:", "0|HZZZZZ:"} {
+		_, err := ParseJiraKey(s)
+		a.Error(err, s)
+	}
+}
+
+func TestJiraKey_Order(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+	config := JiraConfig()
+	r.NoError(config.Validate())
+
+	// Keys generated between Jira ranks sort the same with and without the
+	// marker
+	lo, err := ParseJiraKey("0|hzzzzz:")
+	r.NoError(err)
+	hi, err := ParseJiraKey("0|i00000:")
+	r.NoError(err)
+
+	prev := *lo
+	for range 20 {
+		next, err := Between(prev, *hi, config)
+		r.NoError(err)
+		a.Positive(next.Compare(prev))
+
+		p, err := FormatJiraKey(prev)
+		r.NoError(err)
+		n, err := FormatJiraKey(*next)
+		r.NoError(err)
+		a.Less(p, n)
+		prev = *next
+	}
+
+	// Short keys are padded to the integer width
+	k, err := config.ParseKey("0|h")
+	r.NoError(err)
+	_, err = FormatJiraKey(*k)
+	a.Error(err)
+	mid, err := Between(MinKey(0, config), MaxKey(0, config), config)
+	r.NoError(err)
+	_, err = FormatJiraKey(*mid)
+	a.NoError(err)
+}
+
+func TestJiraKey_Encoding(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	var jk JiraKey
+	r.NoError(jk.Scan("0|hzzzzz:"))
+	a.Equal("0|hzzzzz:", jk.String())
+
+	v, err := jk.Value()
+	r.NoError(err)
+	a.Equal("0|hzzzzz:", v)
+
+	data, err := json.Marshal(struct{ Rank JiraKey }{jk})
+	r.NoError(err)
+	a.JSONEq(`{"Rank": "0|hzzzzz:"}`, string(data))
+
+	var decoded struct{ Rank JiraKey }
+	r.NoError(json.Unmarshal(data, &decoded))
+	a.Equal(0, decoded.Rank.Compare(jk.Key))
+
+	a.Error(jk.Scan(1))
+	a.Error(jk.Scan("0|hzzzzz"))
+}
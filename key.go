@@ -7,6 +7,7 @@ import (
 	"encoding"
 	"encoding/json"
 	"fmt"
+	"math"
 	"math/big"
 	"math/rand"
 	"strconv"
@@ -18,56 +19,67 @@ const (
 	Minimum  = '0'
 	Midpoint = 'U'
 	Maximum  = 'z'
+
+	// DefaultSeparator separates the bucket from the rank in a key's string
+	// form, e.g. "0|aaaaaa".
+	DefaultSeparator = '|'
 )
 
 var (
 	// Default alphabet for encoding positions
 	defaultAlphabet = []byte("0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz")
-
-	// Default base for our character set (75 characters)
-	defaultBase = big.NewInt(75)
 )
 
+// TopOf returns the single character sentinel at the top of a bucket. It is
+// the default upper bound for appends. Keys longer than one character may still
+// sort after it, see MaxKey for the largest key allowed by a Config.
 func TopOf(bucket uint8) Key {
-	rank := []byte{Maximum}
-
-	raw := append([]byte{byte(bucket + '0'), '|'}, rank...)
-
-	return Key{
-		raw:    raw,
-		rank:   rank,
-		bucket: bucket,
-	}
+	return *makeKey(bucket, []byte{Maximum})
 }
 
+// MiddleOf returns the single character key in the middle of a bucket.
 func MiddleOf(bucket uint8) Key {
-	rank := []byte{Midpoint}
+	return *makeKey(bucket, []byte{Midpoint})
+}
 
-	raw := append([]byte{byte(bucket + '0'), '|'}, rank...)
+// BottomOf returns the lowest possible key of a bucket. No key sorts before it
+// regardless of configuration, so it is equal to MinKey.
+func BottomOf(bucket uint8) Key {
+	return *makeKey(bucket, []byte{Minimum})
+}
 
-	return Key{
-		raw:    raw,
-		rank:   rank,
-		bucket: bucket,
-	}
+// MinKey returns the lowest key of a bucket under the given configuration.
+func MinKey(bucket uint8, config *Config) Key {
+	return config.adopt(*makeKey(bucket, []byte{config.alphabet().Min()}))
 }
 
-func BottomOf(bucket uint8) Key {
-	rank := []byte{Minimum}
+// MaxKey returns the highest key of a bucket under the given configuration,
+// which is the maximum character repeated MaxRankLength times. Unlike TopOf,
+// no key within the configured length sorts after it.
+func MaxKey(bucket uint8, config *Config) Key {
+	rank := bytes.Repeat([]byte{config.alphabet().Max()}, max(config.MaxRankLength, 1))
+	return config.adopt(*makeKey(bucket, rank))
+}
 
-	raw := append([]byte{byte(bucket + '0'), '|'}, rank...)
+// topOf, middleOf and bottomOf are the single character sentinels of a bucket
+// for the configured alphabet.
+func topOf(bucket uint8, config *Config) Key {
+	return config.adopt(*makeKey(bucket, []byte{config.alphabet().Max()}))
+}
 
-	return Key{
-		raw:    raw,
-		rank:   rank,
-		bucket: bucket,
-	}
+func middleOf(bucket uint8, config *Config) Key {
+	return config.adopt(*makeKey(bucket, []byte{config.alphabet().Mid()}))
+}
+
+func bottomOf(bucket uint8, config *Config) Key {
+	return config.adopt(*makeKey(bucket, []byte{config.alphabet().Min()}))
 }
 
 type Key struct {
-	raw    []byte // "0|aaaaaa"
-	rank   Rank   // "aaaaaa"
-	bucket uint8  // 0, 1, 2
+	raw      []byte    // "0|aaaaaa"
+	rank     Rank      // "aaaaaa"
+	bucket   uint8     // 0, 1, 2
+	alphabet *Alphabet // nil for the default alphabet
 }
 
 func (k Key) String() string {
@@ -78,8 +90,74 @@ func (k Key) GoString() string {
 	return string(k.raw)
 }
 
+// IsZero reports whether k is the zero Key, which holds no rank at all.
+func (k Key) IsZero() bool {
+	return len(k.raw) == 0
+}
+
+// Compare orders keys by bucket and then by rank. The separator does not take
+// part in the comparison, so keys formatted with different separators still
+// compare as expected.
 func (k Key) Compare(b Key) int {
-	return bytes.Compare(k.raw, b.raw)
+	switch {
+	case k.bucket < b.bucket:
+		return -1
+	case k.bucket > b.bucket:
+		return 1
+	}
+	return bytes.Compare(k.rank, b.rank)
+}
+
+// Bucket returns the bucket the key belongs to.
+func (k Key) Bucket() uint8 {
+	return k.bucket
+}
+
+// RankString returns the rank without the bucket and separator.
+func (k Key) RankString() string {
+	return string(k.rank)
+}
+
+// RankBytes returns a copy of the rank without the bucket and separator.
+func (k Key) RankBytes() []byte {
+	return bytes.Clone(k.rank)
+}
+
+// Separator returns the character between the bucket and the rank.
+func (k Key) Separator() byte {
+	if len(k.raw) < 2 {
+		return DefaultSeparator
+	}
+	return k.raw[1]
+}
+
+// WithSeparator returns a copy of k that is formatted with the given separator
+// between the bucket and the rank.
+func (k Key) WithSeparator(sep byte) Key {
+	if k.IsZero() || k.raw[1] == sep {
+		return k
+	}
+	k.raw = append([]byte{k.raw[0], sep}, k.rank...)
+	k.rank = k.raw[2:]
+	return k
+}
+
+// Alphabet returns the alphabet used for arithmetic on the key's rank. Keys
+// parsed without a Config use Base75Alphabet.
+func (k Key) Alphabet() *Alphabet {
+	if k.alphabet == nil {
+		return Base75Alphabet
+	}
+	return k.alphabet
+}
+
+// withAlphabet returns a copy of k using the given alphabet for arithmetic.
+func (k Key) withAlphabet(a *Alphabet) Key {
+	if a == Base75Alphabet {
+		a = nil
+	}
+	k.alphabet = a
+	return k
 }
 
 func (k *Key) SetBucket(b uint8) {
@@ -91,12 +169,12 @@ func (k *Key) SetBucket(b uint8) {
 
 // ToBigInt converts the key's rank to a big.Int representation
 func (k Key) ToBigInt() *big.Int {
-	return decodeBase75ToBigInt(k.rank)
+	return k.Alphabet().value(k.rank)
 }
 
-// FromBigInt creates a new key from a big.Int value
+// FromBigInt creates a new key from a big.Int value using the default alphabet
 func FromBigInt(bucket uint8, value *big.Int) (*Key, error) {
-	rank := encodeBigIntToBase75(value)
+	rank := Base75Alphabet.encodeMinimal(value)
 	return parseRaw(bucket, rank)
 }
 
@@ -104,7 +182,7 @@ func FromBigInt(bucket uint8, value *big.Int) (*Key, error) {
 func (k Key) Add(distance *big.Int) (*Key, error) {
 	value := k.ToBigInt()
 	result := new(big.Int).Add(value, distance)
-	return FromBigInt(k.bucket, result)
+	return k.derive(result)
 }
 
 // Subtract returns a new key that is the result of subtracting the given distance
@@ -114,14 +192,14 @@ func (k Key) Subtract(distance *big.Int) (*Key, error) {
 	if result.Sign() < 0 {
 		return nil, ErrOutOfBounds
 	}
-	return FromBigInt(k.bucket, result)
+	return k.derive(result)
 }
 
 // Multiply returns a new key that is the result of multiplying by the given factor
 func (k Key) Multiply(factor *big.Int) (*Key, error) {
 	value := k.ToBigInt()
 	result := new(big.Int).Mul(value, factor)
-	return FromBigInt(k.bucket, result)
+	return k.derive(result)
 }
 
 // Divide returns a new key that is the result of dividing by the given divisor
@@ -131,7 +209,18 @@ func (k Key) Divide(divisor *big.Int) (*Key, error) {
 	}
 	value := k.ToBigInt()
 	result := new(big.Int).Div(value, divisor)
-	return FromBigInt(k.bucket, result)
+	return k.derive(result)
+}
+
+// derive creates a key in the same bucket as k, with the same separator and
+// alphabet, from a big.Int value.
+func (k Key) derive(value *big.Int) (*Key, error) {
+	d, err := parseRaw(k.bucket, k.Alphabet().encodeMinimal(value))
+	if err != nil {
+		return nil, err
+	}
+	*d = d.WithSeparator(k.Separator()).withAlphabet(k.alphabet)
+	return d, nil
 }
 
 // Distance returns the distance between this key and another key
@@ -157,7 +246,25 @@ func ParseKey(s string) (*Key, error) {
 
 	rank := []byte(s[2:])
 
-	return parseRaw(uint8(bucket), rank)
+	k, err := parseRaw(uint8(bucket), rank)
+	if err != nil {
+		return nil, err
+	}
+	*k = k.WithSeparator(s[1])
+	return k, nil
+}
+
+// NewKey creates a key from a bucket and rank stored separately, e.g. in two
+// database columns. The rank must only use characters of the default alphabet;
+// use Config.NewKey for other alphabets.
+func NewKey(bucket uint8, rank string) (*Key, error) {
+	if bucket > MaxBucket {
+		return nil, fmt.Errorf("invalid bucket: %d (maximum %d)", bucket, MaxBucket)
+	}
+	if !Base75Alphabet.Contains([]byte(rank)) {
+		return nil, fmt.Errorf("invalid rank: %q", rank)
+	}
+	return parseRaw(bucket, []byte(rank))
 }
 
 func parseRaw(bucket uint8, rank []byte) (*Key, error) {
@@ -171,7 +278,7 @@ func parseRaw(bucket uint8, rank []byte) (*Key, error) {
 		}
 	}
 
-	raw := append([]byte{byte(bucket + 48), '|'}, rank...)
+	raw := append([]byte{byte(bucket + 48), DefaultSeparator}, rank...)
 
 	return &Key{
 		raw:    raw,
@@ -184,16 +291,17 @@ func parseRaw(bucket uint8, rank []byte) (*Key, error) {
 func KeyAt(bucket uint8, f float64, config *Config) (Key, error) {
 	bucketChar := byte(bucket + 48)
 
-	base := float64(len(defaultAlphabet)) // 75
+	alphabet := config.alphabet()
+	base := float64(alphabet.Len())
 	key := make([]byte, 0, config.MaxRankLength)
 
 	for i := 0; i < config.MaxRankLength; i++ {
 		f *= base
 		index := int(f)
-		if index >= len(defaultAlphabet) {
-			index = len(defaultAlphabet) - 1
+		if index >= alphabet.Len() {
+			index = alphabet.Len() - 1
 		}
-		key = append(key, defaultAlphabet[index])
+		key = append(key, alphabet.chars[index])
 		f -= float64(index)
 
 		if f <= 0.0 {
@@ -201,31 +309,68 @@ func KeyAt(bucket uint8, f float64, config *Config) (Key, error) {
 		}
 	}
 
-	k, err := ParseKey(string(append([]byte{bucketChar, '|'}, key...)))
+	k, err := ParseKey(string(append([]byte{bucketChar, config.separator()}, key...)))
 	if err != nil {
 		return Key{}, err
 	}
 
+	*k = config.adopt(*k)
+	config.padRank(k)
 	return *k, nil
 }
 
 // Between returns a new key between two keys.
+//
+// Either side may be the zero Key to leave it open: a zero lhs means the
+// bottom of rhs's bucket and a zero rhs means the top of lhs's bucket, as
+// for SmartPrepend and SmartAppend, so the result stays short. A lhs above
+// the single character top of its bucket runs up to MaxKey instead. If both are
+// zero the middle of bucket 0 is returned.
 func Between(lhs, rhs Key, config *Config) (*Key, error) {
+	config.metrics().BetweenCalled()
+	k, err := between(lhs, rhs, config)
+	if err != nil {
+		return nil, err
+	}
+	config.padRank(k)
+	config.checkRankLength(k)
+	return k, nil
+}
+
+func between(lhs, rhs Key, config *Config) (*Key, error) {
+	switch {
+	case lhs.IsZero() && rhs.IsZero():
+		lhs, rhs = bottomOf(0, config), topOf(0, config)
+	case lhs.IsZero():
+		lhs = bottomOf(rhs.bucket, config)
+	case rhs.IsZero():
+		rhs = topOf(lhs.bucket, config)
+		if lhs.Compare(rhs) >= 0 {
+			// lhs already starts with the largest character
+			rhs = MaxKey(lhs.bucket, config)
+		}
+	}
+
 	// Ensure both keys are in the same bucket
 	if lhs.bucket != rhs.bucket {
 		return nil, fmt.Errorf("keys must be in the same bucket")
 	}
 
-	// Parse the rank digits in base-B (75)
-	sa := suffixDigits(lhs.rank)
-	sb := suffixDigits(rhs.rank)
+	alphabet := config.alphabet()
+	if !alphabet.Contains(lhs.rank) || !alphabet.Contains(rhs.rank) {
+		return nil, fmt.Errorf("keys must only use characters from the alphabet %q", alphabet)
+	}
+
+	if config.Watermark != 0 {
+		return betweenWatermarked(lhs, rhs, config)
+	}
 
 	// Determine the minimum length to work with
-	L := max(len(sa), len(sb), 1) // At least 1 digit
+	L := max(len(lhs.rank), len(rhs.rank), 1) // At least 1 digit
 
 	// Convert to big.Int in base-B and scale to same length
-	na := scaleUpTo(toBigIntBaseB(sa), L)
-	nb := scaleUpTo(toBigIntBaseB(sb), L)
+	na := alphabet.scale(lhs.rank, L)
+	nb := alphabet.scale(rhs.rank, L)
 
 	// Ensure proper ordering
 	if na.Cmp(nb) >= 0 {
@@ -241,7 +386,12 @@ func Between(lhs, rhs Key, config *Config) (*Key, error) {
 		// Check if this midpoint is strictly between na and nb
 		if mid.Cmp(na) > 0 && mid.Cmp(nb) < 0 {
 			// We found a valid midpoint, encode it back to base-B
-			return makeKey(lhs.bucket, encodeBaseB(mid, L)), nil
+			rank := alphabet.encode(mid, L)
+			if config.TrimTrailingMinimum {
+				rank = alphabet.trimTrailingMinimum(rank)
+			}
+			k := config.adopt(*makeKey(lhs.bucket, rank))
+			return &k, nil
 		}
 
 		// No integer strictly between at this precision, add one digit
@@ -249,13 +399,50 @@ func Between(lhs, rhs Key, config *Config) (*Key, error) {
 			return nil, ErrRebalanceRequired
 		}
 
-		// Scale up by base (75) and try again
-		L++
-		na.Mul(na, defaultBase)
-		nb.Mul(nb, defaultBase)
+		// Scale up by base per added digit and try again
+		grow := config.growthDigits()
+		if config.MaxRankLength > 0 {
+			grow = min(grow, config.MaxRankLength-L)
+		}
+		scale := alphabet.space(grow)
+		L += grow
+		na.Mul(na, scale)
+		nb.Mul(nb, scale)
 	}
 }
 
+// Next returns the key immediately after k among keys of the same rank length,
+// e.g. "0|ab" becomes "0|ac" and "0|az" becomes "0|b0". Unlike After, the
+// length is kept so the result always sorts after k. Longer keys such as
+// "0|ab0" still fit in between. It returns ErrOutOfBounds if k is the largest
+// key of its length.
+func (k Key) Next() (*Key, error) {
+	return k.shift(1)
+}
+
+// Prev returns the key immediately before k among keys of the same rank
+// length. It returns ErrOutOfBounds if k is the smallest key of its length.
+func (k Key) Prev() (*Key, error) {
+	return k.shift(-1)
+}
+
+// shift adds delta to the rank at its current length.
+func (k Key) shift(delta int64) (*Key, error) {
+	alphabet := k.Alphabet()
+	v := alphabet.value(k.rank)
+	v.Add(v, big.NewInt(delta))
+	if v.Sign() < 0 || v.Cmp(alphabet.space(len(k.rank))) >= 0 {
+		return nil, ErrOutOfBounds
+	}
+
+	n, err := parseRaw(k.bucket, alphabet.encode(v, len(k.rank)))
+	if err != nil {
+		return nil, err
+	}
+	*n = n.WithSeparator(k.Separator()).withAlphabet(k.alphabet)
+	return n, nil
+}
+
 func (k Key) After(distance int64) (*Key, error) {
 	step := big.NewInt(distance)
 	return k.Add(step)
@@ -266,18 +453,29 @@ func (k Key) Before(distance int64) (*Key, error) {
 	return k.Subtract(step)
 }
 
-func mid(a, b byte) (byte, bool) {
-	if a == b {
-		return a, false
+// MidChar returns the character halfway between a and b in the given
+// alphabet. It works on alphabet indices rather than raw byte values, so it is
+// correct for any sorted alphabet and cannot overflow. A nil alphabet means the
+// default alphabet. It reports false if a and b are equal or adjacent, or if
+// either is not part of the alphabet.
+func MidChar(a, b byte, alphabet []byte) (byte, bool) {
+	if alphabet == nil {
+		alphabet = defaultAlphabet
 	}
 
-	m := (a + b) / 2
-
-	if m == a || m == b {
+	ia := bytes.IndexByte(alphabet, a)
+	ib := bytes.IndexByte(alphabet, b)
+	if ia == -1 || ib == -1 {
+		return a, false
+	}
+	if ia > ib {
+		ia, ib = ib, ia
+	}
+	if ib-ia < 2 {
 		return a, false
 	}
 
-	return m, true
+	return alphabet[(ia+ib)/2], true
 }
 
 func decodeBase75(rank []byte) (int64, error) {
@@ -307,138 +505,140 @@ func encodeBase75(val int64) []byte {
 	return out
 }
 
-// decodeBase75ToBigInt converts a base75 rank string to a big.Int
-func decodeBase75ToBigInt(rank []byte) *big.Int {
-	base := big.NewInt(int64(len(defaultAlphabet)))
-	result := big.NewInt(0)
-
-	for _, c := range rank {
-		pos := int64(bytes.IndexByte(defaultAlphabet, c))
-		if pos == -1 {
-			return big.NewInt(0) // Invalid character, return 0
-		}
-		result.Mul(result, base)
-		result.Add(result, big.NewInt(pos))
+// makeKey creates a new Key from bucket and rank
+func makeKey(bucket uint8, rank []byte) *Key {
+	raw := append([]byte{byte(bucket + '0'), DefaultSeparator}, rank...)
+	return &Key{
+		raw:    raw,
+		rank:   rank,
+		bucket: bucket,
 	}
-
-	return result
 }
 
-// encodeBigIntToBase75 converts a big.Int to a base75 rank string
-func encodeBigIntToBase75(val *big.Int) []byte {
-	if val.Sign() == 0 {
-		return []byte{defaultAlphabet[0]}
-	}
-
-	base := big.NewInt(int64(len(defaultAlphabet)))
-	zero := big.NewInt(0)
+// SmartAppend generates a new key for appending using the specified strategy
+func SmartAppend(last Key, config *Config) (*Key, error) {
+	return smartAppend(nil, last, config)
+}
 
-	var out []byte
-	temp := new(big.Int).Set(val)
-
-	for temp.Cmp(zero) > 0 {
-		rem := new(big.Int)
-		temp.DivMod(temp, base, rem)
-		remInt := int(rem.Int64())
-		if remInt >= len(defaultAlphabet) {
-			remInt = len(defaultAlphabet) - 1
+// smartAppend is SmartAppend for a list whose second to last key is prev, if
+// any, which AppendStrategyGeometric grows the gap from.
+func smartAppend(prev *Key, last Key, config *Config) (*Key, error) {
+	switch config.AppendStrategy {
+	case AppendStrategyDefault:
+		return Between(last, topOf(last.bucket, config), config)
+	case AppendStrategyStep:
+		step := big.NewInt(config.StepSize)
+		k, err := config.adopt(last).Add(step)
+		config.padRank(k)
+		config.checkRankLength(k)
+		return k, err
+	case AppendStrategyAdaptive:
+		if k := adaptiveStep(last, 1, config.StepSize, config); k != nil {
+			return k, nil
+		}
+		return Between(last, topOf(last.bucket, config), config)
+	case AppendStrategyGeometric:
+		step := geometricStep(prev, last, config.StepSize, config)
+		if k := adaptiveStep(last, 1, step, config); k != nil {
+			return k, nil
 		}
-		out = append([]byte{defaultAlphabet[remInt]}, out...)
+		return Between(last, topOf(last.bucket, config), config)
+	default:
+		return Between(last, topOf(last.bucket, config), config)
 	}
+}
 
-	return out
+// SmartPrepend generates a new key for prepending using the specified
+// strategy, PrependStrategy if set and AppendStrategy otherwise
+func SmartPrepend(first Key, config *Config) (*Key, error) {
+	return smartPrepend(first, nil, config)
 }
 
-// suffixDigits converts a rank string to a slice of digit indices in base-B
-func suffixDigits(rank []byte) []int {
-	digits := make([]int, len(rank))
-	for i, char := range rank {
-		digits[i] = bytes.IndexByte(defaultAlphabet, char)
-		if digits[i] == -1 {
-			digits[i] = 0 // Default to 0 for invalid characters
+// smartPrepend is SmartPrepend for a list whose second key is next, if any,
+// which AppendStrategyGeometric grows the gap from.
+func smartPrepend(first Key, next *Key, config *Config) (*Key, error) {
+	switch config.prependStrategy() {
+	case AppendStrategyDefault:
+		return Between(bottomOf(first.bucket, config), first, config)
+	case AppendStrategyStep:
+		step := big.NewInt(config.prependStepSize())
+		k, err := config.adopt(first).Subtract(step)
+		config.padRank(k)
+		config.checkRankLength(k)
+		return k, err
+	case AppendStrategyAdaptive:
+		if k := adaptiveStep(first, -1, config.prependStepSize(), config); k != nil {
+			return k, nil
+		}
+		return Between(bottomOf(first.bucket, config), first, config)
+	case AppendStrategyGeometric:
+		step := geometricStep(next, first, config.prependStepSize(), config)
+		if k := adaptiveStep(first, -1, step, config); k != nil {
+			return k, nil
 		}
+		return Between(bottomOf(first.bucket, config), first, config)
+	default:
+		return Between(bottomOf(first.bucket, config), first, config)
 	}
-	return digits
 }
 
-// toBigIntBaseB converts a slice of base-B digits to a big.Int
-func toBigIntBaseB(digits []int) *big.Int {
-	result := big.NewInt(0)
-	base := big.NewInt(75)
+// adaptiveStep returns the key step after k, for a positive direction, or
+// before it, keeping k's rank length. The step is capped at half of the room
+// left between k and the top or bottom of the bucket at that length, and a
+// step of zero or less always takes half of it. It returns nil once there is
+// no room left at that length.
+func adaptiveStep(k Key, direction int, step int64, config *Config) *Key {
+	alphabet := config.alphabet()
+	length := len(k.rank)
+	v := alphabet.value(k.rank)
 
-	for _, digit := range digits {
-		result.Mul(result, base)
-		result.Add(result, big.NewInt(int64(digit)))
+	// The distance to the bottom or the top of the bucket at this length
+	room := new(big.Int).Set(v)
+	if direction > 0 {
+		room.Sub(alphabet.scale([]byte{alphabet.Max()}, length), v)
 	}
-
-	return result
-}
-
-// scaleUpTo scales a big.Int to a specific length by multiplying by base^(targetLength - currentLength)
-func scaleUpTo(val *big.Int, targetLength int) *big.Int {
-	currentLength := len(encodeBigIntToBase75(val))
-	if currentLength >= targetLength {
-		return new(big.Int).Set(val)
+	half := room.Rsh(room, 1)
+	if half.Sign() <= 0 {
+		return nil
 	}
 
-	scaleFactor := new(big.Int).Exp(defaultBase, big.NewInt(int64(targetLength-currentLength)), nil)
-	return new(big.Int).Mul(val, scaleFactor)
-}
-
-// encodeBaseB encodes a big.Int to a base-B string of specified length
-func encodeBaseB(val *big.Int, length int) []byte {
-	base := big.NewInt(75)
-
-	var out []byte
-	temp := new(big.Int).Set(val)
-
-	for range length {
-		rem := new(big.Int)
-		temp.DivMod(temp, base, rem)
-		remInt := int(rem.Int64())
-		if remInt >= len(defaultAlphabet) {
-			remInt = len(defaultAlphabet) - 1
-		}
-		out = append([]byte{defaultAlphabet[remInt]}, out...)
+	d := half
+	if step > 0 && big.NewInt(step).Cmp(half) < 0 {
+		d = big.NewInt(step)
+	}
+	if direction < 0 {
+		d.Neg(d)
 	}
 
-	return out
+	n := config.adopt(*makeKey(k.bucket, alphabet.encode(v.Add(v, d), length)))
+	config.padRank(&n)
+	config.checkRankLength(&n)
+	return &n
 }
 
-// makeKey creates a new Key from bucket and rank
-func makeKey(bucket uint8, rank []byte) *Key {
-	raw := append([]byte{byte(bucket + '0'), '|'}, rank...)
-	return &Key{
-		raw:    raw,
-		rank:   rank,
-		bucket: bucket,
+// geometricStep returns twice the gap between k and its neighbour at k's rank
+// length, capped at MaxStepSize, or step if there is no neighbour of the same
+// length to measure from.
+func geometricStep(neighbour *Key, k Key, step int64, config *Config) int64 {
+	if neighbour == nil || len(neighbour.rank) != len(k.rank) || neighbour.bucket != k.bucket {
+		return step
 	}
-}
 
-// SmartAppend generates a new key for appending using the specified strategy
-func SmartAppend(last Key, config *Config) (*Key, error) {
-	switch config.AppendStrategy {
-	case AppendStrategyDefault:
-		return Between(last, TopOf(last.bucket), config)
-	case AppendStrategyStep:
-		step := big.NewInt(config.StepSize)
-		return last.Add(step)
-	default:
-		return Between(last, TopOf(last.bucket), config)
+	alphabet := config.alphabet()
+	gap := new(big.Int).Sub(alphabet.value(k.rank), alphabet.value(neighbour.rank))
+	gap.Abs(gap).Lsh(gap, 1)
+	if gap.Cmp(big.NewInt(step)) < 0 {
+		gap.SetInt64(step)
 	}
-}
 
-// SmartPrepend generates a new key for prepending using the specified strategy
-func SmartPrepend(first Key, config *Config) (*Key, error) {
-	switch config.AppendStrategy {
-	case AppendStrategyDefault:
-		return Between(BottomOf(first.bucket), first, config)
-	case AppendStrategyStep:
-		step := big.NewInt(config.StepSize)
-		return first.Subtract(step)
-	default:
-		return Between(BottomOf(first.bucket), first, config)
+	limit := big.NewInt(math.MaxInt64)
+	if config.MaxStepSize > 0 {
+		limit.SetInt64(config.MaxStepSize)
+	}
+	if gap.Cmp(limit) > 0 {
+		return limit.Int64()
 	}
+	return gap.Int64()
 }
 
 func Random(config *Config) (Key, error) {
@@ -294,3 +294,364 @@ func TestBetween_OrderIndependent(t *testing.T) {
 		t.Errorf("Between result should be strictly between a and b, got %s", forward.String())
 	}
 }
+
+func TestKey_Between_TrimTrailingMinimum(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	lhs, err := ParseKey("0|az")
+	r.NoError(err)
+	rhs, err := ParseKey("0|b1")
+	r.NoError(err)
+
+	untrimmed, err := Between(*lhs, *rhs, DefaultConfig())
+	r.NoError(err)
+	a.Equal("0|b0", untrimmed.String())
+
+	trimmed, err := Between(*lhs, *rhs, DefaultConfig().WithTrimTrailingMinimum(true))
+	r.NoError(err)
+	a.Equal("0|b", trimmed.String())
+	a.True(trimmed.Compare(*lhs) > 0)
+	a.True(trimmed.Compare(*rhs) < 0)
+}
+
+func TestKey_Sentinels(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal("0|0", BottomOf(0).String())
+	a.Equal("1|U", MiddleOf(1).String())
+	a.Equal("2|z", TopOf(2).String())
+
+	a.Equal(BottomOf(1).String(), MinKey(1, DefaultConfig()).String())
+	a.Equal("1|zzzzzz", MaxKey(1, DefaultConfig()).String())
+	a.Equal("1|zz", MaxKey(1, DefaultConfig().WithMaxRankLength(2)).String())
+
+	// Ordering between the sentinels holds for any config
+	cfg := ProductionConfig()
+	a.True(MinKey(0, cfg).Compare(MiddleOf(0)) < 0)
+	a.True(MiddleOf(0).Compare(TopOf(0)) < 0)
+	a.True(TopOf(0).Compare(MaxKey(0, cfg)) < 0)
+}
+
+func TestKey_Between_GrowthPolicy(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	lhs, err := ParseKey("0|a")
+	r.NoError(err)
+	rhs, err := ParseKey("0|b")
+	r.NoError(err)
+
+	minimal, err := Between(*lhs, *rhs, DefaultConfig())
+	r.NoError(err)
+	a.Equal("0|aU", minimal.String())
+
+	jump, err := Between(*lhs, *rhs, DefaultConfig().WithGrowthPolicy(GrowthPolicyJump).WithGrowthJump(2))
+	r.NoError(err)
+	a.Equal("0|aUU", jump.String())
+
+	// The jump never exceeds MaxRankLength
+	capped, err := Between(*lhs, *rhs, DefaultConfig().WithGrowthPolicy(GrowthPolicyJump).WithGrowthJump(10).WithMaxRankLength(3))
+	r.NoError(err)
+	a.Len(capped.rank, 3)
+}
+
+func TestKey_Between_LeadingMinimum(t *testing.T) {
+	r := require.New(t)
+
+	lhs, err := ParseKey("0|0u1LWZ")
+	r.NoError(err)
+	rhs, err := ParseKey("0|1LWZkJ")
+	r.NoError(err)
+
+	got, err := Between(*lhs, *rhs, DefaultConfig())
+	r.NoError(err)
+	r.True(got.Compare(*lhs) > 0)
+	r.True(got.Compare(*rhs) < 0)
+}
+
+func TestMidChar(t *testing.T) {
+	a := assert.New(t)
+
+	m, ok := MidChar('0', 'z', nil)
+	a.True(ok)
+	a.Equal(byte('U'), m)
+
+	// Index based, so order doesn't matter and 'y'/'z' cannot overflow
+	m, ok = MidChar('z', 'x', nil)
+	a.True(ok)
+	a.Equal(byte('y'), m)
+
+	_, ok = MidChar('y', 'z', nil)
+	a.False(ok, "adjacent characters have no midpoint")
+
+	_, ok = MidChar('a', 'a', nil)
+	a.False(ok)
+
+	_, ok = MidChar('a', '~', nil)
+	a.False(ok, "characters outside the alphabet are rejected")
+
+	m, ok = MidChar('a', 'e', []byte("abcde"))
+	a.True(ok)
+	a.Equal(byte('c'), m)
+}
+
+func TestKey_Between_OpenEnded(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	k := mustKey("1|zU")
+
+	after, err := Between(k, Key{}, DefaultConfig())
+	r.NoError(err)
+	a.True(after.Compare(k) > 0)
+	a.Equal(uint8(1), after.bucket)
+
+	before, err := Between(Key{}, k, DefaultConfig())
+	r.NoError(err)
+	a.True(before.Compare(k) < 0)
+	a.True(before.Compare(BottomOf(1)) > 0)
+
+	mid, err := Between(Key{}, Key{}, DefaultConfig())
+	r.NoError(err)
+	a.Equal(MiddleOf(0).String(), mid.String())
+
+	// Open sides stay short instead of splitting the full length key space
+	for _, config := range []*Config{DefaultConfig(), ProductionConfig()} {
+		after, err = Between(mustKey("0|a"), Key{}, config)
+		r.NoError(err)
+		a.Equal("0|m", after.String())
+
+		before, err = Between(Key{}, mustKey("0|a"), config)
+		r.NoError(err)
+		a.Len(before.String(), 3)
+	}
+
+	// The top of the bucket is exhausted at the configured length
+	_, err = Between(MaxKey(1, DefaultConfig()), Key{}, DefaultConfig())
+	a.Error(err)
+
+	a.True(Key{}.IsZero())
+	a.False(k.IsZero())
+}
+
+func TestKey_Separator(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	c := DefaultConfig().WithSeparator(':')
+
+	k1, err := c.ParseKey("0:a")
+	r.NoError(err)
+	k2, err := c.ParseKey("0:c")
+	r.NoError(err)
+	a.Equal(byte(':'), k1.Separator())
+
+	mid, err := Between(*k1, *k2, c)
+	r.NoError(err)
+	a.Equal("0:b", mid.String())
+
+	next, err := k1.After(1)
+	r.NoError(err)
+	a.Equal("0:b", next.String())
+
+	_, err = c.ParseKey("0|a")
+	a.Error(err)
+
+	// The separator is not part of the ordering
+	pipe, err := ParseKey("0|b")
+	r.NoError(err)
+	a.Equal(0, pipe.Compare(*mid))
+	a.Equal(*mid, pipe.WithSeparator(':'))
+	a.Equal(byte(DefaultSeparator), MinKey(0, DefaultConfig()).Separator())
+	a.Equal("0-z", MaxKey(0, DefaultConfig().WithMaxRankLength(1).WithSeparator('-')).String())
+}
+
+func TestKey_Accessors(t *testing.T) {
+	a := assert.New(t)
+
+	k := mustKey("1|aU0")
+	a.Equal(uint8(1), k.Bucket())
+	a.Equal("aU0", k.RankString())
+	a.Equal([]byte("aU0"), k.RankBytes())
+
+	// RankBytes returns a copy
+	b := k.RankBytes()
+	b[0] = 'z'
+	a.Equal("1|aU0", k.String())
+}
+
+func TestNewKey(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	k, err := NewKey(1, "aU0")
+	r.NoError(err)
+	a.Equal(mustKey("1|aU0"), *k)
+
+	_, err = NewKey(1, "")
+	a.Error(err)
+	_, err = NewKey(1, "a|b")
+	a.Error(err)
+	_, err = NewKey(10, "a")
+	a.Error(err)
+
+	k, err = CaseInsensitiveConfig().WithSeparator(':').NewKey(0, "ab")
+	r.NoError(err)
+	a.Equal("0:ab", k.String())
+	a.Equal(Base36Alphabet, k.Alphabet())
+
+	_, err = CaseInsensitiveConfig().NewKey(0, "aB")
+	a.Error(err)
+}
+
+func TestKey_NextPrev(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	k := mustKey("0|az")
+	next, err := k.Next()
+	r.NoError(err)
+	a.Equal("0|b0", next.String())
+	a.Equal(1, next.Compare(k))
+
+	prev, err := next.Prev()
+	r.NoError(err)
+	a.Equal(k, *prev)
+
+	prev, err = mustKey("0|a0").Prev()
+	r.NoError(err)
+	a.Equal("0|`z", prev.String())
+
+	_, err = mustKey("0|zz").Next()
+	a.ErrorIs(err, ErrOutOfBounds)
+	_, err = mustKey("0|00").Prev()
+	a.ErrorIs(err, ErrOutOfBounds)
+
+	// The alphabet and separator are kept
+	b36, err := CaseInsensitiveConfig().WithSeparator(':').ParseKey("0:9z")
+	r.NoError(err)
+	next, err = b36.Next()
+	r.NoError(err)
+	a.Equal("0:a0", next.String())
+}
+
+func TestBetween_WarnRankLength(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	var warned []string
+	config := DefaultConfig().WithWarnRankLength(3, func(k Key) { warned = append(warned, k.String()) })
+
+	_, err := Between(mustKey("0|a"), mustKey("0|c"), config)
+	r.NoError(err)
+	a.Empty(warned)
+
+	k, err := Between(mustKey("0|aa"), mustKey("0|ab"), config)
+	r.NoError(err)
+	a.Equal([]string{k.String()}, warned)
+
+	step := config.WithAppendStrategy(AppendStrategyStep)
+	k, err = SmartAppend(mustKey("0|aaa"), step)
+	r.NoError(err)
+	a.Equal(k.String(), warned[len(warned)-1])
+}
+
+func TestSmartPrepend_PrependStrategy(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// Without a PrependStrategy, prepends follow the append strategy
+	config := DefaultConfig().WithAppendStrategy(AppendStrategyStep).WithStepSize(2)
+	k, err := SmartPrepend(mustKey("0|d"), config)
+	r.NoError(err)
+	a.Equal("0|b", k.String())
+
+	k, err = SmartPrepend(mustKey("0|d"), config.WithPrependStepSize(3))
+	r.NoError(err)
+	a.Equal("0|a", k.String())
+
+	// Appends step while prepends halve the remaining room
+	mixed := config.WithPrependStrategy(AppendStrategyDefault)
+	k, err = SmartPrepend(mustKey("0|d"), mixed)
+	r.NoError(err)
+	a.Equal("0|J", k.String())
+
+	k, err = SmartAppend(mustKey("0|d"), mixed)
+	r.NoError(err)
+	a.Equal("0|f", k.String())
+}
+
+func TestSmartAppend_Adaptive(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig().WithAppendStrategy(AppendStrategyAdaptive).WithStepSize(10)
+
+	// Far from the ceiling, appends step like AppendStrategyStep
+	k, err := SmartAppend(mustKey("0|a0"), config)
+	r.NoError(err)
+	a.Equal("0|a:", k.String())
+
+	// Close to it, they take half of what is left
+	k, err = SmartAppend(mustKey("0|yp"), config)
+	r.NoError(err)
+	a.Equal("0|yu", k.String())
+
+	// With no room at the current length, the rank grows
+	k, err = SmartAppend(mustKey("0|yz"), config)
+	r.NoError(err)
+	a.Greater(len(k.rank), 2)
+	a.Greater(k.Compare(mustKey("0|yz")), 0)
+
+	// Repeated appends slow down instead of stepping past the top
+	last := mustKey("0|a")
+	for range 30 {
+		next, err := SmartAppend(last, config)
+		r.NoError(err)
+		r.Greater(next.Compare(last), 0)
+		r.Less(next.Compare(TopOf(0)), 0)
+		last = *next
+	}
+
+	k, err = SmartPrepend(mustKey("0|05"), config)
+	r.NoError(err)
+	a.Equal("0|03", k.String())
+}
+
+func TestConfig_MinRankLength(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig().WithMinRankLength(4)
+	r.NoError(config.Validate())
+
+	k, err := Between(mustKey("0|a"), mustKey("0|c"), config)
+	r.NoError(err)
+	a.Equal("0|b000", k.String())
+
+	// Padding doesn't move the key out of its gap
+	k, err = Between(mustKey("0|a"), mustKey("0|b"), config)
+	r.NoError(err)
+	a.Len(k.rank, 4)
+	a.Equal(1, k.Compare(mustKey("0|a")))
+	a.Equal(-1, k.Compare(mustKey("0|b")))
+
+	kk, err := KeyAt(0, 0.25, config)
+	r.NoError(err)
+	a.GreaterOrEqual(len(kk.rank), 4)
+
+	keys, err := Spread(0, 3, config)
+	r.NoError(err)
+	for _, k := range keys {
+		a.Len(k.rank, 4)
+	}
+
+	empty := ReorderableList{}
+	first, err := empty.Append(config)
+	r.NoError(err)
+	a.Equal("0|0000", first.String())
+
+	a.ErrorContains(config.WithTrimTrailingMinimum(true).Validate(), "TrimTrailingMinimum")
+	a.ErrorContains(config.WithMaxRankLength(3).Validate(), "exceeds MaxRankLength")
+}
@@ -0,0 +1,146 @@
+// Package lexorankbench provides reusable workload drivers for measuring key
+// generation performance of a lexorank Config. Results are returned as values
+// rather than printed, so configurations and algorithm changes can be compared
+// programmatically on the hardware they will run on.
+package lexorankbench
+
+import (
+	"math/rand"
+	"runtime"
+	"time"
+
+	"github.com/ntauth/lexorank"
+)
+
+// Workload decides where each operation inserts into the list.
+type Workload struct {
+	// Name identifies the workload in results
+	Name string
+
+	// Position returns the insert position for the i-th operation given the
+	// current list length. It must be in the range [0, length].
+	Position func(i, length int) uint
+}
+
+// SequentialAppend always inserts at the end of the list.
+func SequentialAppend() Workload {
+	return Workload{
+		Name: "sequential-append",
+		Position: func(_, length int) uint {
+			return uint(length)
+		},
+	}
+}
+
+// RandomInsert inserts at uniformly random positions using the given seed, so
+// runs are reproducible.
+func RandomInsert(seed int64) Workload {
+	r := rand.New(rand.NewSource(seed))
+	return Workload{
+		Name: "random-insert",
+		Position: func(_, length int) uint {
+			return uint(r.Intn(length + 1))
+		},
+	}
+}
+
+// HotGap repeatedly inserts right after the first item, exhausting the same
+// gap over and over. This is the worst case for key growth.
+func HotGap() Workload {
+	return Workload{
+		Name: "hot-gap",
+		Position: func(_, length int) uint {
+			return uint(min(1, length))
+		},
+	}
+}
+
+// Result holds the measurements of a single run.
+type Result struct {
+	Workload string
+	Ops      int
+
+	Duration  time.Duration
+	OpsPerSec float64
+
+	// Allocs and Bytes are heap allocations made during the run
+	Allocs uint64
+	Bytes  uint64
+
+	// Normalizations counts how many times an insert failed and the list had
+	// to be normalised before retrying
+	Normalizations int
+
+	// Failures counts inserts that failed even after normalising
+	Failures int
+
+	// MaxRankLength is the longest rank present in the final list
+	MaxRankLength int
+
+	// Sorted reports whether the final list is strictly sorted
+	Sorted bool
+}
+
+// Run performs ops inserts driven by the workload against an initially empty
+// list using the given configuration.
+func Run(w Workload, ops int, config *lexorank.Config) Result {
+	list := make(lexorank.ReorderableList, 0, ops)
+	res := Result{Workload: w.Name, Ops: ops}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	for i := 0; i < ops; i++ {
+		pos := w.Position(i, len(list))
+
+		key, err := list.Insert(pos, config)
+		if err != nil {
+			res.Normalizations++
+			if _, nerr := list.Normalize(config); nerr == nil {
+				key, err = list.Insert(pos, config)
+			}
+		}
+		if err != nil {
+			res.Failures++
+			continue
+		}
+
+		list = append(list, nil)
+		copy(list[pos+1:], list[pos:])
+		list[pos] = &lexorank.Item[struct{}]{Rank: *key}
+	}
+
+	res.Duration = time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	res.Allocs = after.Mallocs - before.Mallocs
+	res.Bytes = after.TotalAlloc - before.TotalAlloc
+	if res.Duration > 0 {
+		res.OpsPerSec = float64(ops) / res.Duration.Seconds()
+	}
+	for _, it := range list {
+		res.MaxRankLength = max(res.MaxRankLength, len(it.GetKey().String())-2)
+	}
+	res.Sorted = list.StrictlySorted()
+
+	return res
+}
+
+// Compare runs every workload against every configuration and returns the
+// results grouped by configuration, in the order given.
+func Compare(workloads func() []Workload, ops int, configs ...*lexorank.Config) [][]Result {
+	results := make([][]Result, len(configs))
+	for i, config := range configs {
+		for _, w := range workloads() {
+			results[i] = append(results[i], Run(w, ops, config))
+		}
+	}
+	return results
+}
+
+// Standard returns the built-in workloads with a fixed seed.
+func Standard() []Workload {
+	return []Workload{SequentialAppend(), RandomInsert(42), HotGap()}
+}
@@ -0,0 +1,46 @@
+package lexorankbench_test
+
+import (
+	"testing"
+
+	"github.com/ntauth/lexorank"
+	"github.com/ntauth/lexorank/lexorankbench"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun_RandomInsert(t *testing.T) {
+	a := assert.New(t)
+
+	config := lexorank.DefaultConfig()
+	res := lexorankbench.Run(lexorankbench.RandomInsert(42), 200, config)
+
+	a.Equal("random-insert", res.Workload)
+	a.Equal(200, res.Ops)
+	a.Zero(res.Failures)
+	a.True(res.Sorted)
+	a.NotZero(res.Allocs)
+	a.Positive(res.OpsPerSec)
+	a.LessOrEqual(res.MaxRankLength, config.MaxRankLength)
+}
+
+func TestRun_HotGapNormalizes(t *testing.T) {
+	a := assert.New(t)
+
+	res := lexorankbench.Run(lexorankbench.HotGap(), 200, lexorank.DefaultConfig())
+
+	a.Positive(res.Normalizations, "a six character rank cannot absorb 200 inserts into one gap")
+	a.Zero(res.Failures)
+	a.True(res.Sorted)
+}
+
+func TestCompare(t *testing.T) {
+	results := lexorankbench.Compare(lexorankbench.Standard, 50, lexorank.DefaultConfig(), lexorank.ProductionConfig())
+
+	assert.Len(t, results, 2)
+	for _, byConfig := range results {
+		assert.Len(t, byConfig, len(lexorankbench.Standard()))
+		for _, res := range byConfig {
+			assert.Equal(t, 50, res.Ops)
+		}
+	}
+}
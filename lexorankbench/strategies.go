@@ -0,0 +1,155 @@
+package lexorankbench
+
+import (
+	"github.com/ntauth/lexorank"
+)
+
+// Trace is a recorded sequence of insert positions, for example sampled from
+// production logs, that can be replayed as a workload.
+type Trace []uint
+
+// Workload returns a workload replaying the trace. Positions beyond the end of
+// the list are clamped to an append, and the trace wraps around if more
+// operations are run than it holds.
+func (t Trace) Workload(name string) Workload {
+	return Workload{
+		Name: name,
+		Position: func(i, length int) uint {
+			if len(t) == 0 {
+				return uint(length)
+			}
+			return min(t[i%len(t)], uint(length))
+		},
+	}
+}
+
+// Strategy is a named configuration taking part in a comparison, such as one
+// using AppendStrategyDefault, one using AppendStrategyStep or a custom one.
+type Strategy struct {
+	Name   string
+	Config *lexorank.Config
+}
+
+// StrategyResult describes how a strategy coped with a workload.
+type StrategyResult struct {
+	Strategy string
+	Ops      int
+
+	// KeysRewritten is the number of existing keys changed by rebalancing or
+	// normalising, i.e. writes beyond the inserted rows themselves
+	KeysRewritten int
+
+	// Rebalances counts inserts that rewrote at least one existing key
+	Rebalances int
+
+	// Normalizations and Failures have the same meaning as in Result
+	Normalizations int
+	Failures       int
+
+	// RankLengths counts the keys of the final list by rank length
+	RankLengths map[int]int
+
+	// MaxRankLength and AvgRankLength describe the final rank lengths
+	MaxRankLength int
+	AvgRankLength float64
+
+	// Sorted reports whether the final list is strictly sorted
+	Sorted bool
+}
+
+// StrategyReport holds the results of one workload for every strategy, in the
+// order given.
+type StrategyReport struct {
+	Workload string
+	Results  []StrategyResult
+}
+
+// Best returns the result with the fewest rewritten keys among those that
+// ended sorted without failures, preferring shorter keys on ties. It reports
+// false if no strategy qualifies.
+func (r StrategyReport) Best() (StrategyResult, bool) {
+	var (
+		best  StrategyResult
+		found bool
+	)
+	for _, res := range r.Results {
+		if !res.Sorted || res.Failures > 0 {
+			continue
+		}
+		if !found ||
+			res.KeysRewritten < best.KeysRewritten ||
+			res.KeysRewritten == best.KeysRewritten && res.MaxRankLength < best.MaxRankLength {
+			best, found = res, true
+		}
+	}
+	return best, found
+}
+
+// CompareStrategies replays the same workload against every strategy. The
+// workload is constructed afresh for each strategy so that stateful workloads
+// such as RandomInsert produce the same sequence of positions every time.
+func CompareStrategies(workload func() Workload, ops int, strategies ...Strategy) StrategyReport {
+	report := StrategyReport{Workload: workload().Name}
+	for _, s := range strategies {
+		report.Results = append(report.Results, runStrategy(workload(), ops, s))
+	}
+	return report
+}
+
+// countingItem counts every key change made to it by the list.
+type countingItem struct {
+	key    lexorank.Key
+	writes *int
+}
+
+func (c *countingItem) GetKey() lexorank.Key { return c.key }
+
+func (c *countingItem) SetKey(k lexorank.Key) {
+	c.key = k
+	*c.writes++
+}
+
+func runStrategy(w Workload, ops int, s Strategy) StrategyResult {
+	list := make(lexorank.ReorderableList, 0, ops)
+	res := StrategyResult{Strategy: s.Name, Ops: ops, RankLengths: make(map[int]int)}
+
+	writes := 0
+	for i := 0; i < ops; i++ {
+		pos := w.Position(i, len(list))
+		before := writes
+
+		key, err := list.Insert(pos, s.Config)
+		if err != nil {
+			res.Normalizations++
+			if _, nerr := list.Normalize(s.Config); nerr == nil {
+				key, err = list.Insert(pos, s.Config)
+			}
+		}
+		if writes > before {
+			res.Rebalances++
+		}
+		if err != nil {
+			res.Failures++
+			continue
+		}
+
+		list = append(list, nil)
+		copy(list[pos+1:], list[pos:])
+		list[pos] = &countingItem{key: *key, writes: &writes}
+	}
+
+	res.KeysRewritten = writes
+	total := 0
+	for _, it := range list {
+		n := len(it.GetKey().RankBytes())
+		res.RankLengths[n]++
+		res.MaxRankLength = max(res.MaxRankLength, n)
+		total += n
+	}
+	if len(list) > 0 {
+		res.AvgRankLength = float64(total) / float64(len(list))
+	}
+	res.Sorted = list.StrictlySorted()
+
+	return res
+}
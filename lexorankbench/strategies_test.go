@@ -0,0 +1,70 @@
+package lexorankbench_test
+
+import (
+	"testing"
+
+	"github.com/ntauth/lexorank"
+	"github.com/ntauth/lexorank/lexorankbench"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrace_Workload(t *testing.T) {
+	a := assert.New(t)
+
+	w := lexorankbench.Trace{0, 5, 1}.Workload("trace")
+	a.Equal("trace", w.Name)
+	a.Equal(uint(0), w.Position(0, 0))
+	a.Equal(uint(2), w.Position(1, 2), "clamped to an append")
+	a.Equal(uint(1), w.Position(2, 2))
+	a.Equal(uint(0), w.Position(3, 3), "wraps around")
+}
+
+func TestCompareStrategies(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	report := lexorankbench.CompareStrategies(func() lexorankbench.Workload {
+		return lexorankbench.RandomInsert(7)
+	}, 300,
+		lexorankbench.Strategy{Name: "default", Config: lexorank.DefaultConfig()},
+		lexorankbench.Strategy{Name: "long", Config: lexorank.DefaultConfig().WithMaxRankLength(12)},
+	)
+
+	a.Equal("random-insert", report.Workload)
+	r.Len(report.Results, 2)
+
+	short, long := report.Results[0], report.Results[1]
+	a.Equal("default", short.Strategy)
+	a.Equal(300, short.Ops)
+	a.True(short.Sorted)
+	a.True(long.Sorted)
+	a.LessOrEqual(short.MaxRankLength, 6)
+	a.GreaterOrEqual(short.KeysRewritten, long.KeysRewritten, "longer ranks leave more room")
+
+	lengths := 0
+	for _, n := range long.RankLengths {
+		lengths += n
+	}
+	a.Equal(300-long.Failures, lengths)
+
+	best, ok := report.Best()
+	r.True(ok)
+	a.LessOrEqual(best.KeysRewritten, min(short.KeysRewritten, long.KeysRewritten))
+	if short.KeysRewritten == long.KeysRewritten {
+		a.Equal(min(short.MaxRankLength, long.MaxRankLength), best.MaxRankLength)
+	}
+}
+
+func TestCompareStrategies_HotGapRewrites(t *testing.T) {
+	a := assert.New(t)
+
+	report := lexorankbench.CompareStrategies(lexorankbench.HotGap, 200,
+		lexorankbench.Strategy{Name: "default", Config: lexorank.DefaultConfig()},
+	)
+
+	res := report.Results[0]
+	a.Positive(res.Rebalances)
+	a.Positive(res.KeysRewritten)
+	a.Zero(res.Failures)
+}
@@ -0,0 +1,122 @@
+// Package lexorankdynamo stores lexorank keys in DynamoDB. Key marshals to a
+// string attribute, so it can serve as the sort key of a table or index, and
+// Between builds the key condition selecting a range of ranks.
+//
+// DynamoDB orders string sort keys by their UTF-8 bytes, which matches
+// Key.Compare for every alphabet of this package.
+package lexorankdynamo
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/ntauth/lexorank"
+)
+
+var (
+	_ attributevalue.Marshaler   = Key{}
+	_ attributevalue.Unmarshaler = (*Key)(nil)
+)
+
+// Key is a lexorank.Key that marshals to and from a DynamoDB string
+// attribute. Use it as the type of the sort key field of an item:
+//
+//	type Task struct {
+//		Board string             `dynamodbav:"board"`
+//		Rank  lexorankdynamo.Key `dynamodbav:"rank"`
+//	}
+//
+// Keys of a non-default alphabet need a Config to unmarshal. Set it on the
+// field before decoding into the item:
+//
+//	task := Task{Rank: lexorankdynamo.Key{Config: config}}
+//	err := attributevalue.UnmarshalMap(out.Item, &task)
+type Key struct {
+	lexorank.Key
+
+	// Config parses unmarshalled keys with its alphabet. Nil means the
+	// default configuration.
+	Config *lexorank.Config `dynamodbav:"-"`
+}
+
+// MarshalDynamoDBAttributeValue implements attributevalue.Marshaler. The zero
+// Key is rejected, as DynamoDB accepts neither an empty string nor NULL for a
+// key attribute; use a *Key with omitempty for an optional attribute.
+func (k Key) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	if k.IsZero() {
+		return nil, fmt.Errorf("cannot marshal the zero lexorankdynamo.Key")
+	}
+	return &types.AttributeValueMemberS{Value: k.String()}, nil
+}
+
+// UnmarshalDynamoDBAttributeValue implements attributevalue.Unmarshaler. NULL
+// unmarshals to the zero Key.
+func (k *Key) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberNULL:
+		k.Key = lexorank.Key{}
+		return nil
+	case *types.AttributeValueMemberS:
+		config := k.Config
+		if config == nil {
+			config = lexorank.DefaultConfig()
+		}
+		parsed, err := config.ParseKey(v.Value)
+		if err != nil {
+			return err
+		}
+		k.Key = *parsed
+		return nil
+	default:
+		return fmt.Errorf("cannot unmarshal %T into lexorankdynamo.Key", av)
+	}
+}
+
+// Condition is a fragment of a KeyConditionExpression together with the
+// placeholders it uses. Add Names and Values to those of the query.
+type Condition struct {
+	Expression string
+	Names      map[string]string
+	Values     map[string]types.AttributeValue
+}
+
+// Between returns the condition selecting the items whose sort key attr lies
+// between lo and hi, inclusive of both as with DynamoDB's BETWEEN. A zero key
+// leaves that side open. With both open nothing needs to be selected, so the
+// Condition is empty: query by the partition key alone instead of joining it.
+// Otherwise join it to the partition key condition with AND:
+//
+//	cond := lexorankdynamo.Between("rank", lo, hi)
+//	values := map[string]types.AttributeValue{":board": board}
+//	maps.Copy(values, cond.Values)
+//	out, err := client.Query(ctx, &dynamodb.QueryInput{
+//		TableName:                 aws.String("tasks"),
+//		KeyConditionExpression:    aws.String("board = :board AND " + cond.Expression),
+//		ExpressionAttributeNames:  cond.Names,
+//		ExpressionAttributeValues: values,
+//	})
+func Between(attr string, lo, hi lexorank.Key) Condition {
+	c := Condition{
+		Names:  map[string]string{"#lexorank": attr},
+		Values: map[string]types.AttributeValue{},
+	}
+	switch {
+	case !lo.IsZero() && !hi.IsZero():
+		c.Expression = "#lexorank BETWEEN :lexorank_lo AND :lexorank_hi"
+	case !lo.IsZero():
+		c.Expression = "#lexorank >= :lexorank_lo"
+	case !hi.IsZero():
+		c.Expression = "#lexorank <= :lexorank_hi"
+	default:
+		return Condition{}
+	}
+	if !lo.IsZero() {
+		c.Values[":lexorank_lo"] = &types.AttributeValueMemberS{Value: lo.String()}
+	}
+	if !hi.IsZero() {
+		c.Values[":lexorank_hi"] = &types.AttributeValueMemberS{Value: hi.String()}
+	}
+	return c
+}
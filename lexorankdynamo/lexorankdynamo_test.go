@@ -0,0 +1,104 @@
+package lexorankdynamo_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/ntauth/lexorank"
+	"github.com/ntauth/lexorank/lexorankdynamo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type task struct {
+	Board string             `dynamodbav:"board"`
+	Rank  lexorankdynamo.Key `dynamodbav:"rank"`
+}
+
+func mustKey(s string) lexorank.Key {
+	k, err := lexorank.ParseKey(s)
+	if err != nil {
+		panic(err)
+	}
+	return *k
+}
+
+func TestKey_RoundTrip(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	in := task{Board: "b1", Rank: lexorankdynamo.Key{Key: mustKey("0|hzzzzz")}}
+	av, err := attributevalue.MarshalMap(in)
+	r.NoError(err)
+	a.Equal(&types.AttributeValueMemberS{Value: "0|hzzzzz"}, av["rank"])
+
+	var out task
+	r.NoError(attributevalue.UnmarshalMap(av, &out))
+	a.Equal(in.Rank.String(), out.Rank.String())
+
+	// DynamoDB rejects empty and NULL key attributes, so the zero key is not
+	// marshalled at all
+	_, err = attributevalue.MarshalMap(task{Board: "b1"})
+	a.Error(err)
+
+	// NULL still reads back as zero
+	av["rank"] = &types.AttributeValueMemberNULL{Value: true}
+	r.NoError(attributevalue.UnmarshalMap(av, &out))
+	a.True(out.Rank.IsZero())
+
+	var k lexorankdynamo.Key
+	a.Error(k.UnmarshalDynamoDBAttributeValue(&types.AttributeValueMemberN{Value: "1"}))
+	a.Error(k.UnmarshalDynamoDBAttributeValue(&types.AttributeValueMemberS{Value: "not a key"}))
+}
+
+func TestKey_Config(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := lexorank.CaseInsensitiveConfig()
+	k, err := config.ParseKey("0|h0z")
+	r.NoError(err)
+
+	av, err := attributevalue.MarshalMap(task{Board: "b1", Rank: lexorankdynamo.Key{Key: *k}})
+	r.NoError(err)
+
+	out := task{Rank: lexorankdynamo.Key{Config: config}}
+	r.NoError(attributevalue.UnmarshalMap(av, &out))
+	a.Equal(*k, out.Rank.Key, "the key is parsed with the config's alphabet")
+	a.Same(config, out.Rank.Config)
+
+	// Keys parsed with the alphabet work with the config
+	next, err := lexorank.Between(out.Rank.Key, lexorank.Key{}, config)
+	r.NoError(err)
+	a.Positive(next.Compare(*k))
+
+	// Characters outside the alphabet are rejected
+	av["rank"] = &types.AttributeValueMemberS{Value: "0|hZ"}
+	a.Error(attributevalue.UnmarshalMap(av, &out))
+}
+
+func TestBetween(t *testing.T) {
+	a := assert.New(t)
+
+	lo, hi := mustKey("0|a"), mustKey("0|c")
+
+	c := lexorankdynamo.Between("rank", lo, hi)
+	a.Equal("#lexorank BETWEEN :lexorank_lo AND :lexorank_hi", c.Expression)
+	a.Equal(map[string]string{"#lexorank": "rank"}, c.Names)
+	a.Equal(map[string]types.AttributeValue{
+		":lexorank_lo": &types.AttributeValueMemberS{Value: "0|a"},
+		":lexorank_hi": &types.AttributeValueMemberS{Value: "0|c"},
+	}, c.Values)
+
+	c = lexorankdynamo.Between("rank", lo, lexorank.Key{})
+	a.Equal("#lexorank >= :lexorank_lo", c.Expression)
+	a.Len(c.Values, 1)
+
+	c = lexorankdynamo.Between("rank", lexorank.Key{}, hi)
+	a.Equal("#lexorank <= :lexorank_hi", c.Expression)
+	a.Len(c.Values, 1)
+
+	a.Equal(lexorankdynamo.Condition{}, lexorankdynamo.Between("rank", lexorank.Key{}, lexorank.Key{}))
+}
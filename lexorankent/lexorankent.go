@@ -0,0 +1,89 @@
+// Package lexorankent integrates lexorank keys with entgo.io schemas. Field
+// declares a column holding a lexorank.Key with a size and collation that keep
+// ORDER BY consistent with Key.Compare, and the predicates select rows by key
+// range without going through strings by hand.
+package lexorankent
+
+import (
+	"fmt"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/schema/field"
+
+	"github.com/ntauth/lexorank"
+)
+
+// Field returns a schema field named name whose Go type is lexorank.Key, for
+// use in an ent schema's Fields method:
+//
+//	func (Task) Fields() []ent.Field {
+//		return []ent.Field{
+//			lexorankent.Field("rank", dialect.Postgres, config),
+//		}
+//	}
+//
+// The column is sized with config.RecommendedColumnSize and collated so that
+// the database compares keys byte by byte on the given dialect: "C" on
+// Postgres and ascii_bin on MySQL. SQLite compares bytes by default.
+func Field(name, driver string, config *lexorank.Config) ent.Field {
+	typ := "text"
+	if n := config.RecommendedColumnSize(); n > 0 {
+		typ = fmt.Sprintf("varchar(%d)", n)
+	}
+
+	f := field.String(name).
+		GoType(lexorank.Key{}).
+		SchemaType(map[string]string{
+			dialect.Postgres: typ,
+			dialect.MySQL:    typ,
+			dialect.SQLite:   typ,
+		})
+	if n := config.RecommendedColumnSize(); n > 0 {
+		f = f.MaxLen(n)
+	}
+
+	switch driver {
+	case dialect.Postgres:
+		f = f.Annotations(entsql.Annotation{Collation: "C"})
+	case dialect.MySQL:
+		f = f.Annotations(entsql.Annotation{Charset: "ascii", Collation: "ascii_bin"})
+	}
+	return f
+}
+
+// Between returns a predicate selecting the rows whose key in column lies
+// strictly between lo and hi, as needed to load the neighbours of a gap for a
+// local rebalance. A zero key leaves that side open. Convert it to the
+// generated predicate type of the entity:
+//
+//	client.Task.Query().
+//		Where(predicate.Task(lexorankent.Between(task.FieldRank, lo, hi))).
+//		Order(ent.Asc(task.FieldRank))
+func Between(column string, lo, hi lexorank.Key) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		var preds []*sql.Predicate
+		if !lo.IsZero() {
+			preds = append(preds, sql.GT(s.C(column), lo.String()))
+		}
+		if !hi.IsZero() {
+			preds = append(preds, sql.LT(s.C(column), hi.String()))
+		}
+		if len(preds) > 0 {
+			s.Where(sql.And(preds...))
+		}
+	}
+}
+
+// InRange returns a predicate selecting the rows whose key in column falls
+// within r, such as lexorank.BucketRange or lexorank.PrefixRange.
+func InRange(column string, r lexorank.RankRange) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		s.Where(sql.And(
+			sql.GTE(s.C(column), r.From),
+			sql.LT(s.C(column), r.To),
+		))
+	}
+}
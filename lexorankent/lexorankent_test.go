@@ -0,0 +1,62 @@
+package lexorankent_test
+
+import (
+	"testing"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/dialect/sql"
+
+	"github.com/ntauth/lexorank"
+	"github.com/ntauth/lexorank/lexorankent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestField(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	desc := lexorankent.Field("rank", dialect.Postgres, lexorank.DefaultConfig()).Descriptor()
+	r.NoError(desc.Err)
+	a.Equal("rank", desc.Name)
+	a.Equal("lexorank.Key", desc.Info.Ident)
+	a.Equal("varchar(8)", desc.SchemaType[dialect.Postgres])
+	r.Len(desc.Annotations, 1)
+	a.Equal("C", desc.Annotations[0].(entsql.Annotation).Collation)
+
+	desc = lexorankent.Field("rank", dialect.MySQL, lexorank.DefaultConfig().WithMaxRankLength(0)).Descriptor()
+	r.NoError(desc.Err)
+	a.Equal("text", desc.SchemaType[dialect.MySQL])
+	a.Equal("ascii_bin", desc.Annotations[0].(entsql.Annotation).Collation)
+}
+
+func TestBetween(t *testing.T) {
+	a := assert.New(t)
+
+	lo, hi := mustKey(t, "0|a"), mustKey(t, "0|c")
+
+	query := func(p func(*sql.Selector)) (string, []any) {
+		s := sql.Dialect(dialect.Postgres).Select("*").From(sql.Table("tasks"))
+		p(s)
+		return s.Query()
+	}
+
+	q, args := query(lexorankent.Between("rank", lo, hi))
+	a.Equal(`SELECT * FROM "tasks" WHERE "tasks"."rank" > $1 AND "tasks"."rank" < $2`, q)
+	a.Equal([]any{"0|a", "0|c"}, args)
+
+	q, args = query(lexorankent.Between("rank", lexorank.Key{}, hi))
+	a.Equal(`SELECT * FROM "tasks" WHERE "tasks"."rank" < $1`, q)
+	a.Equal([]any{"0|c"}, args)
+
+	q, args = query(lexorankent.InRange("rank", lexorank.BucketRange(1, lexorank.DefaultConfig())))
+	a.Equal(`SELECT * FROM "tasks" WHERE "tasks"."rank" >= $1 AND "tasks"."rank" < $2`, q)
+	a.Equal([]any{"1|", "1}"}, args)
+}
+
+func mustKey(t *testing.T, s string) lexorank.Key {
+	k, err := lexorank.ParseKey(s)
+	require.NoError(t, err)
+	return *k
+}
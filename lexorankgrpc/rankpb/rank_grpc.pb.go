@@ -0,0 +1,259 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: rankpb/rank.proto
+
+package rankpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	RankService_GenerateBetween_FullMethodName = "/lexorank.v1.RankService/GenerateBetween"
+	RankService_Append_FullMethodName          = "/lexorank.v1.RankService/Append"
+	RankService_Prepend_FullMethodName         = "/lexorank.v1.RankService/Prepend"
+	RankService_NormalizePlan_FullMethodName   = "/lexorank.v1.RankService/NormalizePlan"
+)
+
+// RankServiceClient is the client API for RankService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// RankService hands out lexorank keys from a central server, so clients in
+// any language generate keys with the same alphabet and configuration as the
+// server that orders them. Keys are exchanged in their string form, e.g.
+// "0|hzzzzz".
+type RankServiceClient interface {
+	// GenerateBetween returns count keys strictly between prev and next.
+	GenerateBetween(ctx context.Context, in *GenerateBetweenRequest, opts ...grpc.CallOption) (*GenerateBetweenResponse, error)
+	// Append returns count keys after last, for adding items to the end of a
+	// list.
+	Append(ctx context.Context, in *AppendRequest, opts ...grpc.CallOption) (*AppendResponse, error)
+	// Prepend returns count keys before first, for adding items to the start
+	// of a list.
+	Prepend(ctx context.Context, in *PrependRequest, opts ...grpc.CallOption) (*PrependResponse, error)
+	// NormalizePlan returns the keys a normalize of the given list would
+	// assign, without storing anything.
+	NormalizePlan(ctx context.Context, in *NormalizePlanRequest, opts ...grpc.CallOption) (*NormalizePlanResponse, error)
+}
+
+type rankServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRankServiceClient(cc grpc.ClientConnInterface) RankServiceClient {
+	return &rankServiceClient{cc}
+}
+
+func (c *rankServiceClient) GenerateBetween(ctx context.Context, in *GenerateBetweenRequest, opts ...grpc.CallOption) (*GenerateBetweenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerateBetweenResponse)
+	err := c.cc.Invoke(ctx, RankService_GenerateBetween_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rankServiceClient) Append(ctx context.Context, in *AppendRequest, opts ...grpc.CallOption) (*AppendResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AppendResponse)
+	err := c.cc.Invoke(ctx, RankService_Append_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rankServiceClient) Prepend(ctx context.Context, in *PrependRequest, opts ...grpc.CallOption) (*PrependResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PrependResponse)
+	err := c.cc.Invoke(ctx, RankService_Prepend_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rankServiceClient) NormalizePlan(ctx context.Context, in *NormalizePlanRequest, opts ...grpc.CallOption) (*NormalizePlanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NormalizePlanResponse)
+	err := c.cc.Invoke(ctx, RankService_NormalizePlan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RankServiceServer is the server API for RankService service.
+// All implementations must embed UnimplementedRankServiceServer
+// for forward compatibility.
+//
+// RankService hands out lexorank keys from a central server, so clients in
+// any language generate keys with the same alphabet and configuration as the
+// server that orders them. Keys are exchanged in their string form, e.g.
+// "0|hzzzzz".
+type RankServiceServer interface {
+	// GenerateBetween returns count keys strictly between prev and next.
+	GenerateBetween(context.Context, *GenerateBetweenRequest) (*GenerateBetweenResponse, error)
+	// Append returns count keys after last, for adding items to the end of a
+	// list.
+	Append(context.Context, *AppendRequest) (*AppendResponse, error)
+	// Prepend returns count keys before first, for adding items to the start
+	// of a list.
+	Prepend(context.Context, *PrependRequest) (*PrependResponse, error)
+	// NormalizePlan returns the keys a normalize of the given list would
+	// assign, without storing anything.
+	NormalizePlan(context.Context, *NormalizePlanRequest) (*NormalizePlanResponse, error)
+	mustEmbedUnimplementedRankServiceServer()
+}
+
+// UnimplementedRankServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRankServiceServer struct{}
+
+func (UnimplementedRankServiceServer) GenerateBetween(context.Context, *GenerateBetweenRequest) (*GenerateBetweenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateBetween not implemented")
+}
+func (UnimplementedRankServiceServer) Append(context.Context, *AppendRequest) (*AppendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Append not implemented")
+}
+func (UnimplementedRankServiceServer) Prepend(context.Context, *PrependRequest) (*PrependResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Prepend not implemented")
+}
+func (UnimplementedRankServiceServer) NormalizePlan(context.Context, *NormalizePlanRequest) (*NormalizePlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NormalizePlan not implemented")
+}
+func (UnimplementedRankServiceServer) mustEmbedUnimplementedRankServiceServer() {}
+func (UnimplementedRankServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeRankServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RankServiceServer will
+// result in compilation errors.
+type UnsafeRankServiceServer interface {
+	mustEmbedUnimplementedRankServiceServer()
+}
+
+func RegisterRankServiceServer(s grpc.ServiceRegistrar, srv RankServiceServer) {
+	// If the following call pancis, it indicates UnimplementedRankServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RankService_ServiceDesc, srv)
+}
+
+func _RankService_GenerateBetween_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateBetweenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RankServiceServer).GenerateBetween(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RankService_GenerateBetween_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RankServiceServer).GenerateBetween(ctx, req.(*GenerateBetweenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RankService_Append_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AppendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RankServiceServer).Append(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RankService_Append_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RankServiceServer).Append(ctx, req.(*AppendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RankService_Prepend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrependRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RankServiceServer).Prepend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RankService_Prepend_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RankServiceServer).Prepend(ctx, req.(*PrependRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RankService_NormalizePlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NormalizePlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RankServiceServer).NormalizePlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RankService_NormalizePlan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RankServiceServer).NormalizePlan(ctx, req.(*NormalizePlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RankService_ServiceDesc is the grpc.ServiceDesc for RankService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RankService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "lexorank.v1.RankService",
+	HandlerType: (*RankServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GenerateBetween",
+			Handler:    _RankService_GenerateBetween_Handler,
+		},
+		{
+			MethodName: "Append",
+			Handler:    _RankService_Append_Handler,
+		},
+		{
+			MethodName: "Prepend",
+			Handler:    _RankService_Prepend_Handler,
+		},
+		{
+			MethodName: "NormalizePlan",
+			Handler:    _RankService_NormalizePlan_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "rankpb/rank.proto",
+}
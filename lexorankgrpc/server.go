@@ -0,0 +1,186 @@
+// Package lexorankgrpc serves lexorank key generation over gRPC, so clients
+// written in other languages, such as browser or Python frontends, request
+// keys from a central Go service and stay consistent with the ordering the
+// server applies. Register a Server on a grpc.Server:
+//
+//	s := grpc.NewServer()
+//	rankpb.RegisterRankServiceServer(s, lexorankgrpc.NewServer(config))
+//
+// The service definition is in rankpb/rank.proto.
+package lexorankgrpc
+
+//go:generate buf generate
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ntauth/lexorank"
+	"github.com/ntauth/lexorank/lexorankgrpc/rankpb"
+)
+
+// Server implements rankpb.RankServiceServer with a lexorank.Ranker.
+type Server struct {
+	rankpb.UnimplementedRankServiceServer
+
+	ranker *lexorank.Ranker
+
+	// MaxCount is the largest number of keys or items a single request may
+	// ask for. Zero means no limit.
+	MaxCount int
+}
+
+var _ rankpb.RankServiceServer = (*Server)(nil)
+
+// NewServer creates a server generating keys with a copy of config, or the
+// default configuration if config is nil. MaxCount defaults to 1000.
+func NewServer(config *lexorank.Config) *Server {
+	return &Server{ranker: lexorank.NewRanker(config), MaxCount: 1000}
+}
+
+// GenerateBetween implements rankpb.RankServiceServer.
+func (s *Server) GenerateBetween(_ context.Context, req *rankpb.GenerateBetweenRequest) (*rankpb.GenerateBetweenResponse, error) {
+	n, err := s.count(req.GetCount())
+	if err != nil {
+		return nil, err
+	}
+	prev, err := s.parse("prev", req.GetPrev())
+	if err != nil {
+		return nil, err
+	}
+	next, err := s.parse("next", req.GetNext())
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.ranker.BetweenN(prev, next, n)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &rankpb.GenerateBetweenResponse{Keys: keyStrings(keys)}, nil
+}
+
+// Append implements rankpb.RankServiceServer.
+func (s *Server) Append(_ context.Context, req *rankpb.AppendRequest) (*rankpb.AppendResponse, error) {
+	n, err := s.count(req.GetCount())
+	if err != nil {
+		return nil, err
+	}
+	last, err := s.parse("last", req.GetLast())
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.ranker.AfterN(last, n)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &rankpb.AppendResponse{Keys: keyStrings(keys)}, nil
+}
+
+// Prepend implements rankpb.RankServiceServer.
+func (s *Server) Prepend(_ context.Context, req *rankpb.PrependRequest) (*rankpb.PrependResponse, error) {
+	n, err := s.count(req.GetCount())
+	if err != nil {
+		return nil, err
+	}
+	first, err := s.parse("first", req.GetFirst())
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.ranker.BeforeN(first, n)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &rankpb.PrependResponse{Keys: keyStrings(keys)}, nil
+}
+
+// NormalizePlan implements rankpb.RankServiceServer.
+func (s *Server) NormalizePlan(_ context.Context, req *rankpb.NormalizePlanRequest) (*rankpb.NormalizePlanResponse, error) {
+	if s.MaxCount > 0 && len(req.GetItems()) > s.MaxCount {
+		return nil, status.Errorf(codes.InvalidArgument, "%d items exceed the limit of %d", len(req.GetItems()), s.MaxCount)
+	}
+
+	items := make([]lexorank.SnapshotItem, len(req.GetItems()))
+	l := make(lexorank.ReorderableList, len(items))
+	for i, it := range req.GetItems() {
+		k, err := s.parse("items.key", it.GetKey())
+		if err != nil {
+			return nil, err
+		}
+		if k.IsZero() {
+			return nil, status.Errorf(codes.InvalidArgument, "item %d has no key", i)
+		}
+		items[i] = lexorank.SnapshotItem{ID: it.GetId(), Key: k}
+		l[i] = &items[i]
+	}
+
+	// Only a plan is computed, so it doesn't depend on AutoNormalize
+	keys, err := s.ranker.NormalizedKeys(l)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &rankpb.NormalizePlanResponse{Changes: []*rankpb.Change{}}
+	for i, k := range keys {
+		if items[i].Key.Compare(k) == 0 {
+			continue
+		}
+		resp.Changes = append(resp.Changes, &rankpb.Change{
+			Index: int32(i),
+			Id:    items[i].ID,
+			Key:   k.String(),
+		})
+	}
+	return resp, nil
+}
+
+// count validates the number of keys requested, where zero means one.
+func (s *Server) count(n int32) (int, error) {
+	switch {
+	case n < 0:
+		return 0, status.Errorf(codes.InvalidArgument, "negative count %d", n)
+	case n == 0:
+		return 1, nil
+	case s.MaxCount > 0 && int(n) > s.MaxCount:
+		return 0, status.Errorf(codes.InvalidArgument, "count %d exceeds the limit of %d", n, s.MaxCount)
+	}
+	return int(n), nil
+}
+
+// parse parses the key in the named request field. An empty string is the
+// zero key.
+func (s *Server) parse(field, raw string) (lexorank.Key, error) {
+	if raw == "" {
+		return lexorank.Key{}, nil
+	}
+	k, err := s.ranker.ParseKey(raw)
+	if err != nil {
+		return lexorank.Key{}, status.Errorf(codes.InvalidArgument, "%s: %v", field, err)
+	}
+	return *k, nil
+}
+
+// toStatus maps errors of the lexorank package to gRPC status codes: request
+// errors are invalid arguments and precondition errors failed preconditions.
+func toStatus(err error) error {
+	switch {
+	case lexorank.IsRequestError(err):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case lexorank.IsPreconditionError(err):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func keyStrings(keys []lexorank.Key) []string {
+	out := make([]string, len(keys))
+	for i, k := range keys {
+		out[i] = k.String()
+	}
+	return out
+}
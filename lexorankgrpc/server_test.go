@@ -0,0 +1,159 @@
+package lexorankgrpc_test
+
+import (
+	"context"
+	"net"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ntauth/lexorank"
+	"github.com/ntauth/lexorank/lexorankgrpc"
+	"github.com/ntauth/lexorank/lexorankgrpc/rankpb"
+)
+
+func newClient(t *testing.T, srv *lexorankgrpc.Server) rankpb.RankServiceClient {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	rankpb.RegisterRankServiceServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return rankpb.NewRankServiceClient(conn)
+}
+
+func parseAll(t *testing.T, raw []string) []lexorank.Key {
+	keys := make([]lexorank.Key, len(raw))
+	for i, s := range raw {
+		k, err := lexorank.ParseKey(s)
+		require.NoError(t, err)
+		keys[i] = *k
+	}
+	return keys
+}
+
+func requireAscending(t *testing.T, keys []lexorank.Key) {
+	require.True(t, sort.SliceIsSorted(keys, func(i, j int) bool { return keys[i].Compare(keys[j]) < 0 }))
+}
+
+func TestServer_GenerateBetween(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+	ctx := context.Background()
+	client := newClient(t, lexorankgrpc.NewServer(nil))
+
+	resp, err := client.GenerateBetween(ctx, &rankpb.GenerateBetweenRequest{Prev: "0|a", Next: "0|c", Count: 3})
+	r.NoError(err)
+	r.Len(resp.Keys, 3)
+	keys := parseAll(t, append(append([]string{"0|a"}, resp.Keys...), "0|c"))
+	requireAscending(t, keys)
+
+	resp, err = client.GenerateBetween(ctx, &rankpb.GenerateBetweenRequest{})
+	r.NoError(err)
+	a.Len(resp.Keys, 1)
+
+	_, err = client.GenerateBetween(ctx, &rankpb.GenerateBetweenRequest{Prev: "not a key"})
+	a.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = client.GenerateBetween(ctx, &rankpb.GenerateBetweenRequest{Count: 5000})
+	a.Equal(codes.InvalidArgument, status.Code(err))
+
+	// A full gap has to be rebalanced first
+	_, err = client.GenerateBetween(ctx, &rankpb.GenerateBetweenRequest{Prev: "0|aaaaa0", Next: "0|aaaaa1", Count: 2})
+	a.Equal(codes.FailedPrecondition, status.Code(err))
+}
+
+func TestServer_AppendPrepend(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	client := newClient(t, lexorankgrpc.NewServer(nil))
+
+	app, err := client.Append(ctx, &rankpb.AppendRequest{Count: 3})
+	r.NoError(err)
+	r.Len(app.Keys, 3)
+	requireAscending(t, parseAll(t, app.Keys))
+
+	more, err := client.Append(ctx, &rankpb.AppendRequest{Last: app.Keys[2], Count: 2})
+	r.NoError(err)
+	requireAscending(t, parseAll(t, append(app.Keys, more.Keys...)))
+
+	pre, err := client.Prepend(ctx, &rankpb.PrependRequest{First: app.Keys[0], Count: 2})
+	r.NoError(err)
+	requireAscending(t, parseAll(t, append(pre.Keys, app.Keys...)))
+
+	pre, err = client.Prepend(ctx, &rankpb.PrependRequest{Count: 2})
+	r.NoError(err)
+	requireAscending(t, parseAll(t, pre.Keys))
+}
+
+func TestServer_NormalizePlan(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+	ctx := context.Background()
+	client := newClient(t, lexorankgrpc.NewServer(nil))
+
+	resp, err := client.NormalizePlan(ctx, &rankpb.NormalizePlanRequest{Items: []*rankpb.Item{
+		{Id: "a", Key: "0|a"},
+		{Id: "b", Key: "0|a0"},
+		{Id: "c", Key: "0|a1"},
+	}})
+	r.NoError(err)
+	r.NotEmpty(resp.Changes)
+
+	after := []string{"0|a", "0|a0", "0|a1"}
+	for _, c := range resp.Changes {
+		a.Equal([]string{"a", "b", "c"}[c.Index], c.Id)
+		after[c.Index] = c.Key
+	}
+	requireAscending(t, parseAll(t, after))
+
+	_, err = client.NormalizePlan(ctx, &rankpb.NormalizePlanRequest{Items: []*rankpb.Item{
+		{Id: "a", Key: "0|a"},
+		{Id: "b"},
+	}})
+	a.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_NormalizePlan_Production(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+	ctx := context.Background()
+
+	// ProductionConfig disables AutoNormalize, which only applies to inline
+	// normalization and not to a plan the caller stores itself
+	config := lexorank.ProductionConfig()
+	client := newClient(t, lexorankgrpc.NewServer(config))
+
+	items := []*rankpb.Item{{Id: "a", Key: "0|a"}, {Id: "b", Key: "0|a"}, {Id: "c", Key: "0|b"}}
+	resp, err := client.NormalizePlan(ctx, &rankpb.NormalizePlanRequest{Items: items})
+	r.NoError(err)
+
+	l := make(lexorank.ReorderableList, len(items))
+	for i, it := range items {
+		k, err := config.ParseKey(it.Key)
+		r.NoError(err)
+		l[i] = &lexorank.SnapshotItem{ID: it.Id, Key: *k}
+	}
+	want, err := l.NormalizedKeys(config)
+	r.NoError(err)
+
+	after := []string{"0|a", "0|a", "0|b"}
+	for _, c := range resp.Changes {
+		a.Equal(items[c.Index].Id, c.Id)
+		after[c.Index] = c.Key
+	}
+	for i, k := range want {
+		a.Equal(k.String(), after[i])
+	}
+}
@@ -0,0 +1,363 @@
+// Package lexorankhttp serves lexorank key generation as a JSON API, for
+// services that want a rank endpoint without setting up gRPC. Handler is a
+// plain http.Handler, so it mounts into net/http, chi, echo or any other
+// router:
+//
+//	mux.Handle("/rank/", http.StripPrefix("/rank", lexorankhttp.NewHandler(config)))
+//
+// Every endpoint takes a POST with a JSON body and answers with JSON. Keys are
+// exchanged in their string form, where an empty string means none.
+//
+//	POST /between         BetweenRequest       -> KeysResponse
+//	POST /append          AppendRequest        -> KeysResponse
+//	POST /prepend         PrependRequest       -> KeysResponse
+//	POST /validate        ValidateRequest      -> ValidateResponse
+//	POST /normalize-plan  NormalizePlanRequest -> NormalizePlanResponse
+//
+// Failures are answered with an ErrorResponse: 400 for malformed requests and
+// keys, 409 when the list must be rebalanced or normalized first.
+package lexorankhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ntauth/lexorank"
+)
+
+// BetweenRequest asks for Count keys strictly between Prev and Next. An empty
+// Prev or Next leaves that side open, and a zero Count means one.
+type BetweenRequest struct {
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Count int    `json:"count,omitempty"`
+}
+
+// AppendRequest asks for Count keys after Last, the key of the last item.
+// Leave Last empty for an empty list.
+type AppendRequest struct {
+	Last  string `json:"last,omitempty"`
+	Count int    `json:"count,omitempty"`
+}
+
+// PrependRequest asks for Count keys before First, the key of the first item.
+// Leave First empty for an empty list.
+type PrependRequest struct {
+	First string `json:"first,omitempty"`
+	Count int    `json:"count,omitempty"`
+}
+
+// KeysResponse holds generated keys in ascending order.
+type KeysResponse struct {
+	Keys []string `json:"keys"`
+}
+
+// ValidateRequest asks whether Key, for example one computed in a browser,
+// may be stored between Prev and Next without colliding with Existing.
+type ValidateRequest struct {
+	Key      string   `json:"key"`
+	Prev     string   `json:"prev,omitempty"`
+	Next     string   `json:"next,omitempty"`
+	Existing []string `json:"existing,omitempty"`
+}
+
+// ValidateResponse reports whether the key is acceptable and, if not, every
+// rule it breaks.
+type ValidateResponse struct {
+	Valid      bool        `json:"valid"`
+	Violations []Violation `json:"violations,omitempty"`
+}
+
+// Violation mirrors lexorank.Violation.
+type Violation struct {
+	Code    lexorank.ViolationCode `json:"code"`
+	Message string                 `json:"message"`
+}
+
+// Item is one item of a list sent to be normalized.
+type Item struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+// NormalizePlanRequest holds the items of a list in order.
+type NormalizePlanRequest struct {
+	Items []Item `json:"items"`
+}
+
+// Change is the new key normalization assigns to the item at Index.
+type Change struct {
+	Index int    `json:"index"`
+	ID    string `json:"id"`
+	Key   string `json:"key"`
+}
+
+// NormalizePlanResponse holds the changes of a normalize in ascending index
+// order. Nothing is stored; the caller applies them.
+type NormalizePlanResponse struct {
+	Changes []Change `json:"changes"`
+}
+
+// ErrorResponse is the body of every failed request.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler serves the JSON API with a lexorank.Ranker.
+type Handler struct {
+	ranker *lexorank.Ranker
+	mux    *http.ServeMux
+
+	// MaxCount is the largest number of keys or items a single request may
+	// ask for. Zero means no limit.
+	MaxCount int
+
+	// MaxBodyBytes limits the size of request bodies. Zero means no limit.
+	MaxBodyBytes int64
+}
+
+// NewHandler creates a handler generating keys with a copy of config, or the
+// default configuration if config is nil. MaxCount defaults to 1000 and
+// MaxBodyBytes to 1 MiB.
+func NewHandler(config *lexorank.Config) *Handler {
+	h := &Handler{
+		ranker:       lexorank.NewRanker(config),
+		mux:          http.NewServeMux(),
+		MaxCount:     1000,
+		MaxBodyBytes: 1 << 20,
+	}
+	h.mux.HandleFunc("POST /between", handle(h, h.between))
+	h.mux.HandleFunc("POST /append", handle(h, h.append))
+	h.mux.HandleFunc("POST /prepend", handle(h, h.prepend))
+	h.mux.HandleFunc("POST /validate", handle(h, h.validate))
+	h.mux.HandleFunc("POST /normalize-plan", handle(h, h.normalizePlan))
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// requestError is an error caused by the request rather than the list, and
+// answered with 400.
+type requestError struct {
+	err error
+}
+
+func (e requestError) Error() string { return e.err.Error() }
+
+func badRequest(format string, args ...any) error {
+	return requestError{fmt.Errorf(format, args...)}
+}
+
+// handle decodes the request body into a Req, runs fn and encodes its result.
+func handle[Req, Resp any](h *Handler, fn func(Req) (*Resp, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body := r.Body
+		if h.MaxBodyBytes > 0 {
+			body = http.MaxBytesReader(w, body, h.MaxBodyBytes)
+		}
+
+		var req Req
+		if err := json.NewDecoder(body).Decode(&req); err != nil {
+			writeError(w, badRequest("invalid request body: %v", err))
+			return
+		}
+
+		resp, err := fn(req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers with the status code for err: malformed requests and
+// request errors are bad requests, precondition errors conflicts.
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	var reqErr requestError
+	switch {
+	case errors.As(err, &reqErr), lexorank.IsRequestError(err):
+		code = http.StatusBadRequest
+	case lexorank.IsPreconditionError(err):
+		code = http.StatusConflict
+	}
+	writeJSON(w, code, ErrorResponse{Error: err.Error()})
+}
+
+func (h *Handler) between(req BetweenRequest) (*KeysResponse, error) {
+	n, err := h.count(req.Count)
+	if err != nil {
+		return nil, err
+	}
+	prev, err := h.parse("prev", req.Prev)
+	if err != nil {
+		return nil, err
+	}
+	next, err := h.parse("next", req.Next)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := h.ranker.BetweenN(prev, next, n)
+	if err != nil {
+		return nil, err
+	}
+	return &KeysResponse{Keys: keyStrings(keys)}, nil
+}
+
+func (h *Handler) append(req AppendRequest) (*KeysResponse, error) {
+	n, err := h.count(req.Count)
+	if err != nil {
+		return nil, err
+	}
+	last, err := h.parse("last", req.Last)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := h.ranker.AfterN(last, n)
+	if err != nil {
+		return nil, err
+	}
+	return &KeysResponse{Keys: keyStrings(keys)}, nil
+}
+
+func (h *Handler) prepend(req PrependRequest) (*KeysResponse, error) {
+	n, err := h.count(req.Count)
+	if err != nil {
+		return nil, err
+	}
+	first, err := h.parse("first", req.First)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := h.ranker.BeforeN(first, n)
+	if err != nil {
+		return nil, err
+	}
+	return &KeysResponse{Keys: keyStrings(keys)}, nil
+}
+
+func (h *Handler) validate(req ValidateRequest) (*ValidateResponse, error) {
+	if h.MaxCount > 0 && len(req.Existing) > h.MaxCount {
+		return nil, badRequest("%d existing keys exceed the limit of %d", len(req.Existing), h.MaxCount)
+	}
+
+	k, err := h.parse("key", req.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	var neighbours [2]*lexorank.Key
+	for i, raw := range []string{req.Prev, req.Next} {
+		n, err := h.parse([]string{"prev", "next"}[i], raw)
+		if err != nil {
+			return nil, err
+		}
+		if !n.IsZero() {
+			neighbours[i] = &n
+		}
+	}
+	existing := make(lexorank.Keys, len(req.Existing))
+	for i, raw := range req.Existing {
+		if existing[i], err = h.parse("existing", raw); err != nil {
+			return nil, err
+		}
+	}
+
+	config := h.ranker.Config()
+	err = lexorank.ValidateProposedKey(k, neighbours[0], neighbours[1], existing, &config)
+	var verr *lexorank.KeyValidationError
+	switch {
+	case err == nil:
+		return &ValidateResponse{Valid: true}, nil
+	case errors.As(err, &verr):
+		resp := &ValidateResponse{Violations: make([]Violation, len(verr.Violations))}
+		for i, v := range verr.Violations {
+			resp.Violations[i] = Violation{Code: v.Code, Message: v.Message}
+		}
+		return resp, nil
+	default:
+		return nil, err
+	}
+}
+
+func (h *Handler) normalizePlan(req NormalizePlanRequest) (*NormalizePlanResponse, error) {
+	if h.MaxCount > 0 && len(req.Items) > h.MaxCount {
+		return nil, badRequest("%d items exceed the limit of %d", len(req.Items), h.MaxCount)
+	}
+
+	items := make([]lexorank.SnapshotItem, len(req.Items))
+	l := make(lexorank.ReorderableList, len(items))
+	for i, it := range req.Items {
+		k, err := h.parse("items.key", it.Key)
+		if err != nil {
+			return nil, err
+		}
+		if k.IsZero() {
+			return nil, badRequest("item %d has no key", i)
+		}
+		items[i] = lexorank.SnapshotItem{ID: it.ID, Key: k}
+		l[i] = &items[i]
+	}
+
+	// Only a plan is computed, so it doesn't depend on AutoNormalize
+	keys, err := h.ranker.NormalizedKeys(l)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &NormalizePlanResponse{Changes: []Change{}}
+	for i, k := range keys {
+		if items[i].Key.Compare(k) == 0 {
+			continue
+		}
+		resp.Changes = append(resp.Changes, Change{Index: i, ID: items[i].ID, Key: k.String()})
+	}
+	return resp, nil
+}
+
+// count validates the number of keys requested, where zero means one.
+func (h *Handler) count(n int) (int, error) {
+	switch {
+	case n < 0:
+		return 0, badRequest("negative count %d", n)
+	case n == 0:
+		return 1, nil
+	case h.MaxCount > 0 && n > h.MaxCount:
+		return 0, badRequest("count %d exceeds the limit of %d", n, h.MaxCount)
+	}
+	return n, nil
+}
+
+// parse parses the key in the named request field. An empty string is the
+// zero key.
+func (h *Handler) parse(field, raw string) (lexorank.Key, error) {
+	if raw == "" {
+		return lexorank.Key{}, nil
+	}
+	k, err := h.ranker.ParseKey(raw)
+	if err != nil {
+		return lexorank.Key{}, badRequest("%s: %v", field, err)
+	}
+	return *k, nil
+}
+
+func keyStrings(keys []lexorank.Key) []string {
+	out := make([]string, len(keys))
+	for i, k := range keys {
+		out[i] = k.String()
+	}
+	return out
+}
@@ -0,0 +1,163 @@
+package lexorankhttp_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ntauth/lexorank"
+	"github.com/ntauth/lexorank/lexorankhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func post(t *testing.T, h http.Handler, path string, req, resp any) int {
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body)))
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), resp))
+	return rec.Code
+}
+
+func requireAscending(t *testing.T, raw ...string) {
+	var prev *lexorank.Key
+	for _, s := range raw {
+		k, err := lexorank.ParseKey(s)
+		require.NoError(t, err)
+		if prev != nil {
+			require.Negative(t, prev.Compare(*k), "%s before %s", prev, k)
+		}
+		prev = k
+	}
+}
+
+func TestHandler_Between(t *testing.T) {
+	a := assert.New(t)
+	h := lexorankhttp.NewHandler(nil)
+
+	var resp lexorankhttp.KeysResponse
+	a.Equal(http.StatusOK, post(t, h, "/between", lexorankhttp.BetweenRequest{Prev: "0|a", Next: "0|c", Count: 3}, &resp))
+	a.Len(resp.Keys, 3)
+	requireAscending(t, append(append([]string{"0|a"}, resp.Keys...), "0|c")...)
+
+	var errResp lexorankhttp.ErrorResponse
+	a.Equal(http.StatusBadRequest, post(t, h, "/between", lexorankhttp.BetweenRequest{Prev: "not a key"}, &errResp))
+	a.Contains(errResp.Error, "prev")
+	a.Equal(http.StatusBadRequest, post(t, h, "/between", lexorankhttp.BetweenRequest{Count: 5000}, &errResp))
+	a.Equal(http.StatusBadRequest, post(t, h, "/between", "not an object", &errResp))
+
+	// A full gap has to be rebalanced first
+	a.Equal(http.StatusConflict, post(t, h, "/between", lexorankhttp.BetweenRequest{Prev: "0|aaaaa0", Next: "0|aaaaa1", Count: 2}, &errResp))
+}
+
+func TestHandler_AppendPrepend(t *testing.T) {
+	a := assert.New(t)
+	h := lexorankhttp.NewHandler(nil)
+
+	var app, pre lexorankhttp.KeysResponse
+	a.Equal(http.StatusOK, post(t, h, "/append", lexorankhttp.AppendRequest{Count: 2}, &app))
+	a.Len(app.Keys, 2)
+	a.Equal(http.StatusOK, post(t, h, "/prepend", lexorankhttp.PrependRequest{First: app.Keys[0], Count: 2}, &pre))
+	a.Len(pre.Keys, 2)
+
+	var more lexorankhttp.KeysResponse
+	a.Equal(http.StatusOK, post(t, h, "/append", lexorankhttp.AppendRequest{Last: app.Keys[1]}, &more))
+	a.Len(more.Keys, 1)
+
+	requireAscending(t, append(append(pre.Keys, app.Keys...), more.Keys...)...)
+}
+
+func TestHandler_Validate(t *testing.T) {
+	a := assert.New(t)
+	h := lexorankhttp.NewHandler(nil)
+
+	var resp lexorankhttp.ValidateResponse
+	a.Equal(http.StatusOK, post(t, h, "/validate", lexorankhttp.ValidateRequest{Key: "0|b", Prev: "0|a", Next: "0|c"}, &resp))
+	a.True(resp.Valid)
+
+	resp = lexorankhttp.ValidateResponse{}
+	a.Equal(http.StatusOK, post(t, h, "/validate", lexorankhttp.ValidateRequest{
+		Key:      "0|b",
+		Prev:     "0|c",
+		Existing: []string{"0|b"},
+	}, &resp))
+	a.False(resp.Valid)
+	var codes []lexorank.ViolationCode
+	for _, v := range resp.Violations {
+		codes = append(codes, v.Code)
+	}
+	a.ElementsMatch([]lexorank.ViolationCode{lexorank.ViolationNotAfter, lexorank.ViolationCollision}, codes)
+}
+
+func TestHandler_NormalizePlan(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+	h := lexorankhttp.NewHandler(nil)
+
+	var resp lexorankhttp.NormalizePlanResponse
+	a.Equal(http.StatusOK, post(t, h, "/normalize-plan", lexorankhttp.NormalizePlanRequest{Items: []lexorankhttp.Item{
+		{ID: "a", Key: "0|a"},
+		{ID: "b", Key: "0|a0"},
+		{ID: "c", Key: "0|a1"},
+	}}, &resp))
+	r.NotEmpty(resp.Changes)
+
+	after := []string{"0|a", "0|a0", "0|a1"}
+	for _, c := range resp.Changes {
+		a.Equal([]string{"a", "b", "c"}[c.Index], c.ID)
+		after[c.Index] = c.Key
+	}
+	requireAscending(t, after...)
+}
+
+func TestHandler_NormalizePlan_Production(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// ProductionConfig disables AutoNormalize, which only applies to inline
+	// normalization and not to a plan the caller stores itself
+	config := lexorank.ProductionConfig()
+	h := lexorankhttp.NewHandler(config)
+
+	items := []lexorankhttp.Item{{ID: "a", Key: "0|a"}, {ID: "b", Key: "0|a"}, {ID: "c", Key: "0|b"}}
+	var resp lexorankhttp.NormalizePlanResponse
+	r.Equal(http.StatusOK, post(t, h, "/normalize-plan", lexorankhttp.NormalizePlanRequest{Items: items}, &resp))
+
+	l := make(lexorank.ReorderableList, len(items))
+	for i, it := range items {
+		k, err := config.ParseKey(it.Key)
+		r.NoError(err)
+		l[i] = &lexorank.SnapshotItem{ID: it.ID, Key: *k}
+	}
+	want, err := l.NormalizedKeys(config)
+	r.NoError(err)
+
+	after := []string{"0|a", "0|a", "0|b"}
+	for _, c := range resp.Changes {
+		a.Equal(items[c.Index].ID, c.ID)
+		after[c.Index] = c.Key
+	}
+	for i, k := range want {
+		a.Equal(k.String(), after[i])
+	}
+}
+
+func TestHandler_Routes(t *testing.T) {
+	a := assert.New(t)
+
+	mux := http.NewServeMux()
+	mux.Handle("/rank/", http.StripPrefix("/rank", lexorankhttp.NewHandler(nil)))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rank/between", nil))
+	a.Equal(http.StatusMethodNotAllowed, rec.Code)
+
+	var resp lexorankhttp.KeysResponse
+	a.Equal(http.StatusOK, post(t, mux, "/rank/between", lexorankhttp.BetweenRequest{}, &resp))
+	a.Len(resp.Keys, 1)
+}
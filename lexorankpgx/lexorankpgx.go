@@ -0,0 +1,97 @@
+// Package lexorankpgx lets github.com/jackc/pgx/v5 encode and scan
+// lexorank.Key values natively, in both the text and binary protocol formats,
+// instead of going through the database/sql Scanner and Valuer interfaces.
+//
+// Register the codec on every connection, e.g. from pgxpool's AfterConnect:
+//
+//	cfg.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+//		lexorankpgx.Register(conn.TypeMap(), config)
+//		return nil
+//	}
+package lexorankpgx
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ntauth/lexorank"
+)
+
+// Codec is a pgtype.Codec for text columns that encodes lexorank.Key values
+// and scans into *lexorank.Key directly. Every other value is handled like
+// pgtype.TextCodec, so strings keep working on the same columns. Text columns
+// use the same bytes in the text and binary formats, so both are supported.
+//
+// The zero Key is encoded as NULL. Scanning NULL into a *lexorank.Key fails;
+// scan into a **lexorank.Key to accept it.
+type Codec struct {
+	pgtype.TextCodec
+
+	// Config parses scanned keys with its alphabet. Nil means the default
+	// alphabet, as with lexorank.ParseKey.
+	Config *lexorank.Config
+}
+
+// PlanEncode implements pgtype.Codec.
+func (c Codec) PlanEncode(m *pgtype.Map, oid uint32, format int16, value any) pgtype.EncodePlan {
+	if _, ok := value.(lexorank.Key); ok && c.FormatSupported(format) {
+		return encodePlanKey{}
+	}
+	return c.TextCodec.PlanEncode(m, oid, format, value)
+}
+
+// PlanScan implements pgtype.Codec.
+func (c Codec) PlanScan(m *pgtype.Map, oid uint32, format int16, target any) pgtype.ScanPlan {
+	if _, ok := target.(*lexorank.Key); ok && c.FormatSupported(format) {
+		return scanPlanKey{config: c.Config}
+	}
+	return c.TextCodec.PlanScan(m, oid, format, target)
+}
+
+type encodePlanKey struct{}
+
+func (encodePlanKey) Encode(value any, buf []byte) ([]byte, error) {
+	k := value.(lexorank.Key)
+	if k.IsZero() {
+		return nil, nil
+	}
+	return append(buf, k.String()...), nil
+}
+
+type scanPlanKey struct {
+	config *lexorank.Config
+}
+
+func (p scanPlanKey) Scan(src []byte, target any) error {
+	if src == nil {
+		return fmt.Errorf("cannot scan NULL into %T", target)
+	}
+
+	var k *lexorank.Key
+	var err error
+	if p.config != nil {
+		k, err = p.config.ParseKey(string(src))
+	} else {
+		k, err = lexorank.ParseKey(string(src))
+	}
+	if err != nil {
+		return err
+	}
+	*target.(*lexorank.Key) = *k
+	return nil
+}
+
+// Register replaces the codec of the text, varchar and bpchar types in m with
+// a Codec parsing keys with config, and maps lexorank.Key to text for
+// parameters whose type is not known.
+func Register(m *pgtype.Map, config *lexorank.Config) {
+	for _, name := range []string{"text", "varchar", "bpchar"} {
+		t, ok := m.TypeForName(name)
+		if !ok {
+			continue
+		}
+		m.RegisterType(&pgtype.Type{Name: t.Name, OID: t.OID, Codec: Codec{Config: config}})
+	}
+	m.RegisterDefaultPgType(lexorank.Key{}, "text")
+}
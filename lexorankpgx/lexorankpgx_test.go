@@ -0,0 +1,76 @@
+package lexorankpgx_test
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ntauth/lexorank"
+	"github.com/ntauth/lexorank/lexorankpgx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodec_RoundTrip(t *testing.T) {
+	m := pgtype.NewMap()
+	lexorankpgx.Register(m, nil)
+
+	k, err := lexorank.ParseKey("0|hzzzzz")
+	require.NoError(t, err)
+
+	for _, format := range []int16{pgtype.TextFormatCode, pgtype.BinaryFormatCode} {
+		for _, oid := range []uint32{pgtype.TextOID, pgtype.VarcharOID} {
+			r := require.New(t)
+			a := assert.New(t)
+
+			buf, err := m.Encode(oid, format, *k, nil)
+			r.NoError(err)
+			a.Equal("0|hzzzzz", string(buf))
+
+			var got lexorank.Key
+			r.NoError(m.Scan(oid, format, buf, &got))
+			a.Equal(k.String(), got.String())
+
+			// Strings are unaffected
+			var s string
+			r.NoError(m.Scan(oid, format, buf, &s))
+			a.Equal("0|hzzzzz", s)
+		}
+	}
+}
+
+func TestCodec_Null(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	m := pgtype.NewMap()
+	lexorankpgx.Register(m, nil)
+
+	buf, err := m.Encode(pgtype.TextOID, pgtype.TextFormatCode, lexorank.Key{}, nil)
+	r.NoError(err)
+	a.Nil(buf)
+
+	var nullable *lexorank.Key
+	r.NoError(m.Scan(pgtype.TextOID, pgtype.TextFormatCode, nil, &nullable))
+	a.Nil(nullable)
+
+	r.NoError(m.Scan(pgtype.TextOID, pgtype.TextFormatCode, []byte("1|abc"), &nullable))
+	r.NotNil(nullable)
+	a.Equal("1|abc", nullable.String())
+
+	var k lexorank.Key
+	a.Error(m.Scan(pgtype.TextOID, pgtype.TextFormatCode, nil, &k))
+	a.Error(m.Scan(pgtype.TextOID, pgtype.TextFormatCode, []byte("not a key"), &k))
+}
+
+func TestCodec_Config(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	m := pgtype.NewMap()
+	lexorankpgx.Register(m, lexorank.CaseInsensitiveConfig().WithSeparator(':'))
+
+	var k lexorank.Key
+	r.NoError(m.Scan(pgtype.TextOID, pgtype.BinaryFormatCode, []byte("0:9z"), &k))
+	a.Equal(lexorank.Base36Alphabet.String(), k.Alphabet().String())
+}
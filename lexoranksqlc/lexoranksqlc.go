@@ -0,0 +1,104 @@
+// Package lexoranksqlc provides column types for sqlc-generated code, so
+// queries return lexorank keys instead of strings. Point the rank columns at
+// them with overrides in sqlc.yaml:
+//
+//	overrides:
+//	  go:
+//	    overrides:
+//	      - column: "tasks.rank"
+//	        go_type: "github.com/ntauth/lexorank/lexoranksqlc.Key"
+//	      - column: "tasks.parent_rank"
+//	        go_type: "github.com/ntauth/lexorank/lexoranksqlc.NullKey"
+//
+// Key is for NOT NULL columns and NullKey for nullable ones, following the
+// database/sql convention of sql.NullString. Both scan through pointer
+// receivers and implement driver.Valuer on values, so they work as query
+// parameters whether sqlc passes them by value or by pointer.
+package lexoranksqlc
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/ntauth/lexorank"
+)
+
+var (
+	_ sql.Scanner   = (*Key)(nil)
+	_ driver.Valuer = Key{}
+	_ sql.Scanner   = (*NullKey)(nil)
+	_ driver.Valuer = NullKey{}
+)
+
+// Key is a lexorank.Key stored in a NOT NULL column. The embedded key is what
+// the rest of the program works with:
+//
+//	row, err := queries.GetTask(ctx, id)
+//	next, err := lexorank.Between(row.Rank.Key, lexorank.Key{}, config)
+type Key struct {
+	lexorank.Key
+}
+
+// Scan implements sql.Scanner. Scanning NULL fails; use NullKey for nullable
+// columns.
+func (k *Key) Scan(value any) error {
+	if value == nil {
+		return fmt.Errorf("cannot scan NULL into lexoranksqlc.Key, use NullKey")
+	}
+	return k.Key.Scan(value)
+}
+
+// Value implements driver.Valuer. The zero Key is rejected rather than stored
+// as an empty string that would not parse back.
+func (k Key) Value() (driver.Value, error) {
+	if k.IsZero() {
+		return nil, fmt.Errorf("cannot store the zero lexoranksqlc.Key, use NullKey")
+	}
+	return k.Key.Value()
+}
+
+// NullKey is a lexorank.Key stored in a nullable column. Valid is false for
+// NULL.
+type NullKey struct {
+	Key   lexorank.Key
+	Valid bool
+}
+
+// NewNullKey returns a NullKey holding k, or NULL if k is nil.
+func NewNullKey(k *lexorank.Key) NullKey {
+	if k == nil {
+		return NullKey{}
+	}
+	return NullKey{Key: *k, Valid: true}
+}
+
+// Ptr returns the key, or nil for NULL.
+func (n NullKey) Ptr() *lexorank.Key {
+	if !n.Valid {
+		return nil
+	}
+	k := n.Key
+	return &k
+}
+
+// Scan implements sql.Scanner.
+func (n *NullKey) Scan(value any) error {
+	if value == nil {
+		*n = NullKey{}
+		return nil
+	}
+	if err := n.Key.Scan(value); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (n NullKey) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return Key{Key: n.Key}.Value()
+}
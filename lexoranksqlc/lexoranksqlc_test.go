@@ -0,0 +1,69 @@
+package lexoranksqlc_test
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/ntauth/lexorank"
+	"github.com/ntauth/lexorank/lexoranksqlc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	var k lexoranksqlc.Key
+	r.NoError(k.Scan("0|hzzzzz"))
+	a.Equal("0|hzzzzz", k.String())
+	r.NoError(k.Scan([]byte("1|a")))
+	a.Equal("1|a", k.String())
+
+	v, err := k.Value()
+	r.NoError(err)
+	a.Equal(driver.Value("1|a"), v)
+
+	// Pointers are valuers too
+	var valuer driver.Valuer = &k
+	v, err = valuer.Value()
+	r.NoError(err)
+	a.Equal(driver.Value("1|a"), v)
+
+	a.Error(k.Scan(nil))
+	a.Error(k.Scan(42))
+
+	_, err = lexoranksqlc.Key{}.Value()
+	a.Error(err)
+}
+
+func TestNullKey(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	k, err := lexorank.ParseKey("0|a")
+	r.NoError(err)
+
+	n := lexoranksqlc.NewNullKey(k)
+	a.True(n.Valid)
+	r.NoError(n.Scan(nil))
+	a.False(n.Valid)
+	a.Nil(n.Ptr())
+
+	v, err := n.Value()
+	r.NoError(err)
+	a.Nil(v)
+
+	r.NoError(n.Scan("0|b"))
+	a.True(n.Valid)
+	r.NotNil(n.Ptr())
+	a.Equal("0|b", n.Ptr().String())
+
+	v, err = n.Value()
+	r.NoError(err)
+	a.Equal(driver.Value("0|b"), v)
+
+	a.Equal(lexoranksqlc.NullKey{}, lexoranksqlc.NewNullKey(nil))
+	a.Equal(n, lexoranksqlc.NewNullKey(n.Ptr()))
+	a.Error(n.Scan("not a key"))
+}
@@ -0,0 +1,126 @@
+package lexorank
+
+import "sort"
+
+// List is a typed alternative to ReorderableList for items that are stored by
+// value. Instead of requiring every item to implement Reorderable, it reads
+// and writes keys through accessor funcs, so a []T coming from storage can be
+// reordered in place and the results used directly as T afterwards.
+//
+// Operations run the same algorithms as ReorderableList and return the
+// indices of the items with new keys in ascending order, for use with
+// ChangeSet. They borrow the items through a temporary view that is allocated
+// once per call rather than once per item.
+type List[T any] struct {
+	Items []T
+
+	key    func(*T) Key
+	setKey func(*T, Key)
+}
+
+// NewList creates a List over items, which must already be ordered by key.
+// The accessor funcs get and set the key of a single item.
+func NewList[T any](items []T, key func(*T) Key, setKey func(*T, Key)) *List[T] {
+	return &List[T]{Items: items, key: key, setKey: setKey}
+}
+
+// listItem is a Reorderable that refers to one item of a List by index.
+type listItem[T any] struct {
+	list  *List[T]
+	index int
+}
+
+func (e *listItem[T]) GetKey() Key  { return e.list.key(&e.list.Items[e.index]) }
+func (e *listItem[T]) SetKey(k Key) { e.list.setKey(&e.list.Items[e.index], k) }
+
+// view returns a ReorderableList whose items read and write the keys of l.
+func (l *List[T]) view() ReorderableList {
+	items := make([]listItem[T], len(l.Items))
+	view := make(ReorderableList, len(l.Items))
+	for i := range items {
+		items[i] = listItem[T]{list: l, index: i}
+		view[i] = &items[i]
+	}
+	return view
+}
+
+// Len returns the number of items in the list.
+func (l *List[T]) Len() int { return len(l.Items) }
+
+// Key returns the key of the item at index i.
+func (l *List[T]) Key(i int) Key { return l.key(&l.Items[i]) }
+
+// Keys returns the current key of every item in list order.
+func (l *List[T]) Keys() Keys { return l.view().Keys() }
+
+// Insert gives item a key that places it at position and adds it to the list
+// there, rebalancing its neighbours if necessary.
+func (l *List[T]) Insert(position uint, item T, config *Config) ([]int, error) {
+	view := l.view()
+	var k *Key
+	changed, err := view.Track(func() error {
+		var err error
+		k, err = view.Insert(position, config)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.setKey(&item, *k)
+	l.Items = append(l.Items, item)
+	copy(l.Items[position+1:], l.Items[position:])
+	l.Items[position] = item
+
+	return insertedIndices(changed, int(position)), nil
+}
+
+// insertedIndices maps the indices of the items rewritten while making room
+// for an insert at position to their indices once the new item is in the
+// list, and adds the new item's own index.
+func insertedIndices(changed []int, position int) []int {
+	touched := []int{position}
+	for _, i := range changed {
+		if i < position {
+			touched = append(touched, i)
+		} else {
+			touched = append(touched, i+1)
+		}
+	}
+	sort.Ints(touched)
+	return touched
+}
+
+// Append adds item to the end of the list with a key after the last one.
+func (l *List[T]) Append(item T, config *Config) ([]int, error) {
+	return l.Insert(uint(len(l.Items)), item, config)
+}
+
+// Prepend adds item to the start of the list with a key before the first one.
+func (l *List[T]) Prepend(item T, config *Config) ([]int, error) {
+	return l.Insert(0, item, config)
+}
+
+// Move gives the item at index from a new key so that it lands at index to,
+// and moves it there in Items, like ReorderableList.Move.
+func (l *List[T]) Move(from, to uint, config *Config) ([]int, error) {
+	touched, err := l.view().Move(from, to, config)
+	if err != nil || from == to {
+		return touched, err
+	}
+
+	it := l.Items[from]
+	if from < to {
+		copy(l.Items[from:to], l.Items[from+1:to+1])
+	} else {
+		copy(l.Items[to+1:from+1], l.Items[to:from])
+	}
+	l.Items[to] = it
+	return touched, nil
+}
+
+// Normalize spreads the keys of all items evenly, like
+// ReorderableList.Normalize.
+func (l *List[T]) Normalize(config *Config) ([]int, error) {
+	return l.view().Normalize(config)
+}
@@ -0,0 +1,90 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type card struct {
+	Title string
+	Rank  Key
+}
+
+func cardList(keys ...string) *List[card] {
+	items := make([]card, len(keys))
+	for i, k := range keys {
+		items[i] = card{Title: string(rune('a' + i)), Rank: mustKey(k)}
+	}
+	return NewList(items,
+		func(c *card) Key { return c.Rank },
+		func(c *card, k Key) { c.Rank = k },
+	)
+}
+
+func titles(l *List[card]) string {
+	var s string
+	for _, it := range l.Items {
+		s += it.Title
+	}
+	return s
+}
+
+func TestList_Insert(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	l := cardList("0|a", "0|c")
+
+	changed, err := l.Insert(1, card{Title: "x"}, DefaultConfig())
+	r.NoError(err)
+	a.Equal([]int{1}, changed)
+	a.Equal("axb", titles(l))
+	a.Equal("0|b", l.Key(1).String())
+
+	_, err = l.Append(card{Title: "y"}, DefaultConfig())
+	r.NoError(err)
+	_, err = l.Prepend(card{Title: "z"}, DefaultConfig())
+	r.NoError(err)
+	a.Equal("zaxby", titles(l))
+	a.True(l.Keys().strictlySorted())
+
+	_, err = l.Insert(9, card{}, DefaultConfig())
+	r.ErrorIs(err, ErrOutOfBounds)
+}
+
+func TestList_Insert_Rebalance(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	l := cardList("0|a", "0|b", "0|b0")
+	config := DefaultConfig()
+	config.MaxRankLength = 2
+
+	changed, err := l.Insert(2, card{Title: "x"}, config)
+	r.NoError(err)
+	a.Equal(4, l.Len())
+	a.Equal("abxc", titles(l))
+	a.Contains(changed, 2)
+	a.True(l.Keys().strictlySorted())
+}
+
+func TestList_Move(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	l := cardList("0|a", "0|c", "0|e")
+
+	changed, err := l.Move(0, 2, DefaultConfig())
+	r.NoError(err)
+	a.Equal([]int{2}, changed)
+	a.Equal("bca", titles(l))
+	a.True(l.Keys().strictlySorted())
+
+	changed, err = l.Normalize(DefaultConfig())
+	r.NoError(err)
+	a.NotEmpty(changed)
+	a.Equal("bca", titles(l))
+	a.True(l.Keys().strictlySorted())
+}
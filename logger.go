@@ -0,0 +1,17 @@
+package lexorank
+
+// Logger receives warnings about notable events in list operations, such as a
+// rebalance falling back to a full normalization, which would otherwise only
+// show up as a burst of writes. Each call has a message and alternating keys
+// and values with details, like log/slog, so a *slog.Logger can be set as
+// Config.Logger directly. Calls are made synchronously from the operation.
+type Logger interface {
+	Warn(msg string, args ...any)
+}
+
+// warn passes an event to config.Logger if set.
+func (c *Config) warn(msg string, args ...any) {
+	if c.Logger != nil {
+		c.Logger.Warn(msg, args...)
+	}
+}
@@ -0,0 +1,81 @@
+package lexorank
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingLogger struct {
+	messages []string
+	args     [][]any
+}
+
+func (l *recordingLogger) Warn(msg string, args ...any) {
+	l.messages = append(l.messages, msg)
+	l.args = append(l.args, args)
+}
+
+func TestLogger(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	logger := &recordingLogger{}
+	config := DefaultConfig().WithMaxRankLength(2).WithLogger(logger)
+
+	// The fast path is silent
+	list := ReorderableList{item(0, "0|a"), item(1, "0|c")}
+	_, err := list.Insert(1, config)
+	r.NoError(err)
+	a.Empty(logger.messages)
+
+	list = ReorderableList{item(0, "0|a"), item(1, "0|aa"), item(2, "0|ab")}
+	_, err = list.Insert(2, config.WithRebalanceStrategy(RebalanceFullNormalize))
+	r.NoError(err)
+	a.Equal([]string{
+		"no room left for a new key at MaxRankLength",
+		"rebalance falling back to normalize",
+	}, logger.messages)
+	a.Equal([]any{"position", uint(2), "items", 3, "max_rank_length", 2}, logger.args[0])
+	a.Equal([]any{"items", 3}, logger.args[1])
+
+	// Inserting between duplicates fails, and the warning says why
+	logger.messages, logger.args = nil, nil
+	list = ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|b"), item(3, "0|c")}
+	_, err = list.Insert(2, DefaultConfig().WithLogger(logger))
+	a.ErrorContains(err, "failed to insert key after rebalance")
+	r.NotEmpty(logger.messages)
+	a.Equal("neighbouring keys are not strictly ordered", logger.messages[0])
+	a.Equal([]any{"position", uint(2), "prev", "0|b", "next", "0|b"}, logger.args[0])
+
+	logger.messages = nil
+	list = ReorderableList{item(0, "0|a"), item(1, "0|aa"), item(2, "0|ab")}
+	_, err = list.Insert(2, config.WithRebalanceStrategy(RebalanceFullNormalize).WithAutoNormalize(true, 3))
+	a.ErrorIs(err, ErrNormalizationRequired)
+	a.Equal([]string{
+		"no room left for a new key at MaxRankLength",
+		"rebalance needs a normalize above the threshold",
+	}, logger.messages)
+}
+
+func TestLogger_Slog(t *testing.T) {
+	r := require.New(t)
+
+	var buf bytes.Buffer
+	config := DefaultConfig().WithMaxRankLength(2).WithLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	list := ReorderableList{item(0, "0|a"), item(1, "0|aa"), item(2, "0|ab")}
+	_, err := list.Insert(2, config.WithRebalanceStrategy(RebalanceFullNormalize))
+	r.NoError(err)
+
+	var first map[string]any
+	r.NoError(json.NewDecoder(&buf).Decode(&first))
+	r.Equal("WARN", first["level"])
+	r.Equal("no room left for a new key at MaxRankLength", first["msg"])
+	r.EqualValues(2, first["position"])
+	r.EqualValues(2, first["max_rank_length"])
+}
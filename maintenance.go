@@ -0,0 +1,237 @@
+package lexorank
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// MaintenanceStore gives a MaintenanceScheduler access to persisted lists and
+// to the cursors it uses to resume work where it left off.
+type MaintenanceStore interface {
+	// LoadList returns the list with the given name, ordered by key.
+	LoadList(ctx context.Context, name string) (ReorderableList, error)
+
+	// SaveKeys persists the changed keys of the list with the given name.
+	SaveKeys(ctx context.Context, name string, list ReorderableList, cs ChangeSet) error
+
+	// LoadCursor returns the index maintenance should resume from, or zero if
+	// the list has never been processed.
+	LoadCursor(ctx context.Context, name string) (int, error)
+
+	// SaveCursor persists the index maintenance should resume from.
+	SaveCursor(ctx context.Context, name string, cursor int) error
+}
+
+// MaintenanceScheduler incrementally compacts registered lists in chunks,
+// rewriting at most Budget keys per minute and only while Window allows it.
+// A chunk is compacted by spreading its items evenly between the keys that
+// surround it, which shortens ranks that have grown from repeated inserts.
+// Chunks whose keys are already as short as compaction would make them are
+// skipped.
+type MaintenanceScheduler struct {
+	Store  MaintenanceStore
+	Config *Config
+
+	// Budget is the maximum number of keys rewritten per minute
+	Budget int
+
+	// ChunkSize is the number of items considered per step
+	ChunkSize int
+
+	// Window reports whether maintenance may run at the given time, e.g. to
+	// restrict it to off-peak hours. A nil Window always allows it.
+	Window func(time.Time) bool
+
+	// Now returns the current time. Nil means time.Now.
+	Now func() time.Time
+
+	mu     sync.Mutex
+	lists  []string
+	minute time.Time
+	spent  int
+}
+
+// defaultMaintenanceChunkSize is the chunk size used when ChunkSize is not
+// positive.
+const defaultMaintenanceChunkSize = 100
+
+// NewMaintenanceScheduler creates a scheduler with a default chunk size of 100.
+func NewMaintenanceScheduler(store MaintenanceStore, config *Config, budget int) *MaintenanceScheduler {
+	return &MaintenanceScheduler{
+		Store:     store,
+		Config:    config,
+		Budget:    budget,
+		ChunkSize: defaultMaintenanceChunkSize,
+		Now:       time.Now,
+	}
+}
+
+// Register adds lists to be maintained.
+func (s *MaintenanceScheduler) Register(names ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lists = append(s.lists, names...)
+}
+
+// RunOnce processes one chunk of every registered list, as far as the window
+// and the remaining budget for the current minute allow. It returns the number
+// of keys rewritten.
+func (s *MaintenanceScheduler) RunOnce(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.Now != nil {
+		now = s.Now()
+	}
+	if s.Window != nil && !s.Window(now) {
+		return 0, nil
+	}
+
+	if minute := now.Truncate(time.Minute); !minute.Equal(s.minute) {
+		s.minute = minute
+		s.spent = 0
+	}
+
+	rewritten := 0
+	for _, name := range s.lists {
+		if err := ctx.Err(); err != nil {
+			return rewritten, err
+		}
+
+		n, err := s.step(ctx, name)
+		rewritten += n
+		if err != nil {
+			return rewritten, err
+		}
+	}
+
+	return rewritten, nil
+}
+
+// Run calls RunOnce every interval until the context is cancelled.
+func (s *MaintenanceScheduler) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunOnce(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *MaintenanceScheduler) step(ctx context.Context, name string) (int, error) {
+	list, err := s.Store.LoadList(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	if len(list) == 0 {
+		return 0, nil
+	}
+
+	cursor, err := s.Store.LoadCursor(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	if cursor < 0 || cursor >= len(list) {
+		cursor = 0
+	}
+
+	left := s.Budget - s.spent
+	if left <= 0 {
+		// No budget left this minute, resume from the same chunk later
+		return 0, nil
+	}
+
+	size := s.ChunkSize
+	if size <= 0 {
+		size = defaultMaintenanceChunkSize
+	}
+	end := min(cursor+size, len(list))
+	cs, err := compactChunk(list, cursor, end, s.Config)
+	if err != nil {
+		return 0, err
+	}
+	if len(cs) > left {
+		// Compact a chunk small enough for the budget instead, which can't
+		// rewrite more keys than it has items
+		end = cursor + left
+		if cs, err = compactChunk(list, cursor, end, s.Config); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(cs) > 0 {
+		if err := list.ApplyChangeSet(cs, s.Config); err != nil {
+			return 0, err
+		}
+		if err := s.Store.SaveKeys(ctx, name, list, cs); err != nil {
+			return 0, err
+		}
+		s.spent += len(cs)
+	}
+
+	if end == len(list) {
+		end = 0
+	}
+	return len(cs), s.Store.SaveCursor(ctx, name, end)
+}
+
+// compactChunk computes the changes that spread items [from, to) evenly between
+// their surrounding keys. It returns no changes if the chunk is sorted and none
+// of its ranks is longer than the compacted ones would be.
+func compactChunk(list ReorderableList, from, to int, config *Config) (ChangeSet, error) {
+	first := list[from].GetKey()
+
+	after := bottomOf(first.bucket, config)
+	if from > 0 {
+		after = list[from-1].GetKey()
+	}
+	var next *Key
+	if to < len(list) {
+		k := list[to].GetKey()
+		next = &k
+	}
+
+	res, err := ReserveRange(after, next, to-from, config)
+	if errors.Is(err, ErrRebalanceRequired) {
+		// The surrounding keys leave no room; only a full normalise helps
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	healthy := true
+	prev := after
+	for i := from; i < to; i++ {
+		k := list[i].GetKey()
+		if len(k.rank) > res.length || k.Compare(prev) <= 0 {
+			healthy = false
+			break
+		}
+		prev = k
+	}
+	if healthy && (next == nil || prev.Compare(*next) < 0) {
+		return nil, nil
+	}
+
+	var cs ChangeSet
+	for i := from; i < to; i++ {
+		k, _ := res.Key(i - from)
+		if k.Compare(list[i].GetKey()) != 0 {
+			cs = append(cs, Change{Index: i, Key: k})
+		}
+	}
+	return cs, nil
+}
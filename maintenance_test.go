@@ -0,0 +1,170 @@
+package lexorank
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryMaintenanceStore struct {
+	lists   map[string]ReorderableList
+	cursors map[string]int
+	writes  int
+}
+
+func (m *memoryMaintenanceStore) LoadList(_ context.Context, name string) (ReorderableList, error) {
+	return m.lists[name], nil
+}
+
+func (m *memoryMaintenanceStore) SaveKeys(_ context.Context, _ string, _ ReorderableList, cs ChangeSet) error {
+	m.writes += len(cs)
+	return nil
+}
+
+func (m *memoryMaintenanceStore) LoadCursor(_ context.Context, name string) (int, error) {
+	return m.cursors[name], nil
+}
+
+func (m *memoryMaintenanceStore) SaveCursor(_ context.Context, name string, cursor int) error {
+	m.cursors[name] = cursor
+	return nil
+}
+
+func TestMaintenanceScheduler_Compacts(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	store := &memoryMaintenanceStore{
+		lists: map[string]ReorderableList{
+			"board": {
+				item(0, "0|aaaaaa"),
+				item(1, "0|aaaaab"),
+				item(2, "0|aaaaac"),
+				item(3, "0|aaaaad"),
+			},
+		},
+		cursors: map[string]int{},
+	}
+
+	now := time.Date(2026, 1, 1, 3, 0, 0, 0, time.UTC)
+	s := NewMaintenanceScheduler(store, DefaultConfig(), 100)
+	s.ChunkSize = 2
+	s.Now = func() time.Time { return now }
+	s.Register("board")
+
+	n, err := s.RunOnce(context.Background())
+	r.NoError(err)
+	a.Equal(2, n)
+	a.Equal(2, store.cursors["board"])
+
+	n, err = s.RunOnce(context.Background())
+	r.NoError(err)
+	a.Equal(2, n)
+	a.Equal(0, store.cursors["board"], "cursor wraps around at the end of the list")
+
+	list := store.lists["board"]
+	a.True(list.StrictlySorted())
+	for _, it := range list {
+		a.Less(len(it.GetKey().rank), 6, "keys have been compacted")
+	}
+
+	// Compacted chunks are left alone
+	n, err = s.RunOnce(context.Background())
+	r.NoError(err)
+	a.Zero(n)
+}
+
+func TestMaintenanceScheduler_BudgetAndWindow(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	store := &memoryMaintenanceStore{
+		lists: map[string]ReorderableList{
+			"board": {item(0, "0|aaaaaa"), item(1, "0|aaaaab"), item(2, "0|aaaaac")},
+		},
+		cursors: map[string]int{},
+	}
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := NewMaintenanceScheduler(store, DefaultConfig(), 2)
+	s.Now = func() time.Time { return now }
+	s.Window = func(t time.Time) bool { return t.Hour() < 6 }
+	s.Register("board")
+
+	n, err := s.RunOnce(context.Background())
+	r.NoError(err)
+	a.Zero(n, "outside the maintenance window")
+
+	now = now.Add(-9 * time.Hour)
+	n, err = s.RunOnce(context.Background())
+	r.NoError(err)
+	a.Equal(2, n, "chunk is shrunk to what the budget allows")
+	a.Equal(2, store.writes)
+	a.Equal(2, store.cursors["board"])
+
+	n, err = s.RunOnce(context.Background())
+	r.NoError(err)
+	a.Zero(n, "budget for this minute is spent")
+	a.Equal(2, store.cursors["board"])
+
+	now = now.Add(time.Minute)
+	_, err = s.RunOnce(context.Background())
+	r.NoError(err)
+	a.Zero(store.cursors["board"], "budget resets every minute")
+	a.True(store.lists["board"].StrictlySorted())
+}
+
+func TestMaintenanceScheduler_BudgetBelowChunkSize(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig()
+	list := make(ReorderableList, 20)
+	for i := range list {
+		list[i] = item(i, "0|aaaa"+string(defaultAlphabet[i+1]))
+	}
+	store := &memoryMaintenanceStore{
+		lists:   map[string]ReorderableList{"board": list},
+		cursors: map[string]int{},
+	}
+
+	now := time.Date(2026, 1, 1, 3, 0, 0, 0, time.UTC)
+	s := &MaintenanceScheduler{
+		Store:     store,
+		Config:    config,
+		Budget:    5,
+		ChunkSize: 10,
+		Now:       func() time.Time { return now },
+	}
+	s.Register("board")
+
+	for range 8 {
+		n, err := s.RunOnce(context.Background())
+		r.NoError(err)
+		a.LessOrEqual(n, 5)
+		now = now.Add(time.Minute)
+	}
+	a.Positive(store.writes)
+	a.True(list.StrictlySorted())
+	for _, it := range list {
+		a.Less(len(it.GetKey().rank), 5, "keys have been compacted")
+	}
+}
+
+func TestMaintenanceScheduler_Defaults(t *testing.T) {
+	r := require.New(t)
+
+	store := &memoryMaintenanceStore{
+		lists:   map[string]ReorderableList{"board": {item(0, "0|aaaaaa"), item(1, "0|aaaaab")}},
+		cursors: map[string]int{},
+	}
+	s := &MaintenanceScheduler{Store: store, Config: DefaultConfig(), Budget: 10}
+	s.Register("board")
+
+	n, err := s.RunOnce(context.Background())
+	r.NoError(err)
+	r.Equal(2, n)
+}
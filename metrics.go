@@ -0,0 +1,47 @@
+package lexorank
+
+// Metrics receives counters and observations from key generation and list
+// operations, so they can be exported to statsd, Prometheus or similar without
+// this package depending on a client library. Set it as Config.Metrics.
+// Methods are called synchronously from the operation and must be cheap and
+// safe for concurrent use. Embed NopMetrics to implement only some of them.
+type Metrics interface {
+	// BetweenCalled counts calls to Between, including the ones made while
+	// rebalancing and ones that fail.
+	BetweenCalled()
+
+	// RebalanceTriggered counts inserts that found no room for a new key and
+	// had to make some, whatever the RebalanceStrategy.
+	RebalanceTriggered()
+
+	// Normalized counts runs of Normalize, including the ones a rebalance
+	// fell back to.
+	Normalized()
+
+	// KeysRewritten counts the keys of existing items rewritten by list
+	// operations, the same ones an AuditLog records.
+	KeysRewritten(n int)
+
+	// RankLength observes the rank length of every key generated by Between
+	// or an append strategy.
+	RankLength(length int)
+}
+
+// NopMetrics implements Metrics by doing nothing.
+type NopMetrics struct{}
+
+func (NopMetrics) BetweenCalled()      {}
+func (NopMetrics) RebalanceTriggered() {}
+func (NopMetrics) Normalized()         {}
+func (NopMetrics) KeysRewritten(int)   {}
+func (NopMetrics) RankLength(int)      {}
+
+var _ Metrics = NopMetrics{}
+
+// metrics returns config.Metrics, or NopMetrics if it is unset.
+func (c *Config) metrics() Metrics {
+	if c.Metrics == nil {
+		return NopMetrics{}
+	}
+	return c.Metrics
+}
@@ -0,0 +1,77 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingMetrics struct {
+	between, rebalances, normalizations, rewritten int
+	lengths                                        []int
+}
+
+func (m *countingMetrics) BetweenCalled()        { m.between++ }
+func (m *countingMetrics) RebalanceTriggered()   { m.rebalances++ }
+func (m *countingMetrics) Normalized()           { m.normalizations++ }
+func (m *countingMetrics) KeysRewritten(n int)   { m.rewritten += n }
+func (m *countingMetrics) RankLength(length int) { m.lengths = append(m.lengths, length) }
+
+func TestMetrics(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	m := &countingMetrics{}
+	config := DefaultConfig().WithMaxRankLength(2).WithMetrics(m)
+
+	k, err := Between(mustKey("0|a"), mustKey("0|c"), config)
+	r.NoError(err)
+	a.Equal("0|b", k.String())
+	_, err = Between(mustKey("0|a"), mustKey("0|a0"), config)
+	r.Error(err)
+	a.Equal(2, m.between)
+	a.Equal([]int{1}, m.lengths)
+
+	// A full gap triggers a rebalance that falls back to a normalize
+	*m = countingMetrics{}
+	list := ReorderableList{item(0, "0|a"), item(1, "0|a0"), item(2, "0|a1")}
+	changed, err := list.Track(func() error {
+		_, err := list.Insert(1, config.WithRebalanceStrategy(RebalanceFullNormalize))
+		return err
+	})
+	r.NoError(err)
+	a.Equal(1, m.rebalances)
+	a.Equal(1, m.normalizations)
+	a.Equal(len(changed), m.rewritten)
+	a.NotEmpty(m.lengths)
+
+	// A fast path insert only generates a key
+	*m = countingMetrics{}
+	_, err = list.Insert(1, config)
+	r.NoError(err)
+	a.Zero(m.rebalances)
+	a.Zero(m.rewritten)
+	a.Len(m.lengths, 1)
+}
+
+// normalizeCounter only counts normalizations.
+type normalizeCounter struct {
+	NopMetrics
+	n int
+}
+
+func (c *normalizeCounter) Normalized() { c.n++ }
+
+func TestNopMetrics(t *testing.T) {
+	r := require.New(t)
+
+	m := &normalizeCounter{}
+	config := DefaultConfig().WithMetrics(m)
+	list := ReorderableList{item(0, "0|a"), item(1, "0|b")}
+	_, err := list.Normalize(config)
+	r.NoError(err)
+	_, err = list.Append(config)
+	r.NoError(err)
+	r.Equal(1, m.n)
+}
@@ -0,0 +1,156 @@
+package lexorank
+
+import (
+	"context"
+	"fmt"
+)
+
+// MigrationCheckpoint records how far a bucket migration has progressed. Done
+// items have already been moved from bucket From to bucket To, out of the
+// Total that were in From when the migration started.
+type MigrationCheckpoint struct {
+	From  uint8 `json:"from"`
+	To    uint8 `json:"to"`
+	Total int   `json:"total"`
+	Done  int   `json:"done"`
+}
+
+// MigrationStore gives a BucketMigration access to the persisted items of a
+// list and to the checkpoint it uses to resume after a crash.
+type MigrationStore interface {
+	// CountBucket returns the number of items with a key in the given bucket.
+	CountBucket(ctx context.Context, bucket uint8) (int, error)
+
+	// LoadBucket returns the last limit items with a key in the given bucket,
+	// ordered by key. Migrated items leave the bucket, so successive calls
+	// return the next chunk.
+	LoadBucket(ctx context.Context, bucket uint8, limit int) (ReorderableList, error)
+
+	// SaveChunk persists the changed keys of a chunk together with the
+	// checkpoint covering it. Both must be written atomically, e.g. in one
+	// transaction, or a resumed migration may hand out the same keys twice.
+	SaveChunk(ctx context.Context, chunk ReorderableList, cs ChangeSet, cp MigrationCheckpoint) error
+
+	// LoadCheckpoint returns the last saved checkpoint, or nil if no
+	// migration has been started.
+	LoadCheckpoint(ctx context.Context) (*MigrationCheckpoint, error)
+}
+
+// BucketMigration moves every item of a list from one bucket to another in
+// chunks, for tables too large to migrate with MigrateToBucket in one go. The
+// new keys are spread evenly across the target bucket in the order of the old
+// ones, and each one is determined by the item's position alone, so a
+// migration interrupted by a crash resumes from its last checkpoint and ends
+// with the same keys as one that ran without stopping.
+//
+// Chunks are migrated from the end of the list backwards, so that until the
+// migration is done the list stays ordered by CompareCyclic with the source
+// bucket as active: items not migrated yet sort before the ones already moved.
+// Items must not be added to the source bucket while a migration is running;
+// add them to the target bucket instead.
+type BucketMigration struct {
+	Store  MigrationStore
+	Config *Config
+
+	// ChunkSize is the number of items migrated per chunk
+	ChunkSize int
+
+	// OnChunk is called after each chunk has been saved, with the checkpoint
+	// covering it. A nil OnChunk is ignored.
+	OnChunk func(MigrationCheckpoint)
+}
+
+// NewBucketMigration creates a migration with a default chunk size of 1000.
+func NewBucketMigration(store MigrationStore, config *Config) *BucketMigration {
+	return &BucketMigration{
+		Store:     store,
+		Config:    config,
+		ChunkSize: 1000,
+	}
+}
+
+// Run migrates all items from bucket from to bucket to, resuming from the
+// stored checkpoint if there is one. It stops early if ctx is cancelled; a
+// later Run picks up where it stopped.
+func (m *BucketMigration) Run(ctx context.Context, from, to uint8) error {
+	if from > MaxBucket || to > MaxBucket {
+		return fmt.Errorf("invalid bucket migration: %d to %d (maximum %d)", from, to, MaxBucket)
+	}
+	if from == to {
+		return fmt.Errorf("cannot migrate bucket %d to itself", from)
+	}
+
+	cp, err := m.Store.LoadCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+	if cp != nil && cp.Done >= cp.Total {
+		if cp.From == from && cp.To == to {
+			return nil
+		}
+		// A finished checkpoint of an earlier rotation
+		cp = nil
+	}
+	if cp == nil {
+		total, err := m.Store.CountBucket(ctx, from)
+		if err != nil {
+			return err
+		}
+		cp = &MigrationCheckpoint{From: from, To: to, Total: total}
+	}
+	if cp.From != from || cp.To != to {
+		return fmt.Errorf("unfinished migration from bucket %d to %d in progress", cp.From, cp.To)
+	}
+	if cp.Total == 0 {
+		return nil
+	}
+
+	upper := MaxKey(to, m.Config)
+	res, err := ReserveRange(MinKey(to, m.Config), &upper, cp.Total, m.Config)
+	if err != nil {
+		return err
+	}
+
+	for cp.Done < cp.Total {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		chunk, err := m.Store.LoadBucket(ctx, from, min(max(m.ChunkSize, 1), cp.Total-cp.Done))
+		if err != nil {
+			return err
+		}
+		if len(chunk) == 0 {
+			return fmt.Errorf("bucket %d ran out of items after %d of %d", from, cp.Done, cp.Total)
+		}
+
+		// The chunk takes the keys just below the ones already handed out
+		first := cp.Total - cp.Done - len(chunk)
+		if first < 0 {
+			return fmt.Errorf("bucket %d has more items than the %d counted", from, cp.Total)
+		}
+		cs := make(ChangeSet, len(chunk))
+		for i := range chunk {
+			k, err := res.Key(first + i)
+			if err != nil {
+				return err
+			}
+			cs[i] = Change{Index: i, Key: k}
+		}
+		if err := chunk.ApplyChangeSet(cs, m.Config); err != nil {
+			return err
+		}
+
+		next := *cp
+		next.Done += len(chunk)
+		if err := m.Store.SaveChunk(ctx, chunk, cs, next); err != nil {
+			return err
+		}
+		*cp = next
+
+		if m.OnChunk != nil {
+			m.OnChunk(next)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package lexorank
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errCrash = errors.New("crash")
+
+type memoryMigrationStore struct {
+	items      ReorderableList
+	checkpoint *MigrationCheckpoint
+	crashAfter int
+	saves      int
+}
+
+func (m *memoryMigrationStore) CountBucket(_ context.Context, bucket uint8) (int, error) {
+	n := 0
+	for _, it := range m.items {
+		if it.GetKey().bucket == bucket {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *memoryMigrationStore) LoadBucket(_ context.Context, bucket uint8, limit int) (ReorderableList, error) {
+	var chunk ReorderableList
+	for _, it := range m.items {
+		if it.GetKey().bucket == bucket {
+			chunk = append(chunk, &SnapshotItem{ID: it.(*SnapshotItem).ID, Key: it.GetKey()})
+		}
+	}
+	sort.Sort(chunk)
+	return chunk[max(len(chunk)-limit, 0):], nil
+}
+
+func (m *memoryMigrationStore) SaveChunk(_ context.Context, chunk ReorderableList, _ ChangeSet, cp MigrationCheckpoint) error {
+	if m.crashAfter > 0 && m.saves == m.crashAfter {
+		return errCrash
+	}
+	m.saves++
+	for _, c := range chunk {
+		for _, it := range m.items {
+			if it.(*SnapshotItem).ID == c.(*SnapshotItem).ID {
+				it.SetKey(c.GetKey())
+			}
+		}
+	}
+	m.checkpoint = &cp
+	return nil
+}
+
+func (m *memoryMigrationStore) LoadCheckpoint(_ context.Context) (*MigrationCheckpoint, error) {
+	return m.checkpoint, nil
+}
+
+func migrationItems(t *testing.T, n int) ReorderableList {
+	keys, err := Spread(2, n, DefaultConfig())
+	require.NoError(t, err)
+
+	l := make(ReorderableList, n)
+	for i, k := range keys {
+		l[i] = &SnapshotItem{ID: string(rune('a' + i)), Key: k}
+	}
+	return l
+}
+
+func TestBucketMigration_Run(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	store := &memoryMigrationStore{items: migrationItems(t, 7)}
+	m := NewBucketMigration(store, DefaultConfig())
+	m.ChunkSize = 3
+
+	var progress []int
+	m.OnChunk = func(cp MigrationCheckpoint) {
+		a.Equal(7, cp.Total)
+		progress = append(progress, cp.Done)
+	}
+
+	r.NoError(m.Run(context.Background(), 2, 0))
+	a.Equal([]int{3, 6, 7}, progress)
+
+	want, err := Spread(0, 7, DefaultConfig())
+	r.NoError(err)
+	for i, it := range store.items {
+		a.Equal(want[i].String(), it.GetKey().String())
+	}
+
+	// Running a finished migration again is a no-op
+	progress = nil
+	r.NoError(m.Run(context.Background(), 2, 0))
+	a.Empty(progress)
+}
+
+func TestBucketMigration_Resume(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	store := &memoryMigrationStore{items: migrationItems(t, 10), crashAfter: 2}
+	m := NewBucketMigration(store, DefaultConfig())
+	m.ChunkSize = 3
+
+	r.ErrorIs(m.Run(context.Background(), 2, 0), errCrash)
+	r.NotNil(store.checkpoint)
+	a.Equal(6, store.checkpoint.Done)
+
+	// A list caught halfway keeps its order across both buckets
+	halfway := make(ReorderableList, len(store.items))
+	copy(halfway, store.items)
+	halfway.SortCyclic(2)
+	a.Equal(store.items, halfway)
+
+	store.crashAfter = 0
+	var progress []int
+	m.OnChunk = func(cp MigrationCheckpoint) {
+		progress = append(progress, cp.Done)
+	}
+	r.NoError(m.Run(context.Background(), 2, 0))
+	a.Equal([]int{9, 10}, progress)
+
+	want, err := Spread(0, 10, DefaultConfig())
+	r.NoError(err)
+	for i, it := range store.items {
+		a.Equal(want[i].String(), it.GetKey().String())
+	}
+
+	r.Error(m.Run(context.Background(), 0, 0))
+}
+
+func TestBucketMigration_Cancelled(t *testing.T) {
+	r := require.New(t)
+
+	store := &memoryMigrationStore{items: migrationItems(t, 4)}
+	m := NewBucketMigration(store, DefaultConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r.ErrorIs(m.Run(ctx, 2, 0), context.Canceled)
+	r.Nil(store.checkpoint)
+}
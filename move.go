@@ -0,0 +1,100 @@
+package lexorank
+
+import "sort"
+
+// Move gives the item at index from a new key so that it lands at index to,
+// and moves it there in the slice. Both indices refer to the list as it is
+// before the move, so to is also the item's final index. Moving to an adjacent
+// slot or to either end works like any other move.
+//
+// Making room may rewrite the keys of other items, subject to
+// MaxKeysRewritten. The final indices of every item with a new key, including
+// the moved one, are returned in ascending order, for use with ChangeSet.
+func (l ReorderableList) Move(from, to uint, config *Config) ([]int, error) {
+	if from >= uint(len(l)) || to >= uint(len(l)) {
+		return nil, ErrOutOfBounds
+	}
+	if from == to {
+		return nil, nil
+	}
+	if err := l.checkSize(config); err != nil {
+		return nil, err
+	}
+
+	it := l[from]
+	rest := make(ReorderableList, 0, len(l)-1)
+	rest = append(append(rest, l[:from]...), l[from+1:]...)
+
+	before := make([]Key, len(rest))
+	for i, r := range rest {
+		before[i] = r.GetKey()
+	}
+
+	inner := config
+	if config.Audit != nil {
+		// The rest of the list is rebalanced without the moved item, so its
+		// entries are recorded separately and shifted to the final indices
+		inner = config.WithAudit(&AuditLog{})
+	}
+	k, err := rest.Insert(to, inner)
+	if inner != config {
+		for _, e := range inner.Audit.Entries() {
+			if e.Index >= int(to) {
+				e.Index++
+			}
+			config.Audit.add(e)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if from < to {
+		copy(l[from:to], l[from+1:to+1])
+	} else {
+		copy(l[to+1:from+1], l[to:from])
+	}
+	l[to] = it
+	l.setKey(int(to), *k, AuditMove, config)
+
+	touched := []int{int(to)}
+	for i, r := range rest {
+		if r.GetKey().Compare(before[i]) == 0 {
+			continue
+		}
+		if i < int(to) {
+			touched = append(touched, i)
+		} else {
+			touched = append(touched, i+1)
+		}
+	}
+	sort.Ints(touched)
+
+	return touched, nil
+}
+
+// MoveBefore moves the item at index i so that it sits directly before the
+// item currently at index target, as reported by drag and drop UIs. Indices
+// refer to the list before the move. It returns the same as Move.
+func (l ReorderableList) MoveBefore(i, target uint, config *Config) ([]int, error) {
+	if i >= uint(len(l)) || target >= uint(len(l)) {
+		return nil, ErrOutOfBounds
+	}
+	if i < target {
+		return l.Move(i, target-1, config)
+	}
+	return l.Move(i, target, config)
+}
+
+// MoveAfter moves the item at index i so that it sits directly after the item
+// currently at index target. Indices refer to the list before the move. It
+// returns the same as Move.
+func (l ReorderableList) MoveAfter(i, target uint, config *Config) ([]int, error) {
+	if i >= uint(len(l)) || target >= uint(len(l)) {
+		return nil, ErrOutOfBounds
+	}
+	if i <= target {
+		return l.Move(i, target, config)
+	}
+	return l.Move(i, target+1, config)
+}
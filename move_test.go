@@ -0,0 +1,110 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func moveList() ReorderableList {
+	return ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|c"), item(3, "0|d")}
+}
+
+func moveIDs(l ReorderableList) []string {
+	out := make([]string, len(l))
+	for i, it := range l {
+		out[i] = it.(Identifiable).GetID()
+	}
+	return out
+}
+
+func TestReorderableList_Move(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to uint
+		want     []string
+	}{
+		{"forward", 0, 2, []string{"1", "2", "0", "3"}},
+		{"backward", 3, 1, []string{"0", "3", "1", "2"}},
+		{"adjacent forward", 1, 2, []string{"0", "2", "1", "3"}},
+		{"adjacent backward", 2, 1, []string{"0", "2", "1", "3"}},
+		{"to end", 0, 3, []string{"1", "2", "3", "0"}},
+		{"to start", 3, 0, []string{"3", "0", "1", "2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+			a := assert.New(t)
+
+			l := moveList()
+			touched, err := l.Move(tt.from, tt.to, DefaultConfig())
+			r.NoError(err)
+			a.Equal(tt.want, moveIDs(l))
+			a.True(l.StrictlySorted())
+			a.Equal([]int{int(tt.to)}, touched)
+		})
+	}
+}
+
+func TestReorderableList_Move_Rebalance(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	l := ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|b0"), item(3, "0|c")}
+	touched, err := l.Move(3, 2, DefaultConfig().WithMaxRankLength(2))
+	r.NoError(err)
+	a.Equal([]string{"0", "1", "3", "2"}, moveIDs(l))
+	a.True(l.StrictlySorted())
+	a.Contains(touched, 2)
+	a.Greater(len(touched), 1, "the neighbours had to make room")
+
+	_, err = l.Move(4, 0, DefaultConfig())
+	a.ErrorIs(err, ErrOutOfBounds)
+
+	touched, err = l.Move(1, 1, DefaultConfig())
+	r.NoError(err)
+	a.Empty(touched)
+}
+
+func TestReorderableList_MoveBeforeAfter(t *testing.T) {
+	tests := []struct {
+		name      string
+		after     bool
+		i, target uint
+		want      []string
+	}{
+		{"before later item", false, 0, 2, []string{"1", "0", "2", "3"}},
+		{"before earlier item", false, 3, 1, []string{"0", "3", "1", "2"}},
+		{"before first", false, 2, 0, []string{"2", "0", "1", "3"}},
+		{"before next is a no-op", false, 1, 2, []string{"0", "1", "2", "3"}},
+		{"before itself is a no-op", false, 1, 1, []string{"0", "1", "2", "3"}},
+		{"after later item", true, 0, 2, []string{"1", "2", "0", "3"}},
+		{"after earlier item", true, 3, 1, []string{"0", "1", "3", "2"}},
+		{"after last", true, 0, 3, []string{"1", "2", "3", "0"}},
+		{"after previous is a no-op", true, 2, 1, []string{"0", "1", "2", "3"}},
+		{"after itself is a no-op", true, 2, 2, []string{"0", "1", "2", "3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+			a := assert.New(t)
+
+			l := moveList()
+			move := l.MoveBefore
+			if tt.after {
+				move = l.MoveAfter
+			}
+			_, err := move(tt.i, tt.target, DefaultConfig())
+			r.NoError(err)
+			a.Equal(tt.want, moveIDs(l))
+			a.True(l.StrictlySorted())
+		})
+	}
+
+	l := moveList()
+	_, err := l.MoveBefore(0, 4, DefaultConfig())
+	assert.ErrorIs(t, err, ErrOutOfBounds)
+	_, err = l.MoveAfter(4, 0, DefaultConfig())
+	assert.ErrorIs(t, err, ErrOutOfBounds)
+}
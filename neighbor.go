@@ -0,0 +1,86 @@
+package lexorank
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+)
+
+// NeighborInsert generates a key between two neighbouring keys without
+// requiring the whole list to be loaded. This is the common production
+// pattern where only the rows directly before and after the target position
+// are fetched from storage.
+//
+// A nil prev means the new key goes to the start of the list and a nil next
+// means it goes to the end, in which case the configured append strategy is
+// used. If both are nil the middle of bucket 0 is returned.
+//
+// When there is no room between the neighbours a *RebalanceWindowError is
+// returned describing the range of rows to load for a local rebalance. It
+// wraps ErrRebalanceRequired so it can be checked with errors.Is.
+func NeighborInsert(prev, next *Key, config *Config) (*Key, error) {
+	var (
+		k   *Key
+		err error
+	)
+
+	switch {
+	case prev == nil && next == nil:
+		mid := middleOf(0, config)
+		return &mid, nil
+	case prev == nil:
+		k, err = SmartPrepend(*next, config)
+	case next == nil:
+		k, err = SmartAppend(*prev, config)
+	default:
+		k, err = Between(*prev, *next, config)
+	}
+
+	if err == nil {
+		return k, nil
+	}
+	if !errors.Is(err, ErrRebalanceRequired) && !errors.Is(err, ErrOutOfBounds) {
+		return nil, err
+	}
+
+	return nil, rebalanceWindow(prev, next, config)
+}
+
+// rebalanceWindow finds the smallest rank prefix shared by both neighbours and
+// widens it by one digit, so the window always contains more than just the two
+// exhausted keys.
+func rebalanceWindow(prev, next *Key, config *Config) *RebalanceWindowError {
+	var bucket uint8
+	alphabet := config.alphabet()
+	lo, hi := []byte{alphabet.Min()}, []byte{alphabet.Max()}
+	if prev != nil {
+		bucket = prev.bucket
+		lo = prev.rank
+	}
+	if next != nil {
+		bucket = next.bucket
+		hi = next.rank
+	}
+
+	n := 0
+	for n < len(lo) && n < len(hi) && lo[n] == hi[n] {
+		n++
+	}
+	if n > 0 {
+		n--
+	}
+	prefix := lo[:n]
+
+	from := bytes.Clone(prefix)
+	if len(from) == 0 {
+		from = []byte{alphabet.Min()}
+	}
+
+	length := max(config.MaxRankLength, len(prefix)+1)
+	to := append(bytes.Clone(prefix), bytes.Repeat([]byte{alphabet.Max()}, length-len(prefix))...)
+
+	return &RebalanceWindowError{
+		From: config.adopt(*makeKey(bucket, from)),
+		To:   config.adopt(*makeKey(bucket, to)),
+	}
+}
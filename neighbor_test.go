@@ -0,0 +1,77 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNeighborInsert_Between(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	prev, err := ParseKey("1|a")
+	r.NoError(err)
+	next, err := ParseKey("1|b")
+	r.NoError(err)
+
+	got, err := NeighborInsert(prev, next, DefaultConfig())
+	r.NoError(err)
+	a.Equal("1|aU", got.String())
+}
+
+func TestNeighborInsert_Ends(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	k, err := ParseKey("1|aaa")
+	r.NoError(err)
+
+	first, err := NeighborInsert(nil, k, DefaultConfig())
+	r.NoError(err)
+	a.True(first.Compare(*k) < 0, "prepended key should sort first")
+
+	last, err := NeighborInsert(k, nil, DefaultConfig())
+	r.NoError(err)
+	a.True(last.Compare(*k) > 0, "appended key should sort last")
+
+	only, err := NeighborInsert(nil, nil, DefaultConfig())
+	r.NoError(err)
+	a.Equal(MiddleOf(0).String(), only.String())
+}
+
+func TestNeighborInsert_RebalanceWindow(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	prev, err := ParseKey("1|aaaaaa")
+	r.NoError(err)
+	next, err := ParseKey("1|aaaaab")
+	r.NoError(err)
+
+	got, err := NeighborInsert(prev, next, DefaultConfig())
+	r.Nil(got)
+	r.ErrorIs(err, ErrRebalanceRequired)
+
+	var window *RebalanceWindowError
+	r.True(errors.As(err, &window))
+	a.Equal("1|aaaa", window.From.String())
+	a.Equal("1|aaaazz", window.To.String())
+	a.True(window.From.Compare(*prev) <= 0)
+	a.True(window.To.Compare(*next) >= 0)
+}
+
+func TestNeighborInsert_MixedBuckets(t *testing.T) {
+	r := require.New(t)
+
+	prev, err := ParseKey("0|a")
+	r.NoError(err)
+	next, err := ParseKey("1|b")
+	r.NoError(err)
+
+	_, err = NeighborInsert(prev, next, DefaultConfig())
+	r.Error(err)
+	r.NotErrorIs(err, ErrRebalanceRequired)
+}
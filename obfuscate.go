@@ -0,0 +1,107 @@
+package lexorank
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"math/rand"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Obfuscator transforms keys before they are exposed to untrusted clients and
+// back again when clients send them in requests. Implementations must be
+// strictly order preserving, so clients can still compare obfuscated keys and
+// send back neighbour keys for inserts.
+type Obfuscator interface {
+	Obfuscate(k Key) string
+	Reveal(s string) (*Key, error)
+}
+
+// KeyedObfuscator is an order-preserving Obfuscator keyed by a secret. Every
+// rank digit is mapped through a strictly increasing table derived from the
+// secret and the digits before it, and emitted as two characters. This hides
+// the raw digit values, and therefore list density and size, while keeping
+// lexicographic order intact.
+//
+// The output reveals the order of keys and the length of their ranks, as any
+// order-preserving scheme must. It is obfuscation, not encryption.
+type KeyedObfuscator struct {
+	secret []byte
+}
+
+var _ Obfuscator = (*KeyedObfuscator)(nil)
+
+// NewKeyedObfuscator returns an order-preserving obfuscator for the secret.
+func NewKeyedObfuscator(secret []byte) *KeyedObfuscator {
+	return &KeyedObfuscator{secret: append([]byte(nil), secret...)}
+}
+
+// Obfuscate returns the obfuscated form of a key. It is itself a valid key
+// for the default alphabet, so it can be parsed and compared as usual.
+func (o *KeyedObfuscator) Obfuscate(k Key) string {
+	base := len(defaultAlphabet)
+
+	out := make([]byte, 0, 2+2*len(k.rank))
+	out = append(out, byte(k.bucket+'0'), k.Separator())
+	for i, c := range k.rank {
+		d := max(Base75Alphabet.digits([]byte{c})[0], 0)
+		v := o.table(k.bucket, k.rank[:i])[d]
+		out = append(out, defaultAlphabet[v/base], defaultAlphabet[v%base])
+	}
+
+	return string(out)
+}
+
+// Reveal recovers the original key from its obfuscated form.
+func (o *KeyedObfuscator) Reveal(s string) (*Key, error) {
+	obf, err := ParseKey(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(obf.rank)%2 != 0 {
+		return nil, errors.New("invalid obfuscated key length")
+	}
+
+	base := len(defaultAlphabet)
+	digits := Base75Alphabet.digits(obf.rank)
+	rank := make([]byte, 0, len(digits)/2)
+	for i := 0; i < len(digits); i += 2 {
+		v := digits[i]*base + digits[i+1]
+		t := o.table(obf.bucket, rank)
+		d := sort.SearchInts(t, v)
+		if d == len(t) || t[d] != v {
+			return nil, errors.New("invalid obfuscated key")
+		}
+		rank = append(rank, defaultAlphabet[d])
+	}
+
+	k, err := parseRaw(obf.bucket, rank)
+	if err != nil {
+		return nil, err
+	}
+	*k = k.WithSeparator(obf.Separator())
+	return k, nil
+}
+
+// table returns a strictly increasing mapping from digit values to values in
+// [0, base*base), seeded by the secret, bucket and preceding digits.
+func (o *KeyedObfuscator) table(bucket uint8, prefix []byte) []int {
+	mac := hmac.New(sha256.New, o.secret)
+	mac.Write([]byte{bucket})
+	mac.Write(prefix)
+	sum := mac.Sum(nil)
+
+	base := len(defaultAlphabet)
+	r := rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(sum))))
+
+	// Gaps of 1..base-1 keep the largest value below base*base
+	t := make([]int, base)
+	v := -1
+	for d := range t {
+		v += 1 + r.Intn(base-1)
+		t[d] = v
+	}
+	return t
+}
@@ -0,0 +1,54 @@
+package lexorank
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyedObfuscator_RoundTrip(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	o := NewKeyedObfuscator([]byte("secret"))
+
+	for _, s := range []string{"0|0", "0|U", "1|aaaaaa", "2|zzzzzz", "0|0u1LWZ"} {
+		k := mustKey(s)
+		obf := o.Obfuscate(k)
+		a.NotEqual(s, obf)
+
+		back, err := o.Reveal(obf)
+		r.NoError(err)
+		a.Equal(s, back.String())
+	}
+}
+
+func TestKeyedObfuscator_PreservesOrder(t *testing.T) {
+	a := assert.New(t)
+
+	o := NewKeyedObfuscator([]byte("secret"))
+
+	keys := []string{"0|0", "0|00U", "0|a", "0|a0", "0|aU", "0|aaaaaa", "0|aaaaab", "0|b", "0|z", "0|zz", "1|0"}
+	a.True(sort.StringsAreSorted(keys))
+
+	obf := make([]string, len(keys))
+	for i, s := range keys {
+		obf[i] = o.Obfuscate(mustKey(s))
+	}
+	a.True(sort.StringsAreSorted(obf), "obfuscated keys must sort like the originals")
+
+	other := NewKeyedObfuscator([]byte("other"))
+	a.NotEqual(o.Obfuscate(mustKey("0|aaaaaa")), other.Obfuscate(mustKey("0|aaaaaa")))
+}
+
+func TestKeyedObfuscator_RevealInvalid(t *testing.T) {
+	o := NewKeyedObfuscator([]byte("secret"))
+
+	_, err := o.Reveal("0|abc")
+	assert.Error(t, err)
+
+	_, err = o.Reveal("nope")
+	assert.Error(t, err)
+}
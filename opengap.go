@@ -0,0 +1,124 @@
+package lexorank
+
+// OpenGap makes room for a new key at position, between the items at
+// position-1 and position, by rewriting as few keys as possible. It looks for
+// the smallest run of items next to the gap whose outer neighbours leave
+// enough room to spread the run and one free slot evenly, and rewrites only
+// that run. Position 0 and len(l) open room at the start and end of the list,
+// against the edges of the bucket.
+//
+// The run is found by widening it geometrically and then narrowing it down by
+// bisection, which finds the smallest run in sorted lists. Nothing is modified
+// if it fails. A *RewriteLimitError or *RebalanceBudgetError is returned if
+// no run within MaxKeysRewritten or MaxRebalanceWrites fits but a larger one
+// would, with Required set to one more than the limit, and
+// ErrNormalizationRequired if no run fits at all. The indices of the rewritten
+// items are returned in ascending order.
+func (l ReorderableList) OpenGap(position uint, config *Config) ([]int, error) {
+	if position > uint(len(l)) {
+		return nil, ErrOutOfBounds
+	}
+
+	if err := l.checkSize(config); err != nil {
+		return nil, err
+	}
+	if err := l.checkBuckets(config); err != nil {
+		return nil, err
+	}
+
+	p := int(position)
+	limit := len(l)
+	if config.MaxKeysRewritten > 0 {
+		limit = min(limit, config.MaxKeysRewritten)
+	}
+	if config.MaxRebalanceWrites > 0 {
+		limit = min(limit, config.MaxRebalanceWrites)
+	}
+
+	// Widen the run until it fits, without going past the limit
+	lo, hi := -1, 0
+	i, keys, ok := l.fitRun(hi, p, config)
+	for !ok && hi < limit {
+		lo, hi = hi, min(max(2*hi, 1), limit)
+		i, keys, ok = l.fitRun(hi, p, config)
+	}
+	if !ok {
+		if limit == len(l) {
+			return nil, ErrNormalizationRequired
+		}
+		if _, _, ok := l.fitRun(len(l), p, config); !ok {
+			return nil, ErrNormalizationRequired
+		}
+		if config.MaxKeysRewritten > 0 && limit+1 > config.MaxKeysRewritten {
+			return nil, &RewriteLimitError{Limit: config.MaxKeysRewritten, Required: limit + 1}
+		}
+		return nil, &RebalanceBudgetError{Limit: config.MaxRebalanceWrites}
+	}
+
+	// Narrow it down to the smallest size that fits
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		if j, k, ok := l.fitRun(mid, p, config); ok {
+			hi, i, keys = mid, j, k
+		} else {
+			lo = mid
+		}
+	}
+
+	var changed []int
+	for j, k := range keys {
+		if l[i+j].GetKey().Compare(k) == 0 {
+			continue
+		}
+		l.setKey(i+j, k, AuditRebalance, config)
+		changed = append(changed, i+j)
+	}
+	return changed, nil
+}
+
+// fitRun returns the start and new keys of the first run of size items
+// touching the gap before p that has room to be spread out, and reports false
+// if there is none.
+func (l ReorderableList) fitRun(size, p int, config *Config) (int, Keys, bool) {
+	// The run covers [i, i+size) and must touch the gap
+	for i := max(p-size, 0); i <= p && i+size <= len(l); i++ {
+		if keys, ok := l.spreadRun(i, size, p, config); ok {
+			return i, keys, true
+		}
+	}
+	return 0, nil, false
+}
+
+// spreadRun computes new keys for the size items starting at i, spread evenly
+// between their outer neighbours with a free slot left before the item at p.
+// It reports false if there is no room for them.
+func (l ReorderableList) spreadRun(i, size, p int, config *Config) (Keys, bool) {
+	var bucket uint8
+	switch {
+	case i > 0:
+		bucket = l[i-1].GetKey().bucket
+	case len(l) > 0:
+		bucket = l[0].GetKey().bucket
+	}
+
+	after := bottomOf(bucket, config)
+	if i > 0 {
+		after = l[i-1].GetKey()
+	}
+	var next *Key
+	if i+size < len(l) {
+		k := l[i+size].GetKey()
+		next = &k
+	}
+
+	res, err := ReserveRange(after, next, size+1, config)
+	if err != nil {
+		return nil, false
+	}
+
+	slots := res.Keys()
+	keys := make(Keys, 0, size)
+	keys = append(keys, slots[:p-i]...)
+	keys = append(keys, slots[p-i+1:]...)
+	return keys, true
+}
@@ -0,0 +1,141 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func denseList() ReorderableList {
+	return ReorderableList{
+		item(0, "0|a"),
+		item(1, "0|m"),
+		item(2, "0|m0"),
+		item(3, "0|m1"),
+		item(4, "0|m2"),
+		item(5, "0|n"),
+		item(6, "0|z"),
+	}
+}
+
+func TestReorderableList_OpenGap(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig().WithMaxRankLength(2)
+
+	list := denseList()
+	changed, err := list.OpenGap(3, config)
+	r.NoError(err)
+	a.Equal([]int{1, 2}, changed, "m0 equals m, so a pair has to move")
+	a.True(list.StrictlySorted())
+	_, err = Between(list[2].GetKey(), list[3].GetKey(), config)
+	a.NoError(err)
+	a.Equal("0|a", list[0].GetKey().String())
+	a.Equal("0|z", list[6].GetKey().String())
+
+	list = denseList()
+	changed, err = list.OpenGap(2, config)
+	r.NoError(err)
+	a.Equal([]int{1}, changed, "m moves down towards a")
+
+	list = ReorderableList{item(0, "0|a"), item(1, "0|b")}
+	changed, err = list.OpenGap(1, config)
+	r.NoError(err)
+	a.Empty(changed, "there already is room")
+
+	_, err = denseList().OpenGap(8, config)
+	a.ErrorIs(err, ErrOutOfBounds)
+
+	_, err = denseList().OpenGap(3, config.WithMaxKeysRewritten(1))
+	a.ErrorIs(err, ErrRewriteLimitExceeded)
+
+	tiny := DefaultConfig().WithMaxRankLength(1).WithAlphabet(mustAlphabet("abc"))
+	full := ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|c")}
+	_, err = full.OpenGap(1, tiny)
+	a.ErrorIs(err, ErrNormalizationRequired)
+}
+
+func TestReorderableList_MinimalRebalance(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig().WithMaxRankLength(2).WithMinimalRebalance(true)
+
+	list := denseList()
+	k, e, err := list.InsertExplain(3, config)
+	r.NoError(err)
+	a.Equal(PathLocalRebalance, e.Path)
+	a.Len(e.Rewritten, 2)
+	a.True(list[2].GetKey().Compare(*k) < 0 && k.Compare(list[3].GetKey()) < 0)
+
+	list = ReorderableList{item(0, "0|a"), item(1, "0|z")}
+	_, e, err = list.AppendExplain(config.WithMaxRankLength(1))
+	r.NoError(err)
+	a.Equal([]int{1}, e.Rewritten)
+
+	_, e, err = list.PrependExplain(config.WithMaxRankLength(1))
+	r.NoError(err)
+	a.Equal(PathDirect, e.Path)
+}
+
+// packedList returns n items with consecutive two character keys, so a gap in
+// the middle only opens once the run reaches one of the ends.
+func packedList(n int) ReorderableList {
+	l := make(ReorderableList, n)
+	for i := range l {
+		l[i] = item(i, "0|"+string(defaultAlphabet[30+(i+1)/75])+string(defaultAlphabet[(i+1)%75]))
+	}
+	return l
+}
+
+func TestReorderableList_OpenGap_Packed(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig().WithMaxRankLength(2)
+
+	list := packedList(2000)
+	before := list.Keys()
+	_, err := list.OpenGap(1000, config.WithMaxKeysRewritten(10))
+	a.ErrorIs(err, ErrRewriteLimitExceeded)
+	a.Equal(before, list.Keys(), "nothing is modified")
+
+	_, err = list.OpenGap(1000, config.WithMaxRebalanceWrites(10))
+	a.ErrorIs(err, ErrRebalanceBudgetExceeded)
+
+	changed, err := list.OpenGap(1000, config)
+	r.NoError(err)
+	a.Len(changed, 1000, "the run reaches the nearest end")
+	a.True(list.StrictlySorted())
+	_, err = Between(list[999].GetKey(), list[1000].GetKey(), config)
+	a.NoError(err)
+}
+
+func TestReorderableList_MinimalRebalance_RewriteLimit(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// No run fits, not even the whole list, so the insert would have to
+	// normalize every key
+	config := DefaultConfig().WithMaxRankLength(1).WithMinimalRebalance(true).WithMaxKeysRewritten(2)
+	list := make(ReorderableList, 75)
+	for i := range list {
+		list[i] = item(i, "0|"+string(defaultAlphabet[i]))
+	}
+	before := list.Keys()
+
+	_, err := list.Insert(10, config)
+	r.ErrorIs(err, ErrRewriteLimitExceeded)
+	var limit *RewriteLimitError
+	r.ErrorAs(err, &limit)
+	a.Equal(2, limit.Limit)
+	a.Equal(75, limit.Required)
+	a.Equal(before, list.Keys(), "nothing is modified")
+
+	// As without MinimalRebalance
+	_, err = list.Insert(10, config.WithMinimalRebalance(false))
+	a.ErrorIs(err, ErrRewriteLimitExceeded)
+	a.Equal(before, list.Keys())
+}
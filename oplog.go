@@ -0,0 +1,130 @@
+package lexorank
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// OpKind identifies a list operation in an operation log.
+type OpKind string
+
+const (
+	OpInsert  OpKind = "insert"
+	OpAppend  OpKind = "append"
+	OpPrepend OpKind = "prepend"
+	OpMove    OpKind = "move"
+)
+
+// Op is a single logical list operation and its inputs. Operations refer to
+// items by position only, so a log can be replayed without any clock, random
+// source or ID generator and always yields the same keys for the same list and
+// configuration.
+type Op struct {
+	Kind OpKind `json:"op"`
+
+	// ID labels the item created by insert, append and prepend operations
+	ID string `json:"id,omitempty"`
+
+	// Position is the insert position, or the final index of a moved item
+	Position uint `json:"position,omitempty"`
+
+	// From is the current index of the item being moved
+	From uint `json:"from,omitempty"`
+
+	// Key is the key the operation produced when it was recorded. If set,
+	// Replay checks that it produces the same key.
+	Key *Key `json:"key,omitempty"`
+}
+
+// WriteOps writes operations as JSON lines, one operation per line.
+func WriteOps(w io.Writer, ops []Op) error {
+	enc := json.NewEncoder(w)
+	for _, op := range ops {
+		if err := enc.Encode(op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadOps reads operations written by WriteOps.
+func ReadOps(r io.Reader) ([]Op, error) {
+	dec := json.NewDecoder(r)
+
+	var ops []Op
+	for {
+		var op Op
+		err := dec.Decode(&op)
+		if err == io.EOF {
+			return ops, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading operation %d", len(ops))
+		}
+		ops = append(ops, op)
+	}
+}
+
+// Apply performs a single operation and returns the resulting list along with
+// the key it produced. Created items are *Item[struct{}] labelled with the
+// operation's ID. Existing items may have their keys rewritten in place.
+func (l ReorderableList) Apply(op Op, config *Config) (ReorderableList, Key, error) {
+	switch op.Kind {
+	case OpInsert, OpAppend, OpPrepend:
+		position := op.Position
+		switch op.Kind {
+		case OpAppend:
+			position = uint(len(l))
+		case OpPrepend:
+			position = 0
+		}
+
+		k, err := l.Insert(position, config)
+		if err != nil {
+			return l, Key{}, err
+		}
+		return insertAt(l, position, &Item[struct{}]{ID: op.ID, Rank: *k}), *k, nil
+
+	case OpMove:
+		if _, err := l.Move(op.From, op.Position, config); err != nil {
+			return l, Key{}, err
+		}
+		return l, l[op.Position].GetKey(), nil
+
+	default:
+		return l, Key{}, errors.Errorf("unknown operation %q", op.Kind)
+	}
+}
+
+// Replay applies the operations in order, starting from initial, and returns
+// the final list. Keys of the initial items are rewritten in place, so pass a
+// fresh copy, for example one decoded from a snapshot. If a recorded key does
+// not match the replayed one a *ReplayDivergenceError is returned, pointing at
+// the first operation where two replicas went apart.
+func Replay(initial ReorderableList, ops []Op, config *Config) (ReorderableList, error) {
+	l := append(ReorderableList{}, initial...)
+	for i, op := range ops {
+		var (
+			k   Key
+			err error
+		)
+		l, k, err = l.Apply(op, config)
+		if err != nil {
+			return l, errors.Wrapf(err, "operation %d (%s)", i, op.Kind)
+		}
+		if op.Key != nil && op.Key.Compare(k) != 0 {
+			return l, &ReplayDivergenceError{Index: i, Op: op, Expected: *op.Key, Actual: k}
+		}
+	}
+	return l, nil
+}
+
+// insertAt returns the list with it inserted at position.
+func insertAt(l ReorderableList, position uint, it Reorderable) ReorderableList {
+	l = append(l, nil)
+	copy(l[position+1:], l[position:])
+	l[position] = it
+	return l
+}
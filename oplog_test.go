@@ -0,0 +1,98 @@
+package lexorank
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func replayOps() []Op {
+	return []Op{
+		{Kind: OpAppend, ID: "a"},
+		{Kind: OpAppend, ID: "b"},
+		{Kind: OpPrepend, ID: "c"},
+		{Kind: OpInsert, ID: "d", Position: 1},
+		{Kind: OpMove, From: 0, Position: 3},
+	}
+}
+
+func TestReplay(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig()
+	initial := func() ReorderableList {
+		return ReorderableList{item(1, "0|U")}
+	}
+
+	first, err := Replay(initial(), replayOps(), config)
+	r.NoError(err)
+	r.Len(first, 5)
+	r.True(first.StrictlySorted())
+
+	ids := make([]string, len(first))
+	for i, it := range first {
+		ids[i] = it.(Identifiable).GetID()
+	}
+	a.Equal([]string{"d", "1", "a", "c", "b"}, ids)
+
+	// Replaying against a fresh copy reproduces the same state
+	second, err := Replay(initial(), replayOps(), config)
+	r.NoError(err)
+	for i := range first {
+		a.Equal(first[i].GetKey(), second[i].GetKey())
+	}
+}
+
+func TestReplay_Divergence(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig()
+
+	// Record the keys produced by one replica
+	var recorded []Op
+	l := ReorderableList{}
+	for _, op := range replayOps()[:4] {
+		var (
+			k   Key
+			err error
+		)
+		l, k, err = l.Apply(op, config)
+		r.NoError(err)
+		op.Key = &k
+		recorded = append(recorded, op)
+	}
+
+	var buf bytes.Buffer
+	r.NoError(WriteOps(&buf, recorded))
+	a.Equal(4, bytes.Count(buf.Bytes(), []byte("\n")))
+
+	ops, err := ReadOps(&buf)
+	r.NoError(err)
+	a.Equal(recorded, ops)
+
+	_, err = Replay(ReorderableList{}, ops, config)
+	r.NoError(err)
+
+	// A replica with a different configuration diverges on the first insert
+	_, err = Replay(ReorderableList{}, ops, config.WithAppendStrategy(AppendStrategyStep))
+	a.ErrorIs(err, ErrReplayDiverged)
+
+	var diverged *ReplayDivergenceError
+	r.True(errors.As(err, &diverged))
+	a.Equal(1, diverged.Index)
+}
+
+func TestApply_Invalid(t *testing.T) {
+	a := assert.New(t)
+
+	l := ReorderableList{item(1, "0|a")}
+	_, _, err := l.Apply(Op{Kind: OpMove, From: 1}, DefaultConfig())
+	a.ErrorIs(err, ErrOutOfBounds)
+	_, _, err = l.Apply(Op{Kind: "swap"}, DefaultConfig())
+	a.Error(err)
+}
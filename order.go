@@ -0,0 +1,77 @@
+package lexorank
+
+import (
+	"sort"
+)
+
+// compareItems orders two items by key, falling back to the configured
+// TieBreaker for equal keys.
+func compareItems(a, b Reorderable, config *Config) int {
+	if c := a.GetKey().Compare(b.GetKey()); c != 0 || config.TieBreaker == nil {
+		return c
+	}
+	return config.TieBreaker(a, b)
+}
+
+// SortedBy reports whether the list is strictly sorted by key and, for equal
+// keys, by the configured TieBreaker. Without a TieBreaker it is equivalent to
+// StrictlySorted.
+func (l ReorderableList) SortedBy(config *Config) bool {
+	for i := 1; i < len(l); i++ {
+		if compareItems(l[i-1], l[i], config) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Sort orders the list in place by key and, for equal keys, by the configured
+// TieBreaker. The sort is stable, so without a TieBreaker items with equal keys
+// keep their current relative order.
+func (l ReorderableList) Sort(config *Config) {
+	sort.SliceStable(l, func(i, j int) bool {
+		return compareItems(l[i], l[j], config) < 0
+	})
+}
+
+// Repair sorts the list and then gives every item that shares its key with a
+// predecessor a fresh key, spread between the duplicated key and the next
+// distinct one. The resulting order is exactly the one produced by Sort, so
+// the TieBreaker decides which duplicate keeps the original key. If there is no
+// room for the new keys the list is normalised.
+func (l ReorderableList) Repair(config *Config) error {
+	l.Sort(config)
+
+	for i := 1; i < len(l); {
+		prev := l[i-1].GetKey()
+		if l[i].GetKey().Compare(prev) != 0 {
+			i++
+			continue
+		}
+
+		end := i
+		for end < len(l) && l[end].GetKey().Compare(prev) == 0 {
+			end++
+		}
+
+		var next *Key
+		if end < len(l) {
+			k := l[end].GetKey()
+			next = &k
+		}
+
+		res, err := ReserveRange(prev, next, end-i, config)
+		if err != nil {
+			_, err := l.Normalize(config)
+			return err
+		}
+		for j := i; j < end; j++ {
+			k, _ := res.Key(j - i)
+			l.setKey(j, k, AuditRepair, config)
+		}
+
+		i = end
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package lexorank
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func byID(a, b Reorderable) int {
+	return strings.Compare(a.(Identifiable).GetID(), b.(Identifiable).GetID())
+}
+
+func ids(l ReorderableList) []string {
+	out := make([]string, len(l))
+	for i, it := range l {
+		out[i] = it.(Identifiable).GetID()
+	}
+	return out
+}
+
+func TestReorderableList_TieBreaker(t *testing.T) {
+	a := assert.New(t)
+
+	config := DefaultConfig().WithTieBreaker(byID)
+
+	list := ReorderableList{
+		item(3, "0|b"),
+		item(2, "0|b"),
+		item(1, "0|a"),
+	}
+	a.False(list.SortedBy(config))
+
+	list.Sort(config)
+	a.Equal([]string{"1", "2", "3"}, ids(list))
+	a.True(list.SortedBy(config))
+	a.False(list.StrictlySorted(), "keys are still duplicated")
+	a.False(list.SortedBy(DefaultConfig()), "without a tie breaker duplicates are unsorted")
+}
+
+func TestReorderableList_Repair(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig().WithTieBreaker(byID)
+
+	list := ReorderableList{
+		item(4, "0|c"),
+		item(3, "0|b"),
+		item(1, "0|b"),
+		item(2, "0|b"),
+		item(0, "0|a"),
+	}
+
+	r.NoError(list.Repair(config))
+	a.Equal([]string{"0", "1", "2", "3", "4"}, ids(list))
+	a.True(list.StrictlySorted())
+	a.Equal("0|b", list[1].GetKey().String(), "the first duplicate keeps its key")
+	a.Equal("0|c", list[4].GetKey().String(), "unique keys are untouched")
+}
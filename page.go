@@ -0,0 +1,120 @@
+package lexorank
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Page describes a keyset pagination query relative to a cursor key, in either
+// direction. Keyset pagination stays stable while items are inserted and
+// moved, unlike OFFSET.
+type Page struct {
+	// Cursor is the key of the last item of the current page, or of its first
+	// item when paging backward. Nil means the start of the list, or the end
+	// when paging backward.
+	Cursor *Key
+
+	// Backward selects the items before the cursor instead of after it
+	Backward bool
+}
+
+// PageAfter returns the page following the cursor.
+func PageAfter(cursor *Key) Page {
+	return Page{Cursor: cursor}
+}
+
+// PageBefore returns the page preceding the cursor. Results are fetched in
+// descending order, so pass them through Forward before display.
+func PageBefore(cursor *Key) Page {
+	return Page{Cursor: cursor, Backward: true}
+}
+
+// Contains reports whether k lies on the page's side of the cursor.
+func (p Page) Contains(k Key) bool {
+	if p.Cursor == nil {
+		return true
+	}
+	if p.Backward {
+		return k.Compare(*p.Cursor) < 0
+	}
+	return k.Compare(*p.Cursor) > 0
+}
+
+// SQL returns the WHERE, ORDER BY and LIMIT clauses for fetching the page from
+// a rank column, along with the query arguments, e.g.
+//
+//	WHERE rank < ? ORDER BY rank DESC LIMIT 20
+//
+// The column must be a plain, optionally qualified, identifier. A limit of
+// zero or less omits the LIMIT clause.
+func (p Page) SQL(column string, limit int, opts ...SQLOption) (string, []any, error) {
+	if !sqlIdentifier.MatchString(column) {
+		return "", nil, errors.Errorf("invalid SQL identifier: %q", column)
+	}
+
+	o := sqlOptions{placeholder: func(int) string { return "?" }}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var (
+		clauses []string
+		args    []any
+	)
+
+	op, order := ">", "ASC"
+	if p.Backward {
+		op, order = "<", "DESC"
+	}
+	if p.Cursor != nil {
+		args = append(args, p.Cursor.String())
+		clauses = append(clauses, fmt.Sprintf("WHERE %s %s %s", column, op, o.placeholder(len(args))))
+	}
+	clauses = append(clauses, fmt.Sprintf("ORDER BY %s %s", column, order))
+	if limit > 0 {
+		clauses = append(clauses, fmt.Sprintf("LIMIT %d", limit))
+	}
+
+	return strings.Join(clauses, " "), args, nil
+}
+
+// Forward puts the results of a page query into ascending key order, in
+// place. Results of backward pages arrive in descending order and are
+// reversed; forward pages are left as they are.
+func (p Page) Forward(l ReorderableList) ReorderableList {
+	if p.Backward {
+		for i, j := 0, len(l)-1; i < j; i, j = i+1, j-1 {
+			l[i], l[j] = l[j], l[i]
+		}
+	}
+	return l
+}
+
+// Page returns up to limit items of a sorted list on the page's side of the
+// cursor, nearest to it first and in ascending key order. It mirrors what the
+// SQL query followed by Forward returns, so a limit of zero or less returns
+// every item on the page's side.
+func (l ReorderableList) Page(p Page, limit int) ReorderableList {
+	if limit <= 0 {
+		limit = len(l)
+	}
+
+	if p.Backward {
+		end := len(l)
+		if p.Cursor != nil {
+			end = l.Search(*p.Cursor)
+		}
+		return l[max(end-limit, 0):end]
+	}
+
+	start := 0
+	if p.Cursor != nil {
+		start = l.Search(*p.Cursor)
+		for start < len(l) && l[start].GetKey().Compare(*p.Cursor) == 0 {
+			start++
+		}
+	}
+	return l[start:min(start+limit, len(l))]
+}
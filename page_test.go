@@ -0,0 +1,80 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPage_SQL(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	cursor := mustKey("0|b")
+
+	q, args, err := PageAfter(nil).SQL("rank", 20)
+	r.NoError(err)
+	a.Equal("ORDER BY rank ASC LIMIT 20", q)
+	a.Empty(args)
+
+	q, args, err = PageAfter(&cursor).SQL("rank", 20)
+	r.NoError(err)
+	a.Equal("WHERE rank > ? ORDER BY rank ASC LIMIT 20", q)
+	a.Equal([]any{"0|b"}, args)
+
+	q, args, err = PageBefore(&cursor).SQL("items.rank", 0, WithDollarPlaceholders())
+	r.NoError(err)
+	a.Equal("WHERE items.rank < $1 ORDER BY items.rank DESC", q)
+	a.Equal([]any{"0|b"}, args)
+
+	_, _, err = PageBefore(nil).SQL("rank; --", 20)
+	a.Error(err)
+}
+
+func TestReorderableList_Page(t *testing.T) {
+	a := assert.New(t)
+
+	l := ReorderableList{item(1, "0|a"), item(2, "0|b"), item(3, "0|c"), item(4, "0|d"), item(5, "0|e")}
+	keys := func(l ReorderableList) []string {
+		out := make([]string, len(l))
+		for i, it := range l {
+			out[i] = it.GetKey().String()
+		}
+		return out
+	}
+
+	first := l.Page(PageAfter(nil), 2)
+	a.Equal([]string{"0|a", "0|b"}, keys(first))
+
+	cursor := first[len(first)-1].GetKey()
+	second := l.Page(PageAfter(&cursor), 2)
+	a.Equal([]string{"0|c", "0|d"}, keys(second))
+
+	// Going back from the second page returns the first one
+	cursor = second[0].GetKey()
+	a.Equal([]string{"0|a", "0|b"}, keys(l.Page(PageBefore(&cursor), 2)))
+	a.Equal([]string{"0|d", "0|e"}, keys(l.Page(PageBefore(nil), 2)))
+
+	for _, it := range l.Page(PageBefore(&cursor), 2) {
+		a.True(PageBefore(&cursor).Contains(it.GetKey()))
+	}
+
+	// Like the SQL, a limit of zero or less means no limit
+	for _, limit := range []int{0, -1} {
+		a.Equal([]string{"0|a", "0|b", "0|c", "0|d", "0|e"}, keys(l.Page(PageAfter(nil), limit)))
+		a.Equal([]string{"0|d", "0|e"}, keys(l.Page(PageAfter(&cursor), limit)))
+		a.Equal([]string{"0|a", "0|b"}, keys(l.Page(PageBefore(&cursor), limit)))
+		a.Len(l.Page(PageBefore(nil), limit), 5)
+	}
+}
+
+func TestPage_Forward(t *testing.T) {
+	a := assert.New(t)
+
+	desc := ReorderableList{item(3, "0|c"), item(2, "0|b"), item(1, "0|a")}
+	a.True(PageBefore(nil).Forward(desc).StrictlySorted())
+
+	asc := ReorderableList{item(1, "0|a"), item(2, "0|b")}
+	a.True(PageAfter(nil).Forward(asc).StrictlySorted())
+}
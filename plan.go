@@ -0,0 +1,233 @@
+package lexorank
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// ReorderPlan holds the key assignments an operation would make, computed by
+// one of the Plan methods without modifying the list. It can be validated,
+// persisted inside a transaction and then applied with ApplyPlan, or discarded.
+type ReorderPlan struct {
+	// Key is the key for the new item, or the new key of the moved item.
+	Key Key
+
+	// From and To are the indices of a planned Move. Both are -1 for inserts.
+	From int
+	To   int
+
+	// Changes holds the keys assigned to existing items, including the moved
+	// one, in ascending index order. Indices refer to the list as it was when
+	// the plan was made. IDs are filled in for Identifiable items.
+	Changes ChangeSet
+
+	before Keys
+	hooks  *planHooks
+}
+
+// planHooks stands in for the Metrics, Logger, OnLongKey and Audit hooks of
+// the config while a plan is computed, so they only see what happens once the
+// plan is applied.
+type planHooks struct {
+	between, rebalances, normalizations int
+
+	lengths  []int
+	warnings []planWarning
+	longKeys []Key
+	audit    AuditLog
+}
+
+type planWarning struct {
+	msg  string
+	args []any
+}
+
+func (h *planHooks) BetweenCalled()        { h.between++ }
+func (h *planHooks) RebalanceTriggered()   { h.rebalances++ }
+func (h *planHooks) Normalized()           { h.normalizations++ }
+func (h *planHooks) KeysRewritten(int)     {}
+func (h *planHooks) RankLength(length int) { h.lengths = append(h.lengths, length) }
+
+func (h *planHooks) Warn(msg string, args ...any) {
+	h.warnings = append(h.warnings, planWarning{msg: msg, args: args})
+}
+
+// planning returns a copy of the config whose hooks record into a new
+// planHooks instead.
+func (c *Config) planning() (*Config, *planHooks) {
+	h := &planHooks{}
+	newConfig := *c
+	newConfig.Metrics = h
+	newConfig.Logger = h
+	newConfig.Audit = &h.audit
+	if c.OnLongKey != nil {
+		newConfig.OnLongKey = func(k Key) { h.longKeys = append(h.longKeys, k) }
+	}
+	return &newConfig, h
+}
+
+// replay passes what was recorded while planning to the hooks of config, once
+// the plan has been applied to l. KeysRewritten is not replayed, as applying
+// the changes counts them.
+func (h *planHooks) replay(l ReorderableList, config *Config) {
+	m := config.metrics()
+	for range h.between {
+		m.BetweenCalled()
+	}
+	for range h.rebalances {
+		m.RebalanceTriggered()
+	}
+	for range h.normalizations {
+		m.Normalized()
+	}
+	for _, length := range h.lengths {
+		m.RankLength(length)
+	}
+	for _, w := range h.warnings {
+		config.warn(w.msg, w.args...)
+	}
+	if config.OnLongKey != nil {
+		for _, k := range h.longKeys {
+			config.OnLongKey(k)
+		}
+	}
+	if config.Audit != nil {
+		for _, e := range h.audit.Entries() {
+			// Entries name the shadow items, which are labelled by index
+			e.ID = ""
+			if id, ok := l[e.Index].(Identifiable); ok {
+				e.ID = id.GetID()
+			}
+			config.Audit.add(e)
+		}
+	}
+}
+
+// PlanInsert computes the outcome of Insert without modifying the list.
+func (l ReorderableList) PlanInsert(position uint, config *Config) (*ReorderPlan, error) {
+	return l.plan(config, func(shadow ReorderableList, config *Config) (Key, error) {
+		k, err := shadow.Insert(position, config)
+		if err != nil {
+			return Key{}, err
+		}
+		return *k, nil
+	})
+}
+
+// PlanAppend computes the outcome of Append without modifying the list.
+func (l ReorderableList) PlanAppend(config *Config) (*ReorderPlan, error) {
+	return l.plan(config, func(shadow ReorderableList, config *Config) (Key, error) {
+		return shadow.Append(config)
+	})
+}
+
+// PlanPrepend computes the outcome of Prepend without modifying the list.
+func (l ReorderableList) PlanPrepend(config *Config) (*ReorderPlan, error) {
+	return l.plan(config, func(shadow ReorderableList, config *Config) (Key, error) {
+		return shadow.Prepend(config)
+	})
+}
+
+// PlanMove computes the outcome of Move without modifying the list.
+func (l ReorderableList) PlanMove(from, to uint, config *Config) (*ReorderPlan, error) {
+	p, err := l.plan(config, func(shadow ReorderableList, config *Config) (Key, error) {
+		if _, err := shadow.Move(from, to, config); err != nil {
+			return Key{}, err
+		}
+		return shadow[to].GetKey(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.From, p.To = int(from), int(to)
+	return p, nil
+}
+
+// plan runs op against a copy of the list's keys and records what changed.
+// The config passed to op records its hooks into the plan.
+func (l ReorderableList) plan(config *Config, op func(shadow ReorderableList, config *Config) (Key, error)) (*ReorderPlan, error) {
+	items := make([]SnapshotItem, len(l))
+	for i, it := range l {
+		items[i] = SnapshotItem{ID: strconv.Itoa(i), Key: it.GetKey()}
+	}
+	shadow := fromSnapshotItems(items)
+
+	planning, hooks := config.planning()
+	k, err := op(shadow, planning)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ReorderPlan{Key: k, From: -1, To: -1, before: l.Keys(), hooks: hooks}
+	for i := range items {
+		if items[i].Key.Compare(p.before[i]) == 0 {
+			continue
+		}
+		c := Change{Index: i, Key: items[i].Key}
+		if id, ok := l[i].(Identifiable); ok {
+			c.ID = id.GetID()
+		}
+		p.Changes = append(p.Changes, c)
+	}
+
+	return p, nil
+}
+
+// ApplyPlan carries out a plan made from this list. If any key has changed since
+// the plan was made it fails with ErrStaleView and the list is left untouched.
+// For a planned Move the item is also moved to its new index in the slice.
+// The key for a planned insert is not assigned to anything; use p.Key for the
+// new item.
+//
+// The Metrics, Logger, OnLongKey and Audit hooks of config see the plan only
+// now, as if the operation had run on the list.
+func (l ReorderableList) ApplyPlan(p *ReorderPlan, config *Config) error {
+	if len(l) != len(p.before) || len(l.Changes(p.before)) > 0 {
+		return errors.Wrap(ErrStaleView, "list changed since the plan was made")
+	}
+
+	// The audit log gets the entries recorded while planning instead, with
+	// the reasons the operation had
+	quiet := config
+	if p.hooks != nil {
+		quiet = config.WithAudit(nil)
+	}
+
+	if p.From < 0 {
+		if err := l.ApplyChangeSet(p.Changes, quiet); err != nil {
+			return err
+		}
+		if p.hooks != nil {
+			p.hooks.replay(l, config)
+		}
+		return nil
+	}
+
+	// Validate and assign the keys against the final order, then reorder.
+	moved := make(ReorderableList, 0, len(l))
+	moved = append(append(moved, l[:p.From]...), l[p.From+1:]...)
+	moved = append(moved[:p.To], append(ReorderableList{l[p.From]}, moved[p.To:]...)...)
+
+	cs := make(ChangeSet, len(p.Changes))
+	for i, c := range p.Changes {
+		switch {
+		case c.Index == p.From:
+			c.Index = p.To
+		case p.From < c.Index && c.Index <= p.To:
+			c.Index--
+		case p.To <= c.Index && c.Index < p.From:
+			c.Index++
+		}
+		cs[i] = c
+	}
+
+	if err := moved.ApplyChangeSet(cs, quiet); err != nil {
+		return err
+	}
+	copy(l, moved)
+	if p.hooks != nil {
+		p.hooks.replay(l, config)
+	}
+	return nil
+}
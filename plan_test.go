@@ -0,0 +1,115 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorderableList_PlanInsert(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|b0")}
+	before := list.Keys()
+	config := DefaultConfig().WithMaxRankLength(2)
+
+	p, err := list.PlanInsert(2, config)
+	r.NoError(err)
+	a.Equal(before, list.Keys(), "planning does not touch the list")
+	a.Equal(-1, p.From)
+	a.Len(p.Changes, 3)
+	a.Equal("2", p.Changes[2].ID)
+
+	r.NoError(list.ApplyPlan(p, config))
+	a.True(list.StrictlySorted())
+	a.True(list[1].GetKey().Compare(p.Key) < 0 && p.Key.Compare(list[2].GetKey()) < 0)
+
+	a.ErrorIs(list.ApplyPlan(p, config), ErrStaleView, "the plan was made against the old keys")
+
+	p, err = list.PlanAppend(DefaultConfig())
+	r.NoError(err)
+	a.Empty(p.Changes)
+	a.True(p.Key.Compare(list[2].GetKey()) > 0)
+
+	p, err = list.PlanPrepend(DefaultConfig())
+	r.NoError(err)
+	a.True(p.Key.Compare(list[0].GetKey()) < 0)
+
+	_, err = list.PlanInsert(4, DefaultConfig())
+	a.ErrorIs(err, ErrOutOfBounds)
+}
+
+func TestReorderableList_PlanMove(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := moveList()
+	p, err := list.PlanMove(0, 2, DefaultConfig())
+	r.NoError(err)
+	a.Equal([]string{"0", "1", "2", "3"}, moveIDs(list))
+	a.Equal(2, p.To)
+	r.Len(p.Changes, 1)
+	a.Equal(0, p.Changes[0].Index)
+
+	r.NoError(list.ApplyPlan(p, DefaultConfig()))
+	a.Equal([]string{"1", "2", "0", "3"}, moveIDs(list))
+	a.Equal(p.Key, list[2].GetKey())
+	a.True(list.StrictlySorted())
+
+	tight := ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|b0"), item(3, "0|c")}
+	p, err = tight.PlanMove(3, 2, DefaultConfig().WithMaxRankLength(2))
+	r.NoError(err)
+	a.Greater(len(p.Changes), 1)
+
+	r.NoError(tight.ApplyPlan(p, DefaultConfig().WithMaxRankLength(2)))
+	a.Equal([]string{"0", "1", "3", "2"}, moveIDs(tight))
+	a.True(tight.StrictlySorted())
+}
+
+func TestReorderableList_PlanHooks(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	m := &countingMetrics{}
+	logger := &recordingLogger{}
+	log := &AuditLog{}
+	var long []Key
+	config := DefaultConfig().WithMaxRankLength(2).WithMetrics(m).WithLogger(logger).WithAudit(log).
+		WithWarnRankLength(1, func(k Key) { long = append(long, k) })
+
+	list := ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|b0")}
+	p, err := list.PlanInsert(2, config)
+	r.NoError(err)
+	a.Equal(countingMetrics{}, *m, "planning reports nothing")
+	a.Empty(logger.messages)
+	a.Empty(log.Entries())
+	a.Empty(long)
+
+	r.NoError(list.ApplyPlan(p, config))
+	a.Equal(1, m.rebalances)
+	a.Equal(len(p.Changes), m.rewritten)
+	a.Positive(m.between)
+	a.NotEmpty(logger.messages)
+	a.NotEmpty(long)
+
+	entries := log.Entries()
+	r.NotEmpty(entries)
+	for _, e := range entries {
+		a.Equal(list[e.Index].(Identifiable).GetID(), e.ID)
+		a.NotEqual(AuditChangeSet, e.Reason, "entries keep the reason of the planned operation")
+	}
+	a.Equal(list[2].GetKey(), entries[len(entries)-1].New)
+
+	// Moves report the final indices
+	log.Reset()
+	list = moveList()
+	p, err = list.PlanMove(0, 2, config)
+	r.NoError(err)
+	a.Empty(log.Entries())
+	r.NoError(list.ApplyPlan(p, config))
+	entries = log.Entries()
+	r.Len(entries, 1)
+	a.Equal(AuditEntry{Index: 2, ID: "0", Old: p.before[0], New: p.Key, Reason: AuditMove}, entries[0])
+}
@@ -0,0 +1,109 @@
+package lexorank
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// PositionedItem is a row of a table ordered by an integer position column, as
+// read when adopting lexorank keys for an existing table.
+type PositionedItem struct {
+	ID       string
+	Position int64
+}
+
+// MigratePositions assigns keys spread evenly across bucket to items in the
+// order of their positions, which may have gaps, be negative or arrive
+// unsorted. Items with the same position keep their order in the slice, so
+// read them with a tie-breaker such as ORDER BY position, id.
+//
+// The returned change set has one change per item, with Index referring to
+// items, and is meant to be persisted with ApplyChangeSetTx or written out
+// with WriteChangeSetSQL. For tables too large to load at once, use a
+// PositionMigrator.
+func MigratePositions(items []PositionedItem, bucket uint8, config *Config) (ChangeSet, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return items[order[i]].Position < items[order[j]].Position
+	})
+
+	sorted := make([]PositionedItem, len(items))
+	for i, index := range order {
+		sorted[i] = items[index]
+	}
+
+	m, err := NewPositionMigrator(len(items), bucket, config)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := m.Next(sorted)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cs {
+		cs[i].Index = order[cs[i].Index]
+	}
+	sort.Slice(cs, func(i, j int) bool { return cs[i].Index < cs[j].Index })
+	return cs, nil
+}
+
+// PositionMigrator generates keys for a table ordered by integer positions in
+// batches, so the rows can be read page by page instead of all at once. The
+// keys are reserved up front for the total number of rows and each one
+// depends on the row's place in the order alone, so the result is the same as
+// MigratePositions on the whole table.
+type PositionMigrator struct {
+	res  *Reservation
+	done int
+	last *int64
+}
+
+// NewPositionMigrator reserves keys in bucket for total rows.
+func NewPositionMigrator(total int, bucket uint8, config *Config) (*PositionMigrator, error) {
+	upper := MaxKey(bucket, config)
+	res, err := ReserveRange(MinKey(bucket, config), &upper, total, config)
+	if err != nil {
+		return nil, err
+	}
+	return &PositionMigrator{res: res}, nil
+}
+
+// Next returns the keys for the next batch of rows, which must continue the
+// position order of the earlier batches, as when paging through ORDER BY
+// position, id. Indices in the change set refer to batch. It fails with
+// ErrNotSorted if a position goes backwards and ErrOutOfBounds once more rows
+// arrive than were reserved.
+func (m *PositionMigrator) Next(batch []PositionedItem) (ChangeSet, error) {
+	if m.done+len(batch) > m.res.Count {
+		return nil, errors.Wrapf(ErrOutOfBounds, "%d rows exceed the %d reserved", m.done+len(batch), m.res.Count)
+	}
+
+	cs := make(ChangeSet, len(batch))
+	for i, it := range batch {
+		if m.last != nil && it.Position < *m.last {
+			return nil, errors.Wrapf(ErrNotSorted, "position %d of %s follows %d", it.Position, it.ID, *m.last)
+		}
+		k, err := m.res.Key(m.done + i)
+		if err != nil {
+			return nil, err
+		}
+		cs[i] = Change{Index: i, ID: it.ID, Key: k}
+		m.last = &batch[i].Position
+	}
+
+	m.done += len(batch)
+	return cs, nil
+}
+
+// Remaining returns the number of reserved rows that have no key yet.
+func (m *PositionMigrator) Remaining() int {
+	return m.res.Count - m.done
+}
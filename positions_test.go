@@ -0,0 +1,78 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigratePositions(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	items := []PositionedItem{
+		{ID: "c", Position: 30},
+		{ID: "a", Position: -5},
+		{ID: "b1", Position: 10},
+		{ID: "b2", Position: 10},
+	}
+	cs, err := MigratePositions(items, 0, DefaultConfig())
+	r.NoError(err)
+	r.Len(cs, 4)
+
+	want, err := Spread(0, 4, DefaultConfig())
+	r.NoError(err)
+	byID := map[string]string{}
+	for i, c := range cs {
+		a.Equal(i, c.Index)
+		a.Equal(items[i].ID, c.ID)
+		byID[c.ID] = c.Key.String()
+	}
+	a.Equal(map[string]string{
+		"a":  want[0].String(),
+		"b1": want[1].String(),
+		"b2": want[2].String(),
+		"c":  want[3].String(),
+	}, byID)
+
+	cs, err = MigratePositions(nil, 0, DefaultConfig())
+	r.NoError(err)
+	a.Empty(cs)
+}
+
+func TestPositionMigrator(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	items := make([]PositionedItem, 7)
+	for i := range items {
+		items[i] = PositionedItem{ID: string(rune('a' + i)), Position: int64(i + 1)}
+	}
+	whole, err := MigratePositions(items, 1, DefaultConfig())
+	r.NoError(err)
+
+	m, err := NewPositionMigrator(len(items), 1, DefaultConfig())
+	r.NoError(err)
+	var batched ChangeSet
+	for start := 0; start < len(items); start += 3 {
+		cs, err := m.Next(items[start:min(start+3, len(items))])
+		r.NoError(err)
+		for _, c := range cs {
+			c.Index += start
+			batched = append(batched, c)
+		}
+	}
+	a.Equal(0, m.Remaining())
+	a.Equal(whole, batched)
+
+	_, err = m.Next(items[:1])
+	a.ErrorIs(err, ErrOutOfBounds)
+
+	m, err = NewPositionMigrator(3, 0, DefaultConfig())
+	r.NoError(err)
+	_, err = m.Next([]PositionedItem{{ID: "a", Position: 5}})
+	r.NoError(err)
+	_, err = m.Next([]PositionedItem{{ID: "b", Position: 4}})
+	a.ErrorIs(err, ErrNotSorted)
+}
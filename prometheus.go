@@ -0,0 +1,85 @@
+package lexorank
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WritePrometheus renders the statistics of many lists in the Prometheus text
+// exposition format, labelled by the map key as list="<name>". Gaps are
+// reported as a fraction of the bucket's key space, so lists with different
+// configurations can be compared and the most at-risk lists are the ones with
+// the lowest lexorank_list_gap_min. The lexorank_list_rank_length histogram
+// of every list has the same boundaries, one per length from 1 to the longest
+// Scale or rank length of any list, plus +Inf.
+func WritePrometheus(w io.Writer, lists map[string]ListStats) error {
+	names := make([]string, 0, len(lists))
+	for name := range lists {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+
+	gauge := func(name, help string, value func(ListStats) float64) {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+		for _, list := range names {
+			fmt.Fprintf(bw, "%s{list=%s} %s\n", name, quoteLabel(list), formatFloat(value(lists[list])))
+		}
+	}
+
+	gauge("lexorank_list_items", "Number of items in the list.", func(s ListStats) float64 {
+		return float64(s.Count)
+	})
+	gauge("lexorank_list_gap_min", "Smallest gap between adjacent keys as a fraction of the key space.", func(s ListStats) float64 {
+		return s.gapFraction(s.MinGap)
+	})
+	gauge("lexorank_list_gap_avg", "Average gap between adjacent keys as a fraction of the key space.", func(s ListStats) float64 {
+		return s.gapFraction(s.AvgGap)
+	})
+	gauge("lexorank_list_gap_max", "Largest gap between adjacent keys as a fraction of the key space.", func(s ListStats) float64 {
+		return s.gapFraction(s.MaxGap)
+	})
+
+	// Every list shares the same bucket boundaries, 1 up to the longest length
+	// any list is measured at, so the histograms can be aggregated
+	longest := 1
+	for _, s := range lists {
+		longest = max(longest, s.Scale)
+		for length := range s.RankLengths {
+			longest = max(longest, length)
+		}
+	}
+
+	const hist = "lexorank_list_rank_length"
+	fmt.Fprintf(bw, "# HELP %s Distribution of rank lengths in the list.\n# TYPE %s histogram\n", hist, hist)
+	for _, list := range names {
+		s := lists[list]
+		label := quoteLabel(list)
+
+		cumulative, sum := 0, 0
+		for length := 1; length <= longest; length++ {
+			cumulative += s.RankLengths[length]
+			sum += length * s.RankLengths[length]
+			fmt.Fprintf(bw, "%s_bucket{list=%s,le=\"%d\"} %d\n", hist, label, length, cumulative)
+		}
+		fmt.Fprintf(bw, "%s_bucket{list=%s,le=\"+Inf\"} %d\n", hist, label, cumulative)
+		fmt.Fprintf(bw, "%s_sum{list=%s} %d\n", hist, label, sum)
+		fmt.Fprintf(bw, "%s_count{list=%s} %d\n", hist, label, cumulative)
+	}
+
+	return bw.Flush()
+}
+
+func quoteLabel(v string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(v) + `"`
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,74 @@
+package lexorank
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig().WithMaxRankLength(2)
+	board := ReorderableList{item(0, "0|0"), item(1, "0|U"), item(2, "0|UU")}
+	backlog := ReorderableList{item(0, "0|a")}
+
+	var sb strings.Builder
+	err := WritePrometheus(&sb, map[string]ListStats{
+		"board":    board.Stats(config),
+		`back"log`: backlog.Stats(config),
+	})
+	r.NoError(err)
+
+	out := sb.String()
+	a.Contains(out, "# TYPE lexorank_list_items gauge\n")
+	a.Contains(out, `lexorank_list_items{list="board"} 3`)
+	a.Contains(out, `lexorank_list_items{list="back\"log"} 1`)
+	a.Contains(out, `lexorank_list_gap_min{list="board"} 0.006577777777777778`)
+	a.Contains(out, "# TYPE lexorank_list_rank_length histogram\n")
+	a.Contains(out, `lexorank_list_rank_length_bucket{list="board",le="1"} 2`)
+	a.Contains(out, `lexorank_list_rank_length_bucket{list="board",le="2"} 3`)
+	a.Contains(out, `lexorank_list_rank_length_bucket{list="board",le="+Inf"} 3`)
+	a.Contains(out, `lexorank_list_rank_length_sum{list="board"} 4`)
+	a.Contains(out, `lexorank_list_rank_length_count{list="board"} 3`)
+
+	// Every list has the same boundaries, whatever lengths it holds
+	a.Contains(out, `lexorank_list_rank_length_bucket{list="back\"log",le="1"} 1`)
+	a.Contains(out, `lexorank_list_rank_length_bucket{list="back\"log",le="2"} 1`)
+	a.Equal(6, strings.Count(out, "lexorank_list_rank_length_bucket{"))
+
+	// Families are declared once, with samples sorted by list name
+	a.Equal(1, strings.Count(out, "# TYPE lexorank_list_items"))
+	a.Less(strings.Index(out, `list="back\"log"`), strings.Index(out, `list="board"`))
+}
+
+func TestWritePrometheus_FixedBuckets(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig().WithMaxRankLength(4)
+	short := ReorderableList{item(0, "0|a")}
+	unbounded := ReorderableList{item(0, "0|a"), item(1, "0|abcde")}
+
+	var sb strings.Builder
+	err := WritePrometheus(&sb, map[string]ListStats{
+		"short":     short.Stats(config),
+		"unbounded": unbounded.Stats(DefaultConfig().WithMaxRankLength(0)),
+	})
+	r.NoError(err)
+
+	// Boundaries run from 1 to the longest length of any list plus +Inf
+	out := sb.String()
+	for _, list := range []string{"short", "unbounded"} {
+		for _, le := range []string{"1", "2", "3", "4", "5", "+Inf"} {
+			a.Contains(out, `lexorank_list_rank_length_bucket{list="`+list+`",le="`+le+`"}`)
+		}
+	}
+	a.Contains(out, `lexorank_list_rank_length_bucket{list="short",le="5"} 1`)
+	a.Contains(out, `lexorank_list_rank_length_bucket{list="unbounded",le="4"} 1`)
+	a.Contains(out, `lexorank_list_rank_length_bucket{list="unbounded",le="5"} 2`)
+	a.Equal(12, strings.Count(out, "lexorank_list_rank_length_bucket{"))
+}
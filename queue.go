@@ -0,0 +1,58 @@
+package lexorank
+
+import "github.com/pkg/errors"
+
+// NextAfter returns the key for enqueuing an item after the current tail of a
+// queue ordered by key, using the configured append strategy. A zero tail means
+// the queue is empty, in which case the middle of bucket 0 is returned so
+// items can later be prioritised ahead of it.
+func NextAfter(tail Key, config *Config) (*Key, error) {
+	if tail.IsZero() {
+		mid := middleOf(0, config)
+		return &mid, nil
+	}
+	return SmartAppend(tail, config)
+}
+
+// Ahead returns a key that sorts directly ahead of target, for re-prioritising
+// an item in a queue. prev is the key currently ahead of target, or nil if
+// target is the head of the queue. Like NeighborInsert it returns a
+// *RebalanceWindowError if there is no room between the two.
+func Ahead(target Key, prev *Key, config *Config) (*Key, error) {
+	return NeighborInsert(prev, &target, config)
+}
+
+// PartitionBounds splits the key range [from, to) into parts contiguous
+// ranges, so several workers can claim disjoint slices of a queue with queries
+// like "rank >= bounds[i] AND rank < bounds[i+1]". It returns parts+1
+// boundaries, the first being from and the last to. Either bound may be the
+// zero Key to mean the bottom or top of the bucket.
+func PartitionBounds(from, to Key, parts int, config *Config) (Keys, error) {
+	if parts <= 0 {
+		return nil, errors.Errorf("parts must be positive, got %d", parts)
+	}
+
+	switch {
+	case from.IsZero() && to.IsZero():
+		from, to = MinKey(0, config), MaxKey(0, config)
+	case from.IsZero():
+		from = MinKey(to.bucket, config)
+	case to.IsZero():
+		to = MaxKey(from.bucket, config)
+	}
+
+	bounds := make(Keys, 0, parts+1)
+	bounds = append(bounds, from)
+	if parts > 1 {
+		res, err := ReserveRange(from, &to, parts-1, config)
+		if err != nil {
+			return nil, err
+		}
+		bounds = append(bounds, res.Keys()...)
+	} else if from.Compare(to) >= 0 {
+		return nil, errors.New("left key must be less than right key")
+	}
+	bounds = append(bounds, to)
+
+	return bounds, nil
+}
@@ -0,0 +1,55 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueue_NextAfterAndAhead(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	head, err := NextAfter(Key{}, DefaultConfig())
+	r.NoError(err)
+	a.Equal(MiddleOf(0).String(), head.String())
+
+	tail, err := NextAfter(*head, DefaultConfig())
+	r.NoError(err)
+	a.True(tail.Compare(*head) > 0)
+
+	// Jump the tail ahead of the head
+	urgent, err := Ahead(*head, nil, DefaultConfig())
+	r.NoError(err)
+	a.True(urgent.Compare(*head) < 0)
+
+	// And squeeze another item between the two
+	between, err := Ahead(*head, urgent, DefaultConfig())
+	r.NoError(err)
+	a.True(between.Compare(*urgent) > 0)
+	a.True(between.Compare(*head) < 0)
+}
+
+func TestQueue_PartitionBounds(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	bounds, err := PartitionBounds(Key{}, Key{}, 4, DefaultConfig())
+	r.NoError(err)
+	r.Len(bounds, 5)
+	a.Equal(MinKey(0, DefaultConfig()), bounds[0])
+	a.Equal(MaxKey(0, DefaultConfig()), bounds[4])
+	for i := 1; i < len(bounds); i++ {
+		a.True(bounds[i-1].Compare(bounds[i]) < 0)
+	}
+
+	one, err := PartitionBounds(mustKey("0|a"), mustKey("0|b"), 1, DefaultConfig())
+	r.NoError(err)
+	a.Equal(Keys{mustKey("0|a"), mustKey("0|b")}, one)
+
+	_, err = PartitionBounds(mustKey("0|b"), mustKey("0|a"), 1, DefaultConfig())
+	a.Error(err)
+	_, err = PartitionBounds(Key{}, Key{}, 0, DefaultConfig())
+	a.Error(err)
+}
@@ -0,0 +1,60 @@
+package lexorank
+
+import "bytes"
+
+// RankRange is a half-open range of serialised keys for queries of the form
+// WHERE rank >= From AND rank < To. The bounds are plain strings and need not
+// be valid keys themselves; they compare correctly against stored keys under a
+// binary collation.
+type RankRange struct {
+	From string
+	To   string
+}
+
+// Contains reports whether k falls within the range.
+func (r RankRange) Contains(k Key) bool {
+	s := k.String()
+	return s >= r.From && s < r.To
+}
+
+// BucketRange returns the range covering every key of a bucket.
+func BucketRange(bucket uint8, config *Config) RankRange {
+	prefix := []byte{bucket + '0', config.separator()}
+	return RankRange{From: string(prefix), To: successor(prefix)}
+}
+
+// PrefixRange returns the range covering k and every key whose rank extends
+// the rank of k, such as the keys generated below it by repeated inserts.
+func PrefixRange(k Key) RankRange {
+	return RankRange{From: k.String(), To: successor(k.raw)}
+}
+
+// SectionRange returns the range covering the keys strictly between a divider
+// and the next divider, e.g. the items listed under a section heading. A nil
+// next means the section runs to the end of the bucket. The divider must not be
+// the zero Key.
+func SectionRange(divider Key, next *Key) RankRange {
+	// The smallest key greater than the divider extends it by the minimum
+	// character
+	from := append(bytes.Clone(divider.raw), divider.Alphabet().Min())
+
+	to := successor(divider.raw[:2])
+	if next != nil {
+		to = next.String()
+	}
+
+	return RankRange{From: string(from), To: to}
+}
+
+// successor returns the smallest string that sorts after every string with
+// the given prefix, by incrementing the last byte that is not 0xff.
+func successor(prefix []byte) string {
+	s := bytes.Clone(prefix)
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] < 0xff {
+			s[i]++
+			return string(s[:i+1])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,50 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucketRange(t *testing.T) {
+	a := assert.New(t)
+
+	r := BucketRange(1, DefaultConfig())
+	a.Equal(RankRange{From: "1|", To: "1}"}, r)
+	a.True(r.Contains(mustKey("1|0")))
+	a.True(r.Contains(MaxKey(1, DefaultConfig())))
+	a.False(r.Contains(mustKey("0|z")))
+	a.False(r.Contains(mustKey("2|0")))
+
+	a.Equal(RankRange{From: "0:", To: "0;"}, BucketRange(0, DefaultConfig().WithSeparator(':')))
+}
+
+func TestPrefixRange(t *testing.T) {
+	a := assert.New(t)
+
+	r := PrefixRange(mustKey("0|az"))
+	a.Equal(RankRange{From: "0|az", To: "0|a{"}, r)
+	a.True(r.Contains(mustKey("0|az")))
+	a.True(r.Contains(mustKey("0|azzz")))
+	a.False(r.Contains(mustKey("0|b")))
+	a.False(r.Contains(mustKey("0|ay")))
+}
+
+func TestSectionRange(t *testing.T) {
+	a := assert.New(t)
+
+	divider := mustKey("0|a")
+	next := mustKey("0|c")
+
+	r := SectionRange(divider, &next)
+	a.Equal(RankRange{From: "0|a0", To: "0|c"}, r)
+	a.False(r.Contains(divider))
+	a.True(r.Contains(mustKey("0|a0")))
+	a.True(r.Contains(mustKey("0|bzz")))
+	a.False(r.Contains(next))
+
+	r = SectionRange(divider, nil)
+	a.Equal("0}", r.To)
+	a.True(r.Contains(MaxKey(0, DefaultConfig())))
+	a.False(r.Contains(mustKey("1|0")))
+}
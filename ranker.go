@@ -1,5 +1,12 @@
 package lexorank
 
+import (
+	"math/big"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
 type Orderable interface {
 	GetKey() Key
 }
@@ -47,13 +54,25 @@ func DefaultReorderableList(items []Reorderable) ReorderableList {
 // Purely for testing purposes.
 func (a ReorderableList) Len() int           { return len(a) }
 func (a ReorderableList) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
-func (a ReorderableList) Less(i, j int) bool { return a[i].GetKey().String() < a[j].GetKey().String() }
+func (a ReorderableList) Less(i, j int) bool { return a[i].GetKey().Compare(a[j].GetKey()) < 0 }
 
+// Insert returns a new key for an item placed at the given position. Only the
+// neighbours at position-1 and position need to be strictly ordered for the
+// fast path; otherwise the list is rebalanced from the position onwards. For
+// the rebalance to succeed without a full normalisation, the list should be
+// weakly sorted.
 func (l ReorderableList) Insert(position uint, config *Config) (*Key, error) {
 	if position > uint(len(l)) {
 		return nil, ErrOutOfBounds
 	}
 
+	if err := l.checkSize(config); err != nil {
+		return nil, err
+	}
+	if err := l.checkBuckets(config); err != nil {
+		return nil, err
+	}
+
 	if position == 0 {
 		k, err := l.Prepend(config)
 		if err != nil {
@@ -72,14 +91,21 @@ func (l ReorderableList) Insert(position uint, config *Config) (*Key, error) {
 
 	prev := l[position-1].GetKey()
 	next := l[position].GetKey()
+	if prev.Compare(next) >= 0 {
+		config.warn("neighbouring keys are not strictly ordered",
+			"position", position, "prev", prev.String(), "next", next.String())
+	}
 
 	for range 2 {
+		config.explain.attempt()
 		k, err := Between(prev, next, config)
 		if err == nil {
 			return k, nil
 		}
 
-		l.rebalanceFrom(position, 1, config)
+		if err := l.makeRoom(position, config); abortsRebalance(err) {
+			return nil, err
+		}
 
 		// refresh prev/next keys
 		prev = l[position-1].GetKey()
@@ -94,17 +120,35 @@ func (l ReorderableList) Insert(position uint, config *Config) (*Key, error) {
 // specified configuration for append strategy.
 func (l ReorderableList) Append(config *Config) (Key, error) {
 	if len(l) == 0 {
-		return BottomOf(0), nil
+		k := bottomOf(0, config)
+		config.padRank(&k)
+		return k, nil
 	}
 
+	if err := l.checkSize(config); err != nil {
+		return Key{}, err
+	}
+	if err := l.checkBuckets(config); err != nil {
+		return Key{}, err
+	}
+
+	config.explain.edge("append", config.AppendStrategy)
 	for range 2 {
+		config.explain.attempt()
 		last := l[len(l)-1].GetKey()
-		k, err := SmartAppend(last, config)
+		var prev *Key
+		if len(l) > 1 {
+			k := l[len(l)-2].GetKey()
+			prev = &k
+		}
+		k, err := smartAppend(prev, last, config)
 		if err == nil {
 			return *k, nil
 		}
 
-		l.rebalanceFrom(uint(len(l)-1), -1, config)
+		if err := l.makeRoom(uint(len(l)), config); abortsRebalance(err) {
+			return Key{}, err
+		}
 	}
 
 	return Key{}, ErrKeyInsertionFailedAfterRebalance
@@ -115,40 +159,195 @@ func (l ReorderableList) Append(config *Config) (Key, error) {
 // specified configuration.
 func (l ReorderableList) Prepend(config *Config) (Key, error) {
 	if len(l) == 0 {
-		return TopOf(0), nil
+		k := topOf(0, config)
+		config.padRank(&k)
+		return k, nil
+	}
+
+	if err := l.checkSize(config); err != nil {
+		return Key{}, err
+	}
+	if err := l.checkBuckets(config); err != nil {
+		return Key{}, err
 	}
 
+	config.explain.edge("prepend", config.prependStrategy())
 	for range 2 {
+		config.explain.attempt()
 		first := l[0].GetKey()
-		k, err := SmartPrepend(first, config)
+		var next *Key
+		if len(l) > 1 {
+			k := l[1].GetKey()
+			next = &k
+		}
+		k, err := smartPrepend(first, next, config)
 		if err == nil {
 			return *k, nil
 		}
 
-		l.rebalanceFrom(0, 1, config)
+		if err := l.makeRoom(0, config); abortsRebalance(err) {
+			return Key{}, err
+		}
 	}
 
 	return Key{}, ErrKeyInsertionFailedAfterRebalance
 }
 
+// Rebalance makes room next to the item at position by shifting keys in the
+// given direction, 1 towards the end of the list or -1 towards the start, and
+// falls back to Normalize if that is not enough. This is the same rebalance
+// Insert, Append and Prepend perform when a gap is exhausted, for use by
+// maintenance jobs. The indices of the rewritten items are returned in
+// ascending order.
+func (l ReorderableList) Rebalance(position uint, direction int, config *Config) ([]int, error) {
+	if position >= uint(len(l)) {
+		return nil, ErrOutOfBounds
+	}
+	if direction != 1 && direction != -1 {
+		return nil, errors.Errorf("invalid rebalance direction: %d (expected 1 or -1)", direction)
+	}
+
+	return l.Track(func() error {
+		return l.rebalanceFrom(position, direction, config)
+	})
+}
+
+// makeRoom rebalances the list so that a key fits in the gap before the item
+// at position, following config.RebalanceStrategy. Inline rebalances use
+// OpenGap if config.MinimalRebalance is set and shift neighbours with
+// rebalanceFrom otherwise. Both fall back to Normalize, unless the list is
+// larger than config.MaxKeysRewritten.
+func (l ReorderableList) makeRoom(position uint, config *Config) error {
+	config.metrics().RebalanceTriggered()
+	config.warn("no room left for a new key at MaxRankLength",
+		"position", position, "items", len(l), "max_rank_length", config.MaxRankLength)
+	switch config.RebalanceStrategy {
+	case RebalanceErrorOnly:
+		return ErrRebalanceRequired
+	case RebalanceFullNormalize:
+		if config.MaxKeysRewritten > 0 && len(l) > config.MaxKeysRewritten {
+			return &RewriteLimitError{Limit: config.MaxKeysRewritten, Required: len(l)}
+		}
+		config.explain.took(PathNormalize)
+		return l.fallbackNormalize(config)
+	}
+
+	if !config.MinimalRebalance {
+		switch position {
+		case 0:
+			return l.rebalanceFrom(0, 1, config)
+		case uint(len(l)):
+			return l.rebalanceFrom(position-1, -1, config)
+		default:
+			return l.rebalanceFrom(position, 1, config)
+		}
+	}
+
+	_, err := l.OpenGap(position, config)
+	if !errors.Is(err, ErrNormalizationRequired) {
+		if err == nil {
+			config.explain.took(PathLocalRebalance)
+		}
+		return err
+	}
+	if config.MaxKeysRewritten > 0 && len(l) > config.MaxKeysRewritten {
+		return &RewriteLimitError{Limit: config.MaxKeysRewritten, Required: len(l)}
+	}
+
+	config.explain.took(PathNormalize)
+	return l.fallbackNormalize(config)
+}
+
+// rebalanceFrom tries a local rebalance and falls back to Normalize. It does
+// not require the list to be sorted at all: Normalize only depends on the item
+// order, and produces a strictly sorted list.
 func (l ReorderableList) rebalanceFrom(position uint, direction int, config *Config) error {
-	ok := l.tryRebalanceFrom(position, direction, config)
+	if config.MaxKeysRewritten > 0 && len(l) > config.MaxKeysRewritten && !l.canSplitAt(position, direction, config) {
+		// The local rebalance is going to cascade and fall back to Normalize,
+		// which touches every key. Refuse before anything is modified.
+		return &RewriteLimitError{Limit: config.MaxKeysRewritten, Required: len(l)}
+	}
+
+	ok, err := l.tryRebalanceFrom(position, direction, config)
+	if err != nil {
+		return err
+	}
 	if ok {
+		config.explain.took(PathLocalRebalance)
 		return nil
 	}
 
 	// If we're here, the worst case scenario was reached: every key is adjacent
 	// to the next one. We need to normalise the entire list.
 
-	return l.Normalize(config)
+	config.explain.took(PathNormalize)
+	return l.fallbackNormalize(config)
+}
+
+// fallbackNormalize normalises the list when a rebalance needs it, unless it
+// has at least config.NormalizeThreshold items.
+func (l ReorderableList) fallbackNormalize(config *Config) error {
+	if config.NormalizeThreshold > 0 && len(l) >= config.NormalizeThreshold {
+		config.warn("rebalance needs a normalize above the threshold",
+			"items", len(l), "threshold", config.NormalizeThreshold)
+		return ErrNormalizationRequired
+	}
+	config.warn("rebalance falling back to normalize", "items", len(l))
+	_, err := l.Normalize(config)
+	return err
+}
+
+// abortsRebalance reports whether err from makeRoom must be returned to the
+// caller rather than retried.
+func abortsRebalance(err error) bool {
+	return errors.Is(err, ErrRewriteLimitExceeded) ||
+		errors.Is(err, ErrRebalanceBudgetExceeded) ||
+		errors.Is(err, ErrRebalanceRequired) ||
+		errors.Is(err, ErrNormalizationRequired)
 }
 
-func (l ReorderableList) tryRebalanceFrom(position uint, direction int, config *Config) bool {
+// canSplitAt reports whether the first pass of tryRebalanceFrom will succeed,
+// in which case only a single key is rewritten.
+func (l ReorderableList) canSplitAt(position uint, direction int, config *Config) bool {
 	if direction > 0 && position >= uint(len(l)-1) {
-		return false // at end of list
+		return false
 	}
 	if direction < 0 && position == 0 {
-		return false // at start of list
+		return false
+	}
+
+	var err error
+	if direction > 0 {
+		_, err = Between(l[position].GetKey(), l[position+1].GetKey(), config)
+	} else {
+		_, err = Between(l[position-1].GetKey(), l[position].GetKey(), config)
+	}
+	return err == nil
+}
+
+// tryRebalanceFrom shifts keys one at a time in the given direction. It only
+// reports success when the pair at position can be split on the first pass,
+// which requires that pair to be strictly sorted. Duplicate or unsorted pairs
+// further along are skipped rather than repaired, so callers must normalise
+// when it reports false. It stops with a *RebalanceBudgetError once
+// config.MaxRebalanceWrites keys have been shifted.
+func (l ReorderableList) tryRebalanceFrom(position uint, direction int, config *Config) (bool, error) {
+	if direction > 0 && position >= uint(len(l)-1) {
+		return false, nil // at end of list
+	}
+	if direction < 0 && position == 0 {
+		return false, nil // at start of list
+	}
+
+	var rewritten []int
+	rewrite := func(i int, k Key) error {
+		if config.MaxRebalanceWrites > 0 && len(rewritten) >= config.MaxRebalanceWrites {
+			sort.Ints(rewritten)
+			return &RebalanceBudgetError{Limit: config.MaxRebalanceWrites, Rewritten: rewritten}
+		}
+		l.setKey(i, k, AuditRebalance, config)
+		rewritten = append(rewritten, i)
+		return nil
 	}
 
 	if direction > 0 {
@@ -158,10 +357,12 @@ func (l ReorderableList) tryRebalanceFrom(position uint, direction int, config *
 
 			nextKey, err := Between(curr, next, config)
 			if err == nil {
-				l[i+1].SetKey(*nextKey)
+				if err := rewrite(i+1, *nextKey); err != nil {
+					return false, err
+				}
 				if i == int(position) {
 					// first pass worked, can exit early.
-					return true
+					return true, nil
 				}
 			}
 
@@ -175,10 +376,12 @@ func (l ReorderableList) tryRebalanceFrom(position uint, direction int, config *
 			// For backward rebalancing, we need prev < curr, so swap arguments
 			nextKey, err := Between(prev, curr, config)
 			if err == nil {
-				l[i].SetKey(*nextKey)
+				if err := rewrite(i, *nextKey); err != nil {
+					return false, err
+				}
 				if i == int(position) {
 					// first pass worked, can exit early.
-					return true
+					return true, nil
 				}
 			}
 
@@ -186,32 +389,173 @@ func (l ReorderableList) tryRebalanceFrom(position uint, direction int, config *
 		}
 	}
 
-	return false
+	return false, nil
 }
 
 // Normalize will distribute the keys evenly across the key space
-// using the specified configuration for precision settings.
-func (l ReorderableList) Normalize(config *Config) error {
+// using the specified configuration for precision settings. Items that already
+// hold their evenly spaced key are left alone, and the indices of the items
+// that were given a new key are returned in ascending order, so only those
+// rows need to be written back.
+func (l ReorderableList) Normalize(config *Config) ([]int, error) {
 	if !config.AutoNormalize {
-		return ErrNormalizationRequired
+		return nil, ErrNormalizationRequired
 	}
 
-	for i := range l {
-		f := float64(i+2) / float64(len(l)+3)
-		b := l[i].GetKey().bucket
+	keys, err := l.NormalizedKeys(config)
+	if err != nil {
+		return nil, err
+	}
+	config.metrics().Normalized()
+
+	var changed []int
+	for i, k := range keys {
+		if l[i].GetKey().Compare(k) == 0 {
+			continue
+		}
+		l.setKey(i, k, AuditNormalize, config)
+		changed = append(changed, i)
+	}
 
-		nextKey, err := KeyAt(b, f, config)
+	return changed, nil
+}
+
+// NormalizedKeys returns the keys Normalize would give the items of the list,
+// in order, without modifying it. Unlike Normalize it does not require
+// config.AutoNormalize, so a service can compute a normalization for the
+// caller to review and store even when inline normalization is disabled.
+func (l ReorderableList) NormalizedKeys(config *Config) (Keys, error) {
+	if err := l.checkSize(config); err != nil {
+		return nil, err
+	}
+	if err := l.checkBuckets(config); err != nil {
+		return nil, err
+	}
+
+	key := normalizedKey(len(l), config)
+	keys := make(Keys, len(l))
+	for i := range l {
+		k, err := key(i, l[i].GetKey().bucket)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		keys[i] = k
+	}
+	return keys, nil
+}
+
+// normalizedKey returns the function giving the key Normalize assigns to the
+// item at index i of a list of n items, in the given bucket. If
+// config.NormalizeMinGap is set and the key space has room for it, keys are
+// exactly MaxRankLength characters long and evenly spaced by an integer step;
+// otherwise they are placed with KeyAt.
+func normalizedKey(n int, config *Config) func(i int, bucket uint8) (Key, error) {
+	if config.NormalizeMinGap > 0 && config.MaxRankLength > 0 {
+		alphabet := config.alphabet()
+		step := alphabet.space(config.MaxRankLength)
+		step.Div(step, big.NewInt(int64(n+1)))
+		if step.Cmp(big.NewInt(config.NormalizeMinGap)) >= 0 {
+			return func(i int, bucket uint8) (Key, error) {
+				v := new(big.Int).Mul(step, big.NewInt(int64(i+1)))
+				rank := alphabet.encode(v, config.MaxRankLength)
+				return config.adopt(*makeKey(bucket, rank)), nil
+			}
+		}
+	}
+
+	return func(i int, bucket uint8) (Key, error) {
+		return KeyAt(bucket, float64(i+2)/float64(n+3), config)
+	}
+}
+
+// NormalizeRange spreads the keys of the items from index from to index to,
+// inclusive, evenly between the keys of the items just outside the range, or
+// the edges of the bucket at either end of the list. Only the dense region is
+// rewritten, so it is much cheaper than Normalize on a large list.
+//
+// The items in the range must share the bucket of their neighbours. If there
+// is not enough room between the neighbours ErrRebalanceRequired is returned,
+// and a wider range should be used. The indices of the items that were given a
+// new key are returned in ascending order.
+func (l ReorderableList) NormalizeRange(from, to uint, config *Config) ([]int, error) {
+	if from > to || to >= uint(len(l)) {
+		return nil, ErrOutOfBounds
+	}
+
+	if err := l.checkSize(config); err != nil {
+		return nil, err
+	}
+	if err := l.checkBuckets(config); err != nil {
+		return nil, err
+	}
+
+	after := bottomOf(l[from].GetKey().bucket, config)
+	if from > 0 {
+		after = l[from-1].GetKey()
+	}
+	var next *Key
+	if to+1 < uint(len(l)) {
+		k := l[to+1].GetKey()
+		next = &k
+	}
+
+	res, err := ReserveRange(after, next, int(to-from+1), config)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []int
+	for i, k := range res.Keys() {
+		j := int(from) + i
+		if l[j].GetKey().Compare(k) == 0 {
+			continue
 		}
+		l.setKey(j, k, AuditNormalize, config)
+		changed = append(changed, j)
+	}
+
+	return changed, nil
+}
+
+// checkSize returns a *ListTooLargeError if the list has more than
+// config.MaxListSize items.
+func (l ReorderableList) checkSize(config *Config) error {
+	if config.MaxListSize > 0 && len(l) > config.MaxListSize {
+		return &ListTooLargeError{Limit: config.MaxListSize, Size: len(l)}
+	}
+	return nil
+}
 
-		l[i].SetKey(nextKey)
+// checkBuckets returns a *MixedBucketsError if config.StrictBuckets is set and
+// not every key shares the bucket of the first item.
+func (l ReorderableList) checkBuckets(config *Config) error {
+	if !config.StrictBuckets || len(l) == 0 {
+		return nil
+	}
+
+	bucket := l[0].GetKey().bucket
+	var mismatches []int
+	for i := 1; i < len(l); i++ {
+		if l[i].GetKey().bucket != bucket {
+			mismatches = append(mismatches, i)
+		}
 	}
 
+	if len(mismatches) > 0 {
+		return &MixedBucketsError{Bucket: bucket, Indices: mismatches}
+	}
 	return nil
 }
 
+// IsSorted reports whether the list is strictly sorted. It is equivalent to
+// StrictlySorted; use SortedBy to take a Config.TieBreaker into account.
 func (l ReorderableList) IsSorted() bool {
+	return l.StrictlySorted()
+}
+
+// StrictlySorted reports whether every key is strictly greater than the key
+// before it. Lists containing duplicate keys are not strictly sorted.
+func (l ReorderableList) StrictlySorted() bool {
 	for i := 1; i < len(l); i++ {
 		if l[i-1].GetKey().Compare(l[i].GetKey()) >= 0 {
 			return false
@@ -219,3 +563,14 @@ func (l ReorderableList) IsSorted() bool {
 	}
 	return true
 }
+
+// WeaklySorted reports whether every key is greater than or equal to the key
+// before it. This matches sort.IsSorted, which allows duplicate keys.
+func (l ReorderableList) WeaklySorted() bool {
+	for i := 1; i < len(l); i++ {
+		if l[i-1].GetKey().Compare(l[i].GetKey()) > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -37,7 +37,7 @@ func BenchmarkReorderableList_FullSpace(b *testing.B) {
 	b.Log("Done initialising list:", len(list))
 
 	b.ResetTimer()
-	err := list.Normalize(lexorank.DefaultConfig())
+	_, err := list.Normalize(lexorank.DefaultConfig())
 	assert.NoError(b, err)
 	b.StopTimer()
 
@@ -66,7 +66,7 @@ func BenchmarkReorderableList_RandomInsert(b *testing.B) {
 	list := make(lexorank.ReorderableList, 0)
 
 	b.ResetTimer()
-	err := list.Normalize(lexorank.DefaultConfig())
+	_, err := list.Normalize(lexorank.DefaultConfig())
 	assert.NoError(b, err)
 	b.StopTimer()
 
@@ -77,7 +77,7 @@ func BenchmarkReorderableList_RandomInsert(b *testing.B) {
 
 		key, err := list.Insert(uint(pos), lexorank.DefaultConfig())
 		if err != nil {
-			err := list.Normalize(lexorank.DefaultConfig())
+			_, err := list.Normalize(lexorank.DefaultConfig())
 			assert.NoError(b, err)
 			key, err = list.Insert(uint(pos), lexorank.DefaultConfig())
 			if err != nil {
@@ -149,8 +149,8 @@ func TestReorderableList_Insert_TriggersRebalance_ProductionConfig(t *testing.T)
 	k2, _ := ParseKey("1|aaaaab") // Very close to k1
 
 	list := ReorderableList{
-		&Item{ID: 0, Rank: *k1},
-		&Item{ID: 1, Rank: *k2},
+		&Item[struct{}]{ID: "0", Rank: *k1},
+		&Item[struct{}]{ID: "1", Rank: *k2},
 	}
 
 	newKey, err := list.Insert(1, config) // insert between 0 and 1
@@ -178,12 +178,12 @@ func TestReorderableList_TryRebalanceFrom_ProductionConfig(t *testing.T) {
 	end, _ := Between(*start, TopOf(1), config) // something like 1|m
 
 	list := ReorderableList{
-		&Item{ID: 0, Rank: *start},
-		&Item{ID: 1, Rank: *end},
+		&Item[struct{}]{ID: "0", Rank: *start},
+		&Item[struct{}]{ID: "1", Rank: *end},
 	}
 
 	// We intentionally call tryRebalanceFrom on index 1, going backward (-1)
-	ok := list.tryRebalanceFrom(1, -1, config)
+	ok, _ := list.tryRebalanceFrom(1, -1, config)
 	a.True(ok, "should succeed if Between() arg order is correct")
 
 	// If successful, keys should still be sorted
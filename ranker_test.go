@@ -2,6 +2,7 @@ package lexorank
 
 import (
 	"sort"
+	"strconv"
 	"testing"
 
 	"github.com/kr/pretty"
@@ -9,19 +10,6 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-// Item is a simple struct that implements the Reorderable interface. In your
-// application, this may be a Post or Issue data model for example.
-type Item struct {
-	ID   int
-	Rank Key
-}
-
-// Implements the Orderable interface.
-func (i Item) GetKey() Key { return i.Rank }
-
-// Implements the Mutable interface
-func (i *Item) SetKey(k Key) { i.Rank = k }
-
 func TestReorderableList_Rebalance(t *testing.T) {
 	a := assert.New(t)
 
@@ -242,8 +230,8 @@ func TestReorderableList_Insert_TriggersRebalance(t *testing.T) {
 	k2.raw[7]++  // keep raw consistent
 
 	list := ReorderableList{
-		&Item{ID: 0, Rank: *k1},
-		&Item{ID: 1, Rank: k2},
+		&Item[struct{}]{ID: "0", Rank: *k1},
+		&Item[struct{}]{ID: "1", Rank: k2},
 	}
 
 	oldKey := list[1].GetKey().String()
@@ -423,12 +411,12 @@ func TestTryRebalanceFrom_BackwardFailsWithWrongBetweenOrder(t *testing.T) {
 	end, _ := Between(*start, TopOf(1), DefaultConfig()) // something like 1|m
 
 	list := ReorderableList{
-		&Item{ID: 0, Rank: *start},
-		&Item{ID: 1, Rank: *end},
+		&Item[struct{}]{ID: "0", Rank: *start},
+		&Item[struct{}]{ID: "1", Rank: *end},
 	}
 
 	// We intentionally call tryRebalanceFrom on index 1, going backward (-1)
-	ok := list.tryRebalanceFrom(1, -1, DefaultConfig())
+	ok, _ := list.tryRebalanceFrom(1, -1, DefaultConfig())
 	a.True(ok, "should succeed if Between() arg order is correct")
 
 	// If successful, keys should still be sorted
@@ -442,11 +430,11 @@ func TestTryRebalanceFrom_ForwardFirstPassSucceeds(t *testing.T) {
 	mid, _ := Between(*start, TopOf(1), DefaultConfig()) // enough space
 
 	list := ReorderableList{
-		&Item{ID: 0, Rank: *start},
-		&Item{ID: 1, Rank: *mid},
+		&Item[struct{}]{ID: "0", Rank: *start},
+		&Item[struct{}]{ID: "1", Rank: *mid},
 	}
 
-	ok := list.tryRebalanceFrom(0, 1, DefaultConfig())
+	ok, _ := list.tryRebalanceFrom(0, 1, DefaultConfig())
 	a.True(ok, "expected forward rebalance to succeed on first pass")
 	a.True(sort.IsSorted(list), "list should still be sorted")
 	a.NotEqual(mid.String(), list[1].GetKey().String(), "key should have changed during rebalance")
@@ -459,7 +447,7 @@ func TestTryRebalanceFrom_TightAtTop(t *testing.T) {
 		item(5, "0|UUUUUU"), item(6, "0|g"), item(7, "0|g"), item(8, "0|g"), item(9, "0|g"), item(10, "0|g"), item(11, "0|k"), item(12, "0|p"), item(13, "0|p"), item(14, "0|p"), item(15, "0|p"), item(16, "0|u"), item(17, "0|u"), item(18, "0|w"), item(19, "0|x"), item(20, "0|y"), item(21, "0|yU"), item(22, "0|yg"), item(23, "0|yp"), item(24, "0|yu"), item(25, "0|yw"), item(26, "0|yx"), item(27, "0|yy"), item(28, "0|yyU"), item(29, "0|yyg"), item(30, "0|yyp"), item(31, "0|yyu"), item(32, "0|yyw"), item(33, "0|yyx"), item(34, "0|yyx"), item(35, "0|yyy"), item(36, "0|yyyU"), item(37, "0|yyyp"), item(38, "0|yyyu"), item(39, "0|yyyw"), item(40, "0|yyyy"), item(41, "0|yyyyB"), item(42, "0|yyyyU"), item(43, "0|yyyyp"), item(44, "0|yyyyr"), item(45, "0|yyyyu"), item(46, "0|yyyyw"), item(47, "0|yyyyx"), item(48, "0|yyyyy"),
 	}
 
-	err := list.Normalize(DefaultConfig())
+	_, err := list.Normalize(DefaultConfig())
 	assert.NoError(t, err)
 
 	first := list[0]
@@ -473,5 +461,364 @@ func item(id int, s string) Reorderable {
 	if err != nil {
 		panic(err)
 	}
-	return &Item{ID: id, Rank: *o}
+	return &Item[struct{}]{ID: strconv.Itoa(id), Rank: *o}
+}
+
+func TestReorderableList_Sortedness(t *testing.T) {
+	a := assert.New(t)
+
+	strict := ReorderableList{item(0, "1|a"), item(1, "1|b"), item(2, "1|c")}
+	a.True(strict.StrictlySorted())
+	a.True(strict.WeaklySorted())
+	a.True(strict.IsSorted())
+
+	dupes := ReorderableList{item(0, "1|a"), item(1, "1|b"), item(2, "1|b")}
+	a.False(dupes.StrictlySorted())
+	a.True(dupes.WeaklySorted())
+	a.Equal(sort.IsSorted(dupes), dupes.WeaklySorted())
+
+	unsorted := ReorderableList{item(0, "1|b"), item(1, "1|a")}
+	a.False(unsorted.StrictlySorted())
+	a.False(unsorted.WeaklySorted())
+}
+
+func TestReorderableList_StrictBuckets(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{
+		item(0, "0|a"),
+		item(1, "1|b"),
+		item(2, "0|c"),
+		item(3, "2|d"),
+	}
+
+	// Without the flag operations carry on as before
+	_, err := list.Append(DefaultConfig())
+	r.NoError(err)
+
+	config := DefaultConfig().WithStrictBuckets(true)
+
+	_, err = list.Insert(1, config)
+	r.ErrorIs(err, ErrMixedBuckets)
+
+	var mixed *MixedBucketsError
+	r.ErrorAs(err, &mixed)
+	a.Equal(uint8(0), mixed.Bucket)
+	a.Equal([]int{1, 3}, mixed.Indices)
+
+	_, err = list.Append(config)
+	a.ErrorIs(err, ErrMixedBuckets)
+	_, err = list.Prepend(config)
+	a.ErrorIs(err, ErrMixedBuckets)
+	_, err = list.Normalize(config)
+	a.ErrorIs(err, ErrMixedBuckets)
+
+	homogeneous := ReorderableList{item(0, "1|a"), item(1, "1|b")}
+	_, err = homogeneous.Insert(1, config)
+	a.NoError(err)
+}
+
+func TestReorderableList_MaxKeysRewritten(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	newList := func() ReorderableList {
+		return ReorderableList{
+			item(0, "1|aaaaaa"),
+			item(1, "1|aaaaab"),
+			item(2, "1|aaaaac"),
+			item(3, "1|aaaaad"),
+		}
+	}
+
+	list := newList()
+	_, err := list.Insert(1, DefaultConfig().WithMaxKeysRewritten(2))
+	r.ErrorIs(err, ErrRewriteLimitExceeded)
+
+	var limit *RewriteLimitError
+	r.ErrorAs(err, &limit)
+	a.Equal(2, limit.Limit)
+	a.Equal(4, limit.Required)
+	a.Equal(newList(), list, "no key is touched when the limit is hit")
+
+	_, err = list.Append(DefaultConfig().WithMaxKeysRewritten(2))
+	a.NoError(err, "appending has room and touches nothing")
+
+	_, err = list.Insert(1, DefaultConfig().WithMaxKeysRewritten(4))
+	a.NoError(err)
+}
+
+func TestReorderableList_Rebalance_Indices(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|d"), item(3, "0|e")}
+	changed, err := list.Rebalance(1, 1, DefaultConfig())
+	r.NoError(err)
+	a.Equal([]int{2}, changed)
+	a.Equal("0|c", list[2].GetKey().String())
+
+	changed, err = list.Rebalance(3, -1, DefaultConfig())
+	r.NoError(err)
+	a.Equal([]int{3}, changed)
+	a.True(list.StrictlySorted())
+
+	changed, err = list.Rebalance(3, 1, DefaultConfig())
+	r.NoError(err)
+	a.Equal([]int{0, 1, 2, 3}, changed, "nothing to shift past the end, so the list is normalized")
+
+	_, err = list.Rebalance(4, 1, DefaultConfig())
+	a.ErrorIs(err, ErrOutOfBounds)
+	_, err = list.Rebalance(0, 0, DefaultConfig())
+	a.Error(err)
+}
+
+func TestReorderableList_Normalize_Changed(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{item(0, "0|a"), item(1, "0|a"), item(2, "0|c")}
+	changed, err := list.Normalize(DefaultConfig())
+	r.NoError(err)
+	a.Equal([]int{0, 1, 2}, changed)
+	a.True(list.StrictlySorted())
+
+	changed, err = list.Normalize(DefaultConfig())
+	r.NoError(err)
+	a.Empty(changed, "an already normalized list is left alone")
+
+	list[1].SetKey(mustKey("0|d"))
+	changed, err = list.Normalize(DefaultConfig())
+	r.NoError(err)
+	a.Equal([]int{1}, changed)
+}
+
+func TestReorderableList_NormalizedKeys(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{item(0, "0|a"), item(1, "0|a"), item(2, "0|c")}
+	before := list.Keys()
+
+	// Computed even where Normalize itself is disabled
+	config := ProductionConfig()
+	_, err := list.Normalize(config)
+	a.ErrorIs(err, ErrNormalizationRequired)
+
+	keys, err := list.NormalizedKeys(config)
+	r.NoError(err)
+	a.True(keys.strictlySorted())
+	a.Equal(before, list.Keys(), "the list is not modified")
+
+	_, err = list.Normalize(config.WithAutoNormalize(true, 0))
+	r.NoError(err)
+	a.Equal(keys, list.Keys())
+}
+
+func TestReorderableList_NormalizeRange(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{
+		item(0, "0|a"),
+		item(1, "0|m"),
+		item(2, "0|m1"),
+		item(3, "0|m2"),
+		item(4, "0|s"),
+		item(5, "0|z"),
+	}
+	before := list.Keys()
+
+	changed, err := list.NormalizeRange(1, 3, DefaultConfig())
+	r.NoError(err)
+	a.Equal([]int{1, 2, 3}, changed)
+	a.True(list.StrictlySorted())
+	a.Equal(before[0], list[0].GetKey())
+	a.Equal(before[4], list[4].GetKey())
+	a.Equal("0|e", list[1].GetKey().String())
+
+	changed, err = list.NormalizeRange(0, 0, DefaultConfig())
+	r.NoError(err)
+	a.Equal([]int{0}, changed)
+	a.True(list.StrictlySorted())
+
+	_, err = list.NormalizeRange(3, 6, DefaultConfig())
+	a.ErrorIs(err, ErrOutOfBounds)
+	_, err = list.NormalizeRange(2, 1, DefaultConfig())
+	a.ErrorIs(err, ErrOutOfBounds)
+
+	tight := ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|b"), item(3, "0|c")}
+	_, err = tight.NormalizeRange(1, 2, DefaultConfig().WithMaxRankLength(1))
+	a.ErrorIs(err, ErrRebalanceRequired)
+}
+
+func TestReorderableList_MaxRebalanceWrites(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{item(0, "0|a"), item(1, "0|a0"), item(2, "0|b"), item(3, "0|c")}
+	config := DefaultConfig().WithMaxRankLength(2).WithMaxRebalanceWrites(1)
+
+	changed, err := list.Rebalance(0, 1, config)
+	r.ErrorIs(err, ErrRebalanceBudgetExceeded)
+
+	var budget *RebalanceBudgetError
+	r.ErrorAs(err, &budget)
+	a.Equal(1, budget.Limit)
+	a.Equal([]int{2}, budget.Rewritten)
+	a.Equal([]int{2}, changed)
+	a.True(list.WeaklySorted(), "shifted keys stay in order")
+	a.Equal("0|a", list[0].GetKey().String(), "no fallback to Normalize")
+}
+
+func TestReorderableList_NormalizeMinGap(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig().WithMaxRankLength(2).WithNormalizeMinGap(1000)
+	list := ReorderableList{item(0, "0|a"), item(1, "0|a"), item(2, "0|a"), item(3, "0|a")}
+
+	_, err := list.Normalize(config)
+	r.NoError(err)
+	a.True(list.StrictlySorted())
+	for i := 1; i < len(list); i++ {
+		gap := list[i-1].GetKey().Distance(list[i].GetKey())
+		a.GreaterOrEqual(gap.Int64(), int64(1000))
+		a.Len(list[i].GetKey().RankString(), 2)
+	}
+
+	step := config.WithAppendStrategy(AppendStrategyStep).WithStepSize(1000)
+	k, err := SmartAppend(list[1].GetKey(), step)
+	r.NoError(err)
+	a.True(k.Compare(list[2].GetKey()) < 0, "a step append does not reach the next key")
+
+	// Too many items for the requested gap: KeyAt spacing is used as usual
+	crowded := make(ReorderableList, 10)
+	for i := range crowded {
+		crowded[i] = item(i, "0|a")
+	}
+	_, err = crowded.Normalize(config)
+	r.NoError(err)
+	a.True(crowded.StrictlySorted())
+}
+
+func TestReorderableList_Append_Geometric(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig().
+		WithAppendStrategy(AppendStrategyGeometric).
+		WithStepSize(1).
+		WithMaxStepSize(8)
+
+	list := ReorderableList{item(0, "0|a000")}
+	for range 6 {
+		k, err := list.Append(config)
+		r.NoError(err)
+		list = append(list, &SnapshotItem{Key: k})
+	}
+
+	var keys []string
+	for _, it := range list {
+		keys = append(keys, it.GetKey().String())
+	}
+	// Gaps of 1, 2, 4 and 8, then capped at 8
+	a.Equal([]string{"0|a000", "0|a001", "0|a003", "0|a007", "0|a00?", "0|a00G", "0|a00O"}, keys)
+
+	// Prepends grow their gaps the same way
+	k, err := list[:2].Prepend(config)
+	r.NoError(err)
+	a.Equal(-1, k.Compare(list[0].GetKey()))
+
+	// A standalone SmartAppend steps by StepSize
+	k2, err := SmartAppend(mustKey("0|a000"), config)
+	r.NoError(err)
+	a.Equal("0|a001", k2.String())
+}
+
+func TestReorderableList_Insert_RebalanceStrategy(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	tight := func() ReorderableList {
+		return ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|b0"), item(3, "0|c")}
+	}
+	config := DefaultConfig().WithMaxRankLength(2)
+
+	l := tight()
+	before := l.Keys()
+	_, err := l.Insert(2, config.WithRebalanceStrategy(RebalanceErrorOnly))
+	r.ErrorIs(err, ErrRebalanceRequired)
+	a.Empty(l.Changes(before))
+
+	l = tight()
+	var k *Key
+	changed, err := l.Track(func() error {
+		var err error
+		k, err = l.Insert(2, config.WithRebalanceStrategy(RebalanceFullNormalize))
+		return err
+	})
+	r.NoError(err)
+	a.Equal([]int{0, 1, 2, 3}, changed)
+	a.Equal(1, k.Compare(l[1].GetKey()))
+	a.Equal(-1, k.Compare(l[2].GetKey()))
+
+	_, err = tight().Insert(2, config.WithRebalanceStrategy(RebalanceFullNormalize).WithMaxKeysRewritten(2))
+	r.ErrorIs(err, ErrRewriteLimitExceeded)
+
+	a.ErrorContains(config.WithRebalanceStrategy(RebalanceStrategy(7)).Validate(), "unknown RebalanceStrategy")
+}
+
+func TestReorderableList_Insert_NormalizeThreshold(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	tight := func() ReorderableList {
+		return ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|b0")}
+	}
+	config := DefaultConfig().WithMaxRankLength(2)
+
+	// The list is too large to normalise inline
+	l := tight()
+	before := l.Keys()
+	_, err := l.Insert(2, config.WithAutoNormalize(true, 3))
+	r.ErrorIs(err, ErrNormalizationRequired)
+	a.Empty(l.Changes(before))
+
+	// but an explicit Normalize still runs
+	_, err = l.Normalize(config.WithAutoNormalize(true, 3))
+	r.NoError(err)
+
+	l = tight()
+	_, err = l.Insert(2, config.WithAutoNormalize(true, 4))
+	r.NoError(err)
+
+	_, err = tight().Insert(2, config.WithAutoNormalize(false, 0))
+	r.ErrorIs(err, ErrNormalizationRequired)
+}
+
+func TestReorderableList_MaxListSize(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	l := ReorderableList{item(0, "0|a"), item(1, "0|c"), item(2, "0|e")}
+	config := DefaultConfig().WithMaxListSize(2)
+
+	_, err := l.Insert(1, config)
+	r.ErrorIs(err, ErrListTooLarge)
+	var tle *ListTooLargeError
+	r.ErrorAs(err, &tle)
+	a.Equal(3, tle.Size)
+	a.Equal(2, tle.Limit)
+
+	_, err = l.Append(config)
+	a.ErrorIs(err, ErrListTooLarge)
+	_, err = l.Move(0, 2, config)
+	a.ErrorIs(err, ErrListTooLarge)
+	_, err = l.Normalize(config)
+	a.ErrorIs(err, ErrListTooLarge)
+
+	_, err = l.Insert(1, config.WithMaxListSize(3))
+	a.NoError(err)
 }
@@ -0,0 +1,105 @@
+package lexorank
+
+// Ranker binds a Config so it is set once instead of being passed to every
+// call. Mixing configurations on the same list, e.g. different alphabets or
+// rank lengths, produces keys that don't sort with the existing ones; a Ranker
+// rules that out by construction.
+type Ranker struct {
+	config *Config
+}
+
+// NewRanker creates a Ranker using a copy of config, so later changes to
+// config don't affect it. A nil config uses DefaultConfig.
+func NewRanker(config *Config) *Ranker {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	c := *config
+	return &Ranker{config: &c}
+}
+
+// Config returns a copy of the bound configuration.
+func (r *Ranker) Config() Config { return *r.config }
+
+// ParseKey parses a key with the bound alphabet and separator.
+func (r *Ranker) ParseKey(s string) (*Key, error) { return r.config.ParseKey(s) }
+
+// NewKey builds a key from a bucket and a rank, see Config.NewKey.
+func (r *Ranker) NewKey(bucket uint8, rank string) (*Key, error) {
+	return r.config.NewKey(bucket, rank)
+}
+
+// MinKey returns the lowest key of a bucket, see MinKey.
+func (r *Ranker) MinKey(bucket uint8) Key { return MinKey(bucket, r.config) }
+
+// MaxKey returns the highest key of a bucket, see MaxKey.
+func (r *Ranker) MaxKey(bucket uint8) Key { return MaxKey(bucket, r.config) }
+
+// KeyAt returns the key at fraction f of a bucket, see KeyAt.
+func (r *Ranker) KeyAt(bucket uint8, f float64) (Key, error) { return KeyAt(bucket, f, r.config) }
+
+// Between returns a key between lhs and rhs, see Between.
+func (r *Ranker) Between(lhs, rhs Key) (*Key, error) { return Between(lhs, rhs, r.config) }
+
+// BetweenN returns n evenly spaced keys between lhs and rhs, see BetweenN.
+func (r *Ranker) BetweenN(lhs, rhs Key, n int) ([]Key, error) { return BetweenN(lhs, rhs, n, r.config) }
+
+// Spread returns n keys spaced evenly across a bucket, see Spread.
+func (r *Ranker) Spread(bucket uint8, n int) ([]Key, error) { return Spread(bucket, n, r.config) }
+
+// After returns a key after last using the append strategy, see SmartAppend.
+func (r *Ranker) After(last Key) (*Key, error) { return SmartAppend(last, r.config) }
+
+// Before returns a key before first using the append strategy, see
+// SmartPrepend.
+func (r *Ranker) Before(first Key) (*Key, error) { return SmartPrepend(first, r.config) }
+
+// AfterN returns n keys after last, see AfterN.
+func (r *Ranker) AfterN(last Key, n int) ([]Key, error) { return AfterN(last, n, r.config) }
+
+// BeforeN returns n keys before first, see BeforeN.
+func (r *Ranker) BeforeN(first Key, n int) ([]Key, error) { return BeforeN(first, n, r.config) }
+
+// Insert returns a new key for an item placed at position in l, see
+// ReorderableList.Insert.
+func (r *Ranker) Insert(l ReorderableList, position uint) (*Key, error) {
+	return l.Insert(position, r.config)
+}
+
+// Append returns a new key ordered after the last item of l, see
+// ReorderableList.Append.
+func (r *Ranker) Append(l ReorderableList) (Key, error) { return l.Append(r.config) }
+
+// Prepend returns a new key ordered before the first item of l, see
+// ReorderableList.Prepend.
+func (r *Ranker) Prepend(l ReorderableList) (Key, error) { return l.Prepend(r.config) }
+
+// Move moves the item of l at index from to index to, see
+// ReorderableList.Move.
+func (r *Ranker) Move(l ReorderableList, from, to uint) ([]int, error) {
+	return l.Move(from, to, r.config)
+}
+
+// Rebalance makes room next to the item of l at position, see
+// ReorderableList.Rebalance.
+func (r *Ranker) Rebalance(l ReorderableList, position uint, direction int) ([]int, error) {
+	return l.Rebalance(position, direction, r.config)
+}
+
+// Normalize spreads the keys of l evenly, see ReorderableList.Normalize.
+func (r *Ranker) Normalize(l ReorderableList) ([]int, error) { return l.Normalize(r.config) }
+
+// NormalizedKeys returns the keys Normalize would give the items of l, see
+// ReorderableList.NormalizedKeys.
+func (r *Ranker) NormalizedKeys(l ReorderableList) (Keys, error) { return l.NormalizedKeys(r.config) }
+
+// ApplyChangeSet validates and applies cs to l, see
+// ReorderableList.ApplyChangeSet.
+func (r *Ranker) ApplyChangeSet(l ReorderableList, cs ChangeSet) error {
+	return l.ApplyChangeSet(cs, r.config)
+}
+
+// Sync creates a SyncReorderableList over items bound to this configuration.
+func (r *Ranker) Sync(items []Reorderable) *SyncReorderableList {
+	return NewSyncReorderableList(items, r.config)
+}
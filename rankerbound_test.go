@@ -0,0 +1,40 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRanker_BindsConfig(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig().WithMaxRankLength(2)
+	rk := NewRanker(config)
+
+	// Later changes to the config don't leak into the ranker
+	config.MaxRankLength = 10
+	a.Equal(2, rk.Config().MaxRankLength)
+
+	l := ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|b0")}
+	k, err := rk.Insert(l, 2)
+	r.NoError(err)
+	a.LessOrEqual(len(k.rank), 2)
+	a.True(l.StrictlySorted())
+
+	lhs, err := rk.ParseKey("0|a")
+	r.NoError(err)
+	rhs, err := rk.ParseKey("0|c")
+	r.NoError(err)
+	mid, err := rk.Between(*lhs, *rhs)
+	r.NoError(err)
+	a.Equal("0|b", mid.String())
+
+	keys, err := rk.Spread(1, 3)
+	r.NoError(err)
+	a.Len(keys, 3)
+
+	a.Equal(DefaultConfig().MaxRankLength, NewRanker(nil).Config().MaxRankLength)
+}
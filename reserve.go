@@ -0,0 +1,189 @@
+package lexorank
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Reservation is a block of evenly spaced keys reserved between two existing
+// keys. Keys are fully determined by Start, Step and the reserved length, so a
+// bulk import can generate all of them client-side without any further
+// coordination.
+type Reservation struct {
+	Start Key
+	Step  *big.Int
+	Count int
+
+	bucket uint8
+	config *Config
+	length int
+	start  *big.Int
+}
+
+// ReserveRange reserves room for count sequential keys after the given key and
+// before next. A nil next means the keys run up to the top of the bucket.
+// It returns ErrRebalanceRequired if the keys cannot fit within MaxRankLength.
+func ReserveRange(after Key, next *Key, count int, config *Config) (*Reservation, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("count must be positive, got %d", count)
+	}
+
+	alphabet := config.alphabet()
+	upper := topOf(after.bucket, config)
+	if next != nil {
+		upper = *next
+	}
+	if after.bucket != upper.bucket {
+		return nil, fmt.Errorf("keys must be in the same bucket")
+	}
+
+	// Make sure the bounds are ordered at a length where both are exact
+	common := max(len(after.rank), len(upper.rank), 1)
+	if alphabet.scale(after.rank, common).Cmp(alphabet.scale(upper.rank, common)) >= 0 {
+		return nil, fmt.Errorf("left key must be less than right key")
+	}
+
+	// Find the shortest length at which all keys fit
+	slots := big.NewInt(int64(count) + 1)
+	for L := max(config.MinRankLength, 1); ; L++ {
+		if config.MaxRankLength > 0 && L > max(config.MaxRankLength, common) {
+			return nil, ErrRebalanceRequired
+		}
+
+		lo, hi := rankBoundsAt(alphabet, after.rank, upper.rank, L)
+		gap := new(big.Int).Sub(hi, lo)
+		step := gap.Div(gap, slots)
+
+		if step.Sign() > 0 {
+			start := new(big.Int).Add(lo, step)
+			return &Reservation{
+				Start:  config.adopt(*makeKey(after.bucket, alphabet.encode(start, L))),
+				Step:   step,
+				Count:  count,
+				bucket: after.bucket,
+				config: config,
+				length: L,
+				start:  start,
+			}, nil
+		}
+	}
+}
+
+// rankBoundsAt returns the exclusive numeric bounds, at the given length, of
+// the ranks strictly between lo and hi. Ranks longer than length are rounded
+// outwards, so every value strictly between the bounds sorts between lo and hi.
+func rankBoundsAt(alphabet *Alphabet, lo, hi []byte, length int) (*big.Int, *big.Int) {
+	l := alphabet.scale(lo, length)
+	if len(lo) > length {
+		l.Div(l, alphabet.space(len(lo)-length))
+	}
+
+	h := alphabet.scale(hi, length)
+	if len(hi) > length {
+		div := alphabet.space(len(hi) - length)
+		q, m := new(big.Int).DivMod(h, div, new(big.Int))
+		if m.Sign() > 0 {
+			q.Add(q, big.NewInt(1))
+		}
+		h = q
+	}
+
+	return l, h
+}
+
+// Key returns the i-th reserved key, starting at zero.
+func (r *Reservation) Key(i int) (Key, error) {
+	if i < 0 || i >= r.Count {
+		return Key{}, ErrOutOfBounds
+	}
+
+	v := new(big.Int).Mul(r.Step, big.NewInt(int64(i)))
+	v.Add(v, r.start)
+
+	return r.config.adopt(*makeKey(r.bucket, r.config.alphabet().encode(v, r.length))), nil
+}
+
+// Keys returns all reserved keys in order.
+func (r *Reservation) Keys() Keys {
+	keys := make(Keys, r.Count)
+	for i := range keys {
+		keys[i], _ = r.Key(i)
+	}
+	return keys
+}
+
+// BetweenN returns n strictly increasing keys spread evenly between lhs and
+// rhs, for inserting a block of items at once. The gap is divided a single
+// time, so the keys are as short as possible and each keeps an equal share of
+// the room around it, unlike n chained calls to Between. Zero keys leave a side
+// open, as with Between.
+func BetweenN(lhs, rhs Key, n int, config *Config) ([]Key, error) {
+	switch {
+	case lhs.IsZero() && rhs.IsZero():
+		lhs, rhs = MinKey(0, config), MaxKey(0, config)
+	case lhs.IsZero():
+		lhs = MinKey(rhs.bucket, config)
+	case rhs.IsZero():
+		rhs = MaxKey(lhs.bucket, config)
+	}
+
+	res, err := ReserveRange(lhs, &rhs, n, config)
+	if err != nil {
+		return nil, err
+	}
+	return res.Keys(), nil
+}
+
+// Spread returns n keys spaced evenly across the whole key space of a bucket,
+// for seeding a new table or bulk import so that later inserts have as much
+// room as possible on every side.
+func Spread(bucket uint8, n int, config *Config) ([]Key, error) {
+	return BetweenN(MinKey(bucket, config), MaxKey(bucket, config), n, config)
+}
+
+// AfterN returns n ascending keys after last, each generated from the previous
+// one with SmartAppend, for appending a block of items. A zero last stands for
+// an empty list, whose first keys are spread across the whole bucket by
+// BetweenN so that later appends and prepends have room on both sides.
+func AfterN(last Key, n int, config *Config) ([]Key, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("count must be positive, got %d", n)
+	}
+	if last.IsZero() {
+		return BetweenN(Key{}, Key{}, n, config)
+	}
+
+	keys := make([]Key, 0, n)
+	for len(keys) < n {
+		k, err := SmartAppend(last, config)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *k)
+		last = *k
+	}
+	return keys, nil
+}
+
+// BeforeN returns n ascending keys before first, generated from first downwards
+// with SmartPrepend, for prepending a block of items. A zero first stands for an
+// empty list, as with AfterN.
+func BeforeN(first Key, n int, config *Config) ([]Key, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("count must be positive, got %d", n)
+	}
+	if first.IsZero() {
+		return BetweenN(Key{}, Key{}, n, config)
+	}
+
+	keys := make([]Key, n)
+	for i := n - 1; i >= 0; i-- {
+		k, err := SmartPrepend(first, config)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = *k
+		first = *k
+	}
+	return keys, nil
+}
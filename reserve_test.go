@@ -0,0 +1,182 @@
+package lexorank
+
+import (
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReserveRange(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	after, err := ParseKey("0|a")
+	r.NoError(err)
+	next, err := ParseKey("0|b")
+	r.NoError(err)
+
+	res, err := ReserveRange(*after, next, 100, DefaultConfig())
+	r.NoError(err)
+	a.Equal(100, res.Count)
+	a.Equal(res.Start.String(), res.Keys()[0].String())
+
+	keys := res.Keys()
+	strs := make([]string, 0, len(keys)+2)
+	strs = append(strs, after.String())
+	for _, k := range keys {
+		strs = append(strs, k.String())
+	}
+	strs = append(strs, next.String())
+
+	a.True(sort.StringsAreSorted(strs), "reserved keys must sort between the bounds")
+	for i := 1; i < len(strs); i++ {
+		a.NotEqual(strs[i-1], strs[i], "reserved keys must be unique")
+	}
+
+	_, err = res.Key(100)
+	a.ErrorIs(err, ErrOutOfBounds)
+}
+
+func TestReserveRange_ToTop(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	after, err := ParseKey("0|U")
+	r.NoError(err)
+
+	res, err := ReserveRange(*after, nil, 10, DefaultConfig())
+	r.NoError(err)
+
+	keys := res.Keys()
+	a.True(keys[0].Compare(*after) > 0)
+	a.True(keys[len(keys)-1].Compare(TopOf(0)) < 0)
+}
+
+func TestReserveRange_NoRoom(t *testing.T) {
+	r := require.New(t)
+
+	after, err := ParseKey("0|aaaaaa")
+	r.NoError(err)
+	next, err := ParseKey("0|aaaaab")
+	r.NoError(err)
+
+	_, err = ReserveRange(*after, next, 10, DefaultConfig())
+	r.ErrorIs(err, ErrRebalanceRequired)
+
+	_, err = ReserveRange(*after, next, 0, DefaultConfig())
+	r.Error(err)
+}
+
+func TestReserveRange_Shortest(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	after := mustKey("0|aaaaaa")
+	next := mustKey("0|zzzzzz")
+
+	res, err := ReserveRange(after, &next, 3, DefaultConfig())
+	r.NoError(err)
+
+	for _, k := range res.Keys() {
+		a.Len(k.rank, 1, "keys use the shortest length that fits")
+		a.True(k.Compare(after) > 0)
+		a.True(k.Compare(next) < 0)
+	}
+}
+
+func TestBetweenN(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig()
+	lhs, rhs := mustKey("0|a"), mustKey("0|b")
+
+	keys, err := BetweenN(lhs, rhs, 50, config)
+	r.NoError(err)
+	r.Len(keys, 50)
+
+	prev := lhs
+	for _, k := range keys {
+		a.Equal(1, k.Compare(prev), "%s after %s", k, prev)
+		a.Len(k.RankString(), 2)
+		prev = k
+	}
+	a.Equal(-1, prev.Compare(rhs))
+
+	keys, err = BetweenN(Key{}, Key{}, 3, config)
+	r.NoError(err)
+	a.Equal([]string{"0|B", "0|T", "0|f"}, []string{keys[0].String(), keys[1].String(), keys[2].String()})
+
+	_, err = BetweenN(lhs, rhs, 0, config)
+	a.Error(err)
+	_, err = BetweenN(mustKey("0|aaaaa0"), mustKey("0|aaaaa1"), 2, config)
+	a.ErrorIs(err, ErrRebalanceRequired)
+}
+
+func TestAfterNBeforeN(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig()
+	k := mustKey("0|a")
+
+	after, err := AfterN(k, 3, config)
+	r.NoError(err)
+	r.Len(after, 3)
+	a.True(append(Keys{k}, after...).strictlySorted())
+	next, err := SmartAppend(after[1], config)
+	r.NoError(err)
+	a.Equal(after[2], *next, "each key follows the previous one")
+
+	before, err := BeforeN(k, 3, config)
+	r.NoError(err)
+	r.Len(before, 3)
+	a.True(append(Keys(before), k).strictlySorted())
+	prev, err := SmartPrepend(before[1], config)
+	r.NoError(err)
+	a.Equal(before[0], *prev, "the keys run down from first")
+
+	// An empty list is seeded across the whole bucket
+	seed, err := BetweenN(Key{}, Key{}, 3, config)
+	r.NoError(err)
+	after, err = AfterN(Key{}, 3, config)
+	r.NoError(err)
+	a.Equal(seed, after)
+	before, err = BeforeN(Key{}, 3, config)
+	r.NoError(err)
+	a.Equal(seed, before)
+
+	_, err = AfterN(k, 0, config)
+	a.Error(err)
+	_, err = BeforeN(k, -1, config)
+	a.Error(err)
+}
+
+func TestSpread(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig()
+
+	keys, err := Spread(1, 1000, config)
+	r.NoError(err)
+	r.Len(keys, 1000)
+
+	list := make(ReorderableList, len(keys))
+	for i, k := range keys {
+		a.Equal(uint8(1), k.Bucket())
+		a.Len(k.RankString(), 2, "1000 keys fit in two digits")
+		list[i] = item(i, k.String())
+	}
+	a.True(list.StrictlySorted())
+
+	// Every gap is the same size, give or take rounding
+	stats := list.Stats(config)
+	a.LessOrEqual(new(big.Int).Sub(stats.MaxGap, stats.MinGap).Cmp(config.alphabet().space(stats.Scale-2)), 0)
+
+	_, err = Spread(0, 0, config)
+	a.Error(err)
+}
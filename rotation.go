@@ -0,0 +1,116 @@
+package lexorank
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// BucketCount is the number of buckets keys rotate through, as in Jira's
+// LexoRank: 0, 1, 2 and back to 0.
+const BucketCount = 3
+
+// MaxBucket is the highest bucket a key can have, since buckets are written as
+// a single digit. Buckets above BucketCount-1 are valid but take no part in
+// the rotation.
+const MaxBucket = 9
+
+// NextBucket returns the bucket following b in the rotation.
+func NextBucket(b uint8) uint8 {
+	return (b + 1) % BucketCount
+}
+
+// PrevBucket returns the bucket preceding b in the rotation.
+func PrevBucket(b uint8) uint8 {
+	return (b + BucketCount - 1) % BucketCount
+}
+
+// BucketExhausted reports whether the list has run out of room in its current
+// bucket and should be migrated to the next one: some neighbours have no key
+// left between them, or a rank has reached WarnRankLength when one is set.
+func (l ReorderableList) BucketExhausted(config *Config) bool {
+	if config.WarnRankLength > 0 {
+		for _, it := range l {
+			if len(it.GetKey().rank) >= config.WarnRankLength {
+				return true
+			}
+		}
+	}
+	return l.NeedsRebalance(0, config)
+}
+
+// MigrateToBucket gives every item a new key in the target bucket, spread
+// evenly across it in list order, like Jira's bucket rotation. Because the old
+// and new keys live in different buckets, storage can be updated row by row
+// while untouched rows keep their old keys. Use NextBucket(b) as the target to
+// rotate.
+//
+// The indices of the items with new keys are returned in ascending order.
+func (l ReorderableList) MigrateToBucket(target uint8, config *Config) ([]int, error) {
+	if target > MaxBucket {
+		return nil, fmt.Errorf("invalid bucket: %d (maximum %d)", target, MaxBucket)
+	}
+	if len(l) == 0 {
+		return nil, nil
+	}
+
+	keys, err := Spread(target, len(l), config)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []int
+	for i, k := range keys {
+		if l[i].GetKey().Compare(k) == 0 {
+			continue
+		}
+		l.setKey(i, k, AuditMigrateBucket, config)
+		changed = append(changed, i)
+	}
+	return changed, nil
+}
+
+// CompareCyclic orders keys like Compare, except that buckets are ranked by
+// their position in the rotation starting at active rather than by value. With
+// active set to the bucket a migration moves items out of, items that have not
+// been migrated yet sort before the ones already moved to NextBucket(active),
+// so a list caught halfway through a migration from bucket 2 to bucket 0 keeps
+// its order. Buckets from BucketCount up take no part in the rotation and sort
+// after it, by value.
+func (k Key) CompareCyclic(b Key, active uint8) int {
+	ka, kb := cyclicPosition(k.bucket, active), cyclicPosition(b.bucket, active)
+	switch {
+	case ka < kb:
+		return -1
+	case ka > kb:
+		return 1
+	}
+	return bytes.Compare(k.rank, b.rank)
+}
+
+// cyclicPosition returns the rank of bucket in the rotation starting at
+// active. Buckets outside the rotation keep their value, which ranks them
+// after every bucket in it.
+func cyclicPosition(bucket, active uint8) uint8 {
+	if bucket >= BucketCount {
+		return bucket
+	}
+	return (bucket + BucketCount - active%BucketCount) % BucketCount
+}
+
+// SortCyclic orders the list in place using CompareCyclic. The sort is stable.
+func (l ReorderableList) SortCyclic(active uint8) {
+	sort.SliceStable(l, func(i, j int) bool {
+		return l[i].GetKey().CompareCyclic(l[j].GetKey(), active) < 0
+	})
+}
+
+// SortedCyclic reports whether the list is strictly sorted by CompareCyclic.
+func (l ReorderableList) SortedCyclic(active uint8) bool {
+	for i := 1; i < len(l); i++ {
+		if l[i-1].GetKey().CompareCyclic(l[i].GetKey(), active) >= 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextBucket(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(uint8(1), NextBucket(0))
+	a.Equal(uint8(2), NextBucket(1))
+	a.Equal(uint8(0), NextBucket(2))
+	a.Equal(uint8(2), PrevBucket(0))
+}
+
+func TestReorderableList_MigrateToBucket(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig().WithMaxRankLength(2)
+	list := ReorderableList{item(0, "0|a"), item(1, "0|a1"), item(2, "0|a2")}
+	a.True(list.BucketExhausted(config))
+
+	changed, err := list.MigrateToBucket(NextBucket(0), config)
+	r.NoError(err)
+	a.Equal([]int{0, 1, 2}, changed)
+	a.True(list.StrictlySorted())
+	for _, it := range list {
+		a.Equal(uint8(1), it.GetKey().Bucket())
+	}
+	a.False(list.BucketExhausted(config))
+	a.True(list.BucketExhausted(config.WithWarnRankLength(1, nil)))
+
+	_, err = list.MigrateToBucket(MaxBucket+1, config)
+	a.Error(err)
+}
+
+func TestKey_CompareCyclic(t *testing.T) {
+	a := assert.New(t)
+
+	old, moved := mustKey("2|z"), mustKey("0|a")
+	a.Equal(1, old.Compare(moved))
+	a.Equal(-1, old.CompareCyclic(moved, 2))
+	a.Equal(1, moved.CompareCyclic(old, 2))
+	a.Equal(-1, mustKey("2|a").CompareCyclic(mustKey("2|b"), 2))
+	a.Equal(-1, mustKey("0|z").CompareCyclic(mustKey("1|a"), 0))
+
+	// Buckets outside the rotation are not folded into it, and sort after it
+	for active := uint8(0); active < BucketCount; active++ {
+		a.Equal(1, mustKey("3|a").CompareCyclic(mustKey("0|z"), active))
+		a.Equal(1, mustKey("3|a").CompareCyclic(mustKey("2|z"), active))
+		a.Equal(-1, mustKey("3|z").CompareCyclic(mustKey("9|a"), active))
+	}
+	a.Equal(-1, mustKey("3|a").CompareCyclic(mustKey("3|b"), 0))
+
+	list := ReorderableList{item(0, "0|b"), item(1, "2|y"), item(2, "0|a"), item(3, "2|x")}
+	a.False(list.SortedCyclic(2))
+	list.SortCyclic(2)
+	a.Equal([]string{"3", "1", "2", "0"}, moveIDs(list))
+	a.True(list.SortedCyclic(2))
+}
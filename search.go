@@ -0,0 +1,83 @@
+package lexorank
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Search returns the index of the first item whose key is greater than or
+// equal to k, using binary search. The list must be weakly sorted.
+func (l ReorderableList) Search(k Key) int {
+	return sort.Search(len(l), func(i int) bool {
+		return l[i].GetKey().Compare(k) >= 0
+	})
+}
+
+// Nearest returns the index and item whose key is numerically closest to k.
+// Ties are resolved in favour of the earlier item. It returns -1 and nil for
+// an empty list.
+func (l ReorderableList) Nearest(k Key) (int, Reorderable) {
+	if len(l) == 0 {
+		return -1, nil
+	}
+
+	i := l.Search(k)
+	if i == len(l) {
+		return i - 1, l[i-1]
+	}
+	if i == 0 {
+		return 0, l[0]
+	}
+
+	// Both distances must be measured at the same length to be comparable
+	prev, next := l[i-1].GetKey(), l[i].GetKey()
+	scale := max(len(prev.rank), len(k.rank), len(next.rank))
+	before := distanceAt(k.Alphabet(), prev, k, scale)
+	after := distanceAt(k.Alphabet(), k, next, scale)
+	if before.Cmp(after) <= 0 {
+		return i - 1, l[i-1]
+	}
+	return i, l[i]
+}
+
+// Window returns the items within radius positions of the item nearest to k,
+// along with the index of the first returned item. It returns an empty window
+// for an empty list.
+func (l ReorderableList) Window(k Key, radius int) (int, ReorderableList) {
+	i, _ := l.Nearest(k)
+	if i < 0 {
+		return 0, ReorderableList{}
+	}
+
+	from := max(i-radius, 0)
+	to := min(i+radius+1, len(l))
+	return from, l[from:to]
+}
+
+// ToOrdinal returns the dense zero-based position of k in the list, for APIs
+// and UIs that speak indices. The list must be sorted. It returns
+// ErrKeyNotFound if the key is not in the list, which usually means the list
+// has changed since the key was read.
+func (k Key) ToOrdinal(list ReorderableList) (uint64, error) {
+	i := list.Search(k)
+	if i == len(list) || list[i].GetKey().Compare(k) != 0 {
+		return 0, errors.Wrapf(ErrKeyNotFound, "key %s", k)
+	}
+	return uint64(i), nil
+}
+
+// FromOrdinal returns the key at the given dense position in the list. It
+// returns ErrOutOfBounds if the list has fewer items than expected.
+func FromOrdinal(i uint64, list ReorderableList) (Key, error) {
+	if i >= uint64(len(list)) {
+		return Key{}, errors.Wrapf(ErrOutOfBounds, "ordinal %d in list of %d items", i, len(list))
+	}
+	return list[i].GetKey(), nil
+}
+
+// distanceAt returns rhs - lhs with both ranks scaled to the given length.
+func distanceAt(alphabet *Alphabet, lhs, rhs Key, scale int) *big.Int {
+	return new(big.Int).Sub(alphabet.scale(rhs.rank, scale), alphabet.scale(lhs.rank, scale))
+}
@@ -0,0 +1,104 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReorderableList_Nearest(t *testing.T) {
+	a := assert.New(t)
+
+	list := ReorderableList{
+		item(0, "0|a"),
+		item(1, "0|c"),
+		item(2, "0|g"),
+	}
+
+	i, it := list.Nearest(mustKey("0|b5"))
+	a.Equal(1, i)
+	a.Equal(list[1], it)
+
+	i, _ = list.Nearest(mustKey("0|d"))
+	a.Equal(1, i)
+
+	i, _ = list.Nearest(mustKey("0|0"))
+	a.Equal(0, i)
+
+	i, _ = list.Nearest(mustKey("0|z"))
+	a.Equal(2, i)
+
+	i, _ = list.Nearest(mustKey("0|g"))
+	a.Equal(2, i)
+
+	i, it = ReorderableList{}.Nearest(mustKey("0|a"))
+	a.Equal(-1, i)
+	a.Nil(it)
+}
+
+func TestReorderableList_Window(t *testing.T) {
+	a := assert.New(t)
+
+	list := ReorderableList{
+		item(0, "0|a"),
+		item(1, "0|b"),
+		item(2, "0|c"),
+		item(3, "0|d"),
+		item(4, "0|e"),
+	}
+
+	from, w := list.Window(mustKey("0|c"), 1)
+	a.Equal(1, from)
+	a.Equal(list[1:4], w)
+
+	from, w = list.Window(mustKey("0|a"), 2)
+	a.Equal(0, from)
+	a.Equal(list[0:3], w)
+
+	from, w = list.Window(mustKey("0|z"), 10)
+	a.Equal(0, from)
+	a.Equal(list, w)
+
+	_, w = ReorderableList{}.Window(mustKey("0|a"), 1)
+	a.Empty(w)
+}
+
+func TestKey_Ordinal(t *testing.T) {
+	a := assert.New(t)
+
+	list := ReorderableList{
+		item(0, "0|a"),
+		item(1, "0|c"),
+		item(2, "0|g"),
+	}
+
+	for i := range list {
+		ord, err := list[i].GetKey().ToOrdinal(list)
+		a.NoError(err)
+		a.Equal(uint64(i), ord)
+
+		k, err := FromOrdinal(ord, list)
+		a.NoError(err)
+		a.Equal(list[i].GetKey(), k)
+	}
+
+	_, err := mustKey("0|b").ToOrdinal(list)
+	a.ErrorIs(err, ErrKeyNotFound)
+
+	_, err = FromOrdinal(3, list)
+	a.ErrorIs(err, ErrOutOfBounds)
+}
+
+func TestReorderableList_Nearest_MixedLengths(t *testing.T) {
+	list := ReorderableList{
+		item(0, "0|a"),
+		item(1, "0|aaaa"),
+	}
+
+	// "0|aa" is far closer to "0|aaaa" than to "0|a" once both distances are
+	// measured at the same length
+	i, _ := list.Nearest(mustKey("0|0"))
+	assert.Equal(t, 0, i)
+	i, _ = list.Nearest(mustKey("0|aa"))
+	assert.Equal(t, 1, i)
+}
@@ -0,0 +1,100 @@
+package lexorank
+
+import "fmt"
+
+// BetweenShortest returns a key strictly between lhs and rhs with the shortest
+// possible rank, like the fractional-indexing algorithm. Between bisects the
+// gap numerically at the length of the longer key, so repeated inserts at the
+// same position grow keys faster than necessary; BetweenShortest only adds a
+// digit once no shorter key fits. Its results never end in the minimum
+// character, so they compare the same numerically and lexicographically.
+//
+// Zero keys leave a side open, as with Between. It returns
+// ErrRebalanceRequired if the shortest key is longer than MaxRankLength.
+func BetweenShortest(lhs, rhs Key, config *Config) (*Key, error) {
+	switch {
+	case lhs.IsZero() && rhs.IsZero():
+		mid := middleOf(0, config)
+		return &mid, nil
+	case !lhs.IsZero() && !rhs.IsZero() && lhs.bucket != rhs.bucket:
+		return nil, fmt.Errorf("keys must be in the same bucket")
+	}
+
+	alphabet := config.alphabet()
+	bucket := lhs.bucket
+	if lhs.IsZero() {
+		bucket = rhs.bucket
+	}
+	if !alphabet.Contains(lhs.rank) || !alphabet.Contains(rhs.rank) {
+		return nil, fmt.Errorf("keys must only use characters from the alphabet %q", alphabet)
+	}
+
+	// Trailing minimum characters don't change the value of a rank
+	lo, hi := trimMinimum(alphabet, lhs.rank), trimMinimum(alphabet, rhs.rank)
+	if !rhs.IsZero() {
+		L := max(len(lo), len(hi), 1)
+		if alphabet.scale(lo, L).Cmp(alphabet.scale(hi, L)) >= 0 {
+			return nil, fmt.Errorf("left key must be less than right key")
+		}
+	}
+
+	rank := shortestBetween(alphabet, lo, hi, !rhs.IsZero())
+	if config.MaxRankLength > 0 && len(rank) > config.MaxRankLength {
+		return nil, ErrRebalanceRequired
+	}
+
+	k := config.adopt(*makeKey(bucket, rank))
+	return &k, nil
+}
+
+// shortestBetween returns the shortest rank strictly between lo and hi, which
+// have no trailing minimum characters. If bounded is false there is no upper
+// limit.
+func shortestBetween(alphabet *Alphabet, lo, hi []byte, bounded bool) []byte {
+	digit := func(rank []byte, i int) int {
+		if i < len(rank) {
+			return alphabet.index[rank[i]]
+		}
+		return 0
+	}
+
+	// Keep the prefix both ranks share, treating lo as padded with zeros
+	n := 0
+	if bounded {
+		for n < len(hi) && digit(lo, n) == digit(hi, n) {
+			n++
+		}
+	}
+	prefix := append([]byte{}, hi[:n]...)
+
+	da := digit(lo, n)
+	db := alphabet.Len()
+	if bounded {
+		db = digit(hi, n)
+	}
+
+	if db-da > 1 {
+		return append(prefix, alphabet.chars[(da+db)/2])
+	}
+
+	// Adjacent digits: a prefix of hi fits if hi continues past it
+	if bounded && len(hi) > n+1 {
+		return append(prefix, hi[n])
+	}
+
+	var rest []byte
+	if n < len(lo) {
+		rest = lo[n+1:]
+	}
+	return append(append(prefix, alphabet.chars[da]), shortestBetween(alphabet, rest, nil, false)...)
+}
+
+// trimMinimum removes trailing minimum characters, leaving an empty rank if
+// there is nothing else.
+func trimMinimum(alphabet *Alphabet, rank []byte) []byte {
+	n := len(rank)
+	for n > 0 && rank[n-1] == alphabet.Min() {
+		n--
+	}
+	return rank[:n]
+}
@@ -0,0 +1,78 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBetweenShortest(t *testing.T) {
+	config := DefaultConfig()
+
+	tests := []struct {
+		lhs, rhs string
+		want     string
+	}{
+		{"0|a", "0|c", "0|b"},
+		{"0|a", "0|b", "0|aU"},
+		{"0|aaaaa", "0|b", "0|an"},
+		{"0|a", "0|b1", "0|b"},
+		{"0|a0", "0|a1", "0|a0U"},
+		{"0|az", "0|b", "0|azU"},
+		{"0|abc", "0|abd", "0|abcU"},
+	}
+	for _, tt := range tests {
+		k, err := BetweenShortest(mustKey(tt.lhs), mustKey(tt.rhs), config)
+		require.NoError(t, err, "%s %s", tt.lhs, tt.rhs)
+		assert.Equal(t, tt.want, k.String(), "%s %s", tt.lhs, tt.rhs)
+	}
+}
+
+func TestBetweenShortest_OpenEnded(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig()
+
+	k, err := BetweenShortest(Key{}, mustKey("0|1"), config)
+	r.NoError(err)
+	a.Equal("0|0U", k.String())
+
+	k, err = BetweenShortest(mustKey("0|y"), Key{}, config)
+	r.NoError(err)
+	a.Equal("0|z", k.String())
+
+	k, err = BetweenShortest(mustKey("0|z"), Key{}, config)
+	r.NoError(err)
+	a.Equal("0|zU", k.String())
+
+	_, err = BetweenShortest(mustKey("0|zzzzzz"), Key{}, config)
+	a.ErrorIs(err, ErrRebalanceRequired)
+
+	_, err = BetweenShortest(mustKey("0|a"), mustKey("0|a0"), config)
+	a.Error(err)
+	_, err = BetweenShortest(mustKey("0|a"), mustKey("1|b"), config)
+	a.Error(err)
+}
+
+func TestBetweenShortest_SlowsGrowth(t *testing.T) {
+	r := require.New(t)
+
+	config := DefaultConfig().WithMaxRankLength(0)
+
+	// Repeatedly insert just before the same item
+	grow := func(between func(Key, Key, *Config) (*Key, error)) int {
+		lo, hi := mustKey("0|a"), mustKey("0|b")
+		for range 100 {
+			k, err := between(lo, hi, config)
+			r.NoError(err)
+			r.Equal(1, k.Compare(lo))
+			r.Equal(-1, k.Compare(hi))
+			lo = *k
+		}
+		return len(lo.rank)
+	}
+
+	r.LessOrEqual(grow(BetweenShortest), grow(Between))
+}
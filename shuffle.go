@@ -0,0 +1,44 @@
+package lexorank
+
+import (
+	"math/rand"
+)
+
+// ShuffleRange randomly permutes the items in [start, end) and gives them
+// fresh, evenly spaced keys between the items surrounding the range, for
+// randomised playlists or quizzes whose order is still persisted as keys. The
+// slice is permuted too, so the list stays sorted. The same rng state always
+// produces the same permutation.
+//
+// Every item in the range gets a new key, see ChangeSet for persisting them.
+func (l ReorderableList) ShuffleRange(start, end uint, rng *rand.Rand, config *Config) error {
+	if start > end || end > uint(len(l)) {
+		return ErrOutOfBounds
+	}
+	if end-start < 2 {
+		return nil
+	}
+
+	bucket := l[start].GetKey().bucket
+	after := MinKey(bucket, config)
+	if start > 0 {
+		after = l[start-1].GetKey()
+	}
+	next := MaxKey(bucket, config)
+	if end < uint(len(l)) {
+		next = l[end].GetKey()
+	}
+
+	keys, err := BetweenN(after, next, int(end-start), config)
+	if err != nil {
+		return err
+	}
+
+	r := l[start:end]
+	rng.Shuffle(len(r), func(i, j int) { r[i], r[j] = r[j], r[i] })
+	for i := range r {
+		l.setKey(int(start)+i, keys[i], AuditShuffle, config)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package lexorank
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func shuffleList() ReorderableList {
+	return ReorderableList{
+		item(1, "0|a"), item(2, "0|b"), item(3, "0|c"), item(4, "0|d"),
+		item(5, "0|e"), item(6, "0|f"), item(7, "0|g"), item(8, "0|h"),
+	}
+}
+
+func TestReorderableList_ShuffleRange(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig()
+	ids := func(l ReorderableList) []string {
+		out := make([]string, len(l))
+		for i, it := range l {
+			out[i] = it.(Identifiable).GetID()
+		}
+		return out
+	}
+
+	l := shuffleList()
+	r.NoError(l.ShuffleRange(1, 7, rand.New(rand.NewSource(3)), config))
+	a.True(l.StrictlySorted())
+	a.Equal("1", ids(l)[0])
+	a.Equal("8", ids(l)[7])
+	a.ElementsMatch([]string{"2", "3", "4", "5", "6", "7"}, ids(l)[1:7])
+	a.Equal("0|a", l[0].GetKey().String())
+	a.Equal("0|h", l[7].GetKey().String())
+
+	// The same seed gives the same order and keys
+	m := shuffleList()
+	r.NoError(m.ShuffleRange(1, 7, rand.New(rand.NewSource(3)), config))
+	a.Equal(ids(l), ids(m))
+	for i := range l {
+		a.Equal(l[i].GetKey(), m[i].GetKey())
+	}
+
+	// Whole list
+	r.NoError(l.ShuffleRange(0, uint(len(l)), rand.New(rand.NewSource(1)), config))
+	a.True(l.StrictlySorted())
+
+	a.ErrorIs(l.ShuffleRange(3, 9, rand.New(rand.NewSource(1)), config), ErrOutOfBounds)
+	a.ErrorIs(l.ShuffleRange(4, 3, rand.New(rand.NewSource(1)), config), ErrOutOfBounds)
+}
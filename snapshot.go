@@ -0,0 +1,175 @@
+package lexorank
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Identifiable describes an item with a stable identifier, usually the primary
+// key of the row it was loaded from. Items must implement it for a
+// ReorderableList to be serialised.
+type Identifiable interface {
+	GetID() string
+}
+
+// SnapshotVersion is the current version of the list snapshot formats.
+const SnapshotVersion = 1
+
+var snapshotMagic = []byte("LXRK")
+
+var (
+	_ json.Marshaler             = (ReorderableList)(nil)
+	_ json.Unmarshaler           = (*ReorderableList)(nil)
+	_ encoding.BinaryMarshaler   = (ReorderableList)(nil)
+	_ encoding.BinaryUnmarshaler = (*ReorderableList)(nil)
+)
+
+// SnapshotItem is the item type produced when a ReorderableList is restored
+// from a snapshot. It only carries the ID and key of the original item.
+type SnapshotItem struct {
+	ID  string `json:"id"`
+	Key Key    `json:"key"`
+}
+
+func (s SnapshotItem) GetID() string { return s.ID }
+func (s SnapshotItem) GetKey() Key   { return s.Key }
+func (s *SnapshotItem) SetKey(k Key) { s.Key = k }
+
+type jsonSnapshot struct {
+	Version int            `json:"version"`
+	Items   []SnapshotItem `json:"items"`
+}
+
+func (l ReorderableList) snapshotItems() ([]SnapshotItem, error) {
+	items := make([]SnapshotItem, len(l))
+	for i, it := range l {
+		id, ok := it.(Identifiable)
+		if !ok {
+			return nil, errors.Errorf("item %d of type %T does not implement Identifiable", i, it)
+		}
+		items[i] = SnapshotItem{ID: id.GetID(), Key: it.GetKey()}
+	}
+	return items, nil
+}
+
+func fromSnapshotItems(items []SnapshotItem) ReorderableList {
+	l := make(ReorderableList, len(items))
+	for i := range items {
+		l[i] = &items[i]
+	}
+	return l
+}
+
+// MarshalJSON encodes the list as a versioned snapshot of item IDs and keys.
+func (l ReorderableList) MarshalJSON() ([]byte, error) {
+	items, err := l.snapshotItems()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonSnapshot{Version: SnapshotVersion, Items: items})
+}
+
+// UnmarshalJSON restores a snapshot produced by MarshalJSON. The restored list
+// is made of *SnapshotItem values.
+func (l *ReorderableList) UnmarshalJSON(data []byte) error {
+	var s jsonSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s.Version != SnapshotVersion {
+		return errors.Errorf("unsupported snapshot version: %d", s.Version)
+	}
+	*l = fromSnapshotItems(s.Items)
+	return nil
+}
+
+// MarshalBinary encodes the list in a compact versioned binary format:
+// the "LXRK" magic, a version byte, the item count, then each item's ID and
+// key as length-prefixed strings. All integers are unsigned varints.
+func (l ReorderableList) MarshalBinary() ([]byte, error) {
+	items, err := l.snapshotItems()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(snapshotMagic)
+	buf.WriteByte(SnapshotVersion)
+	buf.Write(binary.AppendUvarint(nil, uint64(len(items))))
+	for _, it := range items {
+		writeBytes(&buf, []byte(it.ID))
+		writeBytes(&buf, it.Key.raw)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary restores a snapshot produced by MarshalBinary. The restored
+// list is made of *SnapshotItem values.
+func (l *ReorderableList) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+
+	magic := make([]byte, len(snapshotMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || !bytes.Equal(magic, snapshotMagic) {
+		return errors.New("invalid snapshot header")
+	}
+
+	version, err := r.ReadByte()
+	if err != nil {
+		return errors.Wrap(err, "reading snapshot version")
+	}
+	if version != SnapshotVersion {
+		return errors.Errorf("unsupported snapshot version: %d", version)
+	}
+
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return errors.Wrap(err, "reading snapshot length")
+	}
+	if count > uint64(r.Len()) {
+		return errors.New("snapshot length exceeds data")
+	}
+
+	items := make([]SnapshotItem, count)
+	for i := range items {
+		id, err := readBytes(r)
+		if err != nil {
+			return errors.Wrapf(err, "reading id of item %d", i)
+		}
+		raw, err := readBytes(r)
+		if err != nil {
+			return errors.Wrapf(err, "reading key of item %d", i)
+		}
+		k, err := ParseKey(string(raw))
+		if err != nil {
+			return errors.Wrapf(err, "parsing key of item %d", i)
+		}
+		items[i] = SnapshotItem{ID: string(id), Key: *k}
+	}
+
+	*l = fromSnapshotItems(items)
+	return nil
+}
+
+func writeBytes(buf *bytes.Buffer, b []byte) {
+	buf.Write(binary.AppendUvarint(nil, uint64(len(b))))
+	buf.Write(b)
+}
+
+func readBytes(r *bytes.Reader) ([]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(r.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := make([]byte, n)
+	_, err = io.ReadFull(r, b)
+	return b, err
+}
@@ -0,0 +1,71 @@
+package lexorank
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorderableList_SnapshotJSON(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{
+		item(0, "1|aaa"),
+		item(1, "1|aab"),
+		item(2, "1|aac"),
+	}
+
+	data, err := json.Marshal(list)
+	r.NoError(err)
+	a.JSONEq(`{"version":1,"items":[{"id":"0","key":"1|aaa"},{"id":"1","key":"1|aab"},{"id":"2","key":"1|aac"}]}`, string(data))
+
+	var restored ReorderableList
+	r.NoError(json.Unmarshal(data, &restored))
+	r.Len(restored, len(list))
+	for i := range list {
+		a.Equal(list[i].GetKey().String(), restored[i].GetKey().String())
+		a.Equal(list[i].(Identifiable).GetID(), restored[i].(Identifiable).GetID())
+	}
+
+	r.Error(json.Unmarshal([]byte(`{"version":99,"items":[]}`), &restored))
+}
+
+func TestReorderableList_SnapshotBinary(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{
+		item(10, "0|U"),
+		item(20, "0|UU"),
+		item(30, "0|z"),
+	}
+
+	data, err := list.MarshalBinary()
+	r.NoError(err)
+
+	var restored ReorderableList
+	r.NoError(restored.UnmarshalBinary(data))
+	r.Len(restored, len(list))
+	for i := range list {
+		a.Equal(list[i].GetKey().String(), restored[i].GetKey().String())
+		a.Equal(list[i].(Identifiable).GetID(), restored[i].(Identifiable).GetID())
+	}
+
+	r.Error(restored.UnmarshalBinary([]byte("nope")))
+	r.Error(restored.UnmarshalBinary(data[:len(data)-1]))
+}
+
+func TestReorderableList_SnapshotRequiresIdentifiable(t *testing.T) {
+	list := ReorderableList{&reorderableOnly{}}
+
+	_, err := json.Marshal(list)
+	assert.Error(t, err)
+}
+
+type reorderableOnly struct{ key Key }
+
+func (r reorderableOnly) GetKey() Key   { return r.key }
+func (r *reorderableOnly) SetKey(k Key) { r.key = k }
@@ -0,0 +1,170 @@
+package lexorank
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Execer is implemented by *sql.Tx, *sql.DB and *sql.Conn.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+var _ Execer = (*sql.Tx)(nil)
+
+// SQLOption configures ApplyChangeSetTx.
+type SQLOption func(*sqlOptions)
+
+type sqlOptions struct {
+	placeholder func(n int) string
+	batchSize   int
+	mysql       bool
+}
+
+// WithDollarPlaceholders makes generated statements use $1, $2, ... style
+// placeholders, as required by Postgres drivers. The default is ?.
+func WithDollarPlaceholders() SQLOption {
+	return func(o *sqlOptions) {
+		o.placeholder = func(n int) string { return fmt.Sprintf("$%d", n) }
+	}
+}
+
+// WithBatchSize sets how many changes are written per UPDATE statement. The
+// default is 500.
+func WithBatchSize(n int) SQLOption {
+	return func(o *sqlOptions) {
+		if n > 0 {
+			o.batchSize = n
+		}
+	}
+}
+
+// WithMySQLEscapes makes WriteChangeSetSQL escape backslashes in string
+// literals, which MySQL treats as an escape character unless the
+// NO_BACKSLASH_ESCAPES SQL mode is set. The default alphabet contains a
+// backslash, so scripts for mysql need this option.
+func WithMySQLEscapes() SQLOption {
+	return func(o *sqlOptions) {
+		o.mysql = true
+	}
+}
+
+var sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// ApplyChangeSetTx persists a change set inside a caller-provided transaction.
+// Changes are written in batches using a single statement per batch:
+//
+//	UPDATE items SET rank = CASE id WHEN ? THEN ? ... END WHERE id IN (?, ...)
+//
+// Every change must have its ID set, see ReorderableList.ChangeSet. Table and
+// column names are inserted into the statement verbatim and must be plain
+// identifiers, optionally schema-qualified; anything else is rejected.
+func ApplyChangeSetTx(ctx context.Context, tx Execer, table, idColumn, rankColumn string, cs ChangeSet, opts ...SQLOption) error {
+	o := sqlOptions{
+		placeholder: func(int) string { return "?" },
+		batchSize:   500,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	for _, ident := range []string{table, idColumn, rankColumn} {
+		if !sqlIdentifier.MatchString(ident) {
+			return errors.Errorf("invalid SQL identifier: %q", ident)
+		}
+	}
+	for _, c := range cs {
+		if c.ID == "" {
+			return errors.Errorf("change for index %d has no ID", c.Index)
+		}
+	}
+
+	for start := 0; start < len(cs); start += o.batchSize {
+		batch := cs[start:min(start+o.batchSize, len(cs))]
+		var args []any
+		query := updateStatement(table, idColumn, rankColumn, batch, func(v string) string {
+			args = append(args, v)
+			return o.placeholder(len(args))
+		})
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return errors.Wrapf(err, "updating %s", table)
+		}
+	}
+
+	return nil
+}
+
+// WriteChangeSetSQL writes a change set to w as an SQL script with the values
+// inlined, for review or for running with psql or mysql when the program has
+// no database access itself. It writes the same statements as
+// ApplyChangeSetTx, one per batch, each terminated by a semicolon. Literals
+// are quoted for Postgres and standard SQL; pass WithMySQLEscapes for mysql.
+// Options other than WithBatchSize and WithMySQLEscapes have no effect.
+func WriteChangeSetSQL(w io.Writer, table, idColumn, rankColumn string, cs ChangeSet, opts ...SQLOption) error {
+	o := sqlOptions{batchSize: 500}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	for _, ident := range []string{table, idColumn, rankColumn} {
+		if !sqlIdentifier.MatchString(ident) {
+			return errors.Errorf("invalid SQL identifier: %q", ident)
+		}
+	}
+	for _, c := range cs {
+		if c.ID == "" {
+			return errors.Errorf("change for index %d has no ID", c.Index)
+		}
+	}
+
+	for start := 0; start < len(cs); start += o.batchSize {
+		batch := cs[start:min(start+o.batchSize, len(cs))]
+		quote := quoteSQLString
+		if o.mysql {
+			quote = quoteMySQLString
+		}
+		query := updateStatement(table, idColumn, rankColumn, batch, quote)
+		if _, err := io.WriteString(w, query+";\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// quoteSQLString returns v as a standard SQL string literal.
+func quoteSQLString(v string) string {
+	return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+}
+
+// quoteMySQLString returns v as a MySQL string literal, with backslashes
+// escaped as well.
+func quoteMySQLString(v string) string {
+	return quoteSQLString(strings.ReplaceAll(v, `\`, `\\`))
+}
+
+// updateStatement builds the UPDATE for a batch. bind is called for every
+// value in order and returns the text standing in for it.
+func updateStatement(table, idColumn, rankColumn string, batch ChangeSet, bind func(string) string) string {
+	var q strings.Builder
+	fmt.Fprintf(&q, "UPDATE %s SET %s = CASE %s", table, rankColumn, idColumn)
+	for _, c := range batch {
+		fmt.Fprintf(&q, " WHEN %s THEN %s", bind(c.ID), bind(c.Key.String()))
+	}
+	fmt.Fprintf(&q, " END WHERE %s IN (", idColumn)
+	for i, c := range batch {
+		if i > 0 {
+			q.WriteString(", ")
+		}
+		q.WriteString(bind(c.ID))
+	}
+	q.WriteString(")")
+
+	return q.String()
+}
@@ -0,0 +1,95 @@
+package lexorank
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingExecer struct {
+	queries []string
+	args    [][]any
+}
+
+func (r *recordingExecer) ExecContext(_ context.Context, query string, args ...any) (sql.Result, error) {
+	r.queries = append(r.queries, query)
+	r.args = append(r.args, args)
+	return nil, nil
+}
+
+func TestApplyChangeSetTx(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{item(1, "0|a"), item(2, "0|b"), item(3, "0|c")}
+	cs, err := list.ChangeSet([]int{0, 2})
+	r.NoError(err)
+
+	tx := &recordingExecer{}
+	r.NoError(ApplyChangeSetTx(context.Background(), tx, "public.items", "id", "rank", cs))
+
+	r.Len(tx.queries, 1)
+	a.Equal("UPDATE public.items SET rank = CASE id WHEN ? THEN ? WHEN ? THEN ? END WHERE id IN (?, ?)", tx.queries[0])
+	a.Equal([]any{"1", "0|a", "3", "0|c", "1", "3"}, tx.args[0])
+}
+
+func TestApplyChangeSetTx_Options(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	cs := ChangeSet{
+		{Index: 0, ID: "1", Key: mustKey("0|a")},
+		{Index: 1, ID: "2", Key: mustKey("0|b")},
+		{Index: 2, ID: "3", Key: mustKey("0|c")},
+	}
+
+	tx := &recordingExecer{}
+	r.NoError(ApplyChangeSetTx(context.Background(), tx, "items", "id", "rank", cs, WithDollarPlaceholders(), WithBatchSize(2)))
+
+	r.Len(tx.queries, 2)
+	a.Equal("UPDATE items SET rank = CASE id WHEN $1 THEN $2 WHEN $3 THEN $4 END WHERE id IN ($5, $6)", tx.queries[0])
+	a.Equal("UPDATE items SET rank = CASE id WHEN $1 THEN $2 END WHERE id IN ($3)", tx.queries[1])
+}
+
+func TestApplyChangeSetTx_Invalid(t *testing.T) {
+	a := assert.New(t)
+	tx := &recordingExecer{}
+
+	cs := ChangeSet{{Index: 0, ID: "1", Key: mustKey("0|a")}}
+	a.Error(ApplyChangeSetTx(context.Background(), tx, "items; DROP TABLE items", "id", "rank", cs))
+	a.Error(ApplyChangeSetTx(context.Background(), tx, "items", "id", "rank", ChangeSet{{Index: 0, Key: mustKey("0|a")}}))
+	a.Empty(tx.queries)
+}
+
+func TestWriteChangeSetSQL(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	cs, err := MigratePositions([]PositionedItem{
+		{ID: "o'brien", Position: 2},
+		{ID: "1", Position: 1},
+		{ID: "2", Position: 3},
+	}, 0, DefaultConfig())
+	r.NoError(err)
+
+	var b strings.Builder
+	r.NoError(WriteChangeSetSQL(&b, "items", "id", "rank", cs, WithBatchSize(2)))
+	a.Equal("UPDATE items SET rank = CASE id WHEN 'o''brien' THEN '0|T' WHEN '1' THEN '0|B' END WHERE id IN ('o''brien', '1');\n"+
+		"UPDATE items SET rank = CASE id WHEN '2' THEN '0|f' END WHERE id IN ('2');\n", b.String())
+
+	a.Error(WriteChangeSetSQL(&b, "items; DROP TABLE items", "id", "rank", cs))
+
+	// The default alphabet includes a backslash, an escape character in MySQL
+	cs = ChangeSet{{Index: 0, ID: `a\'b`, Key: mustKey(`0|a\`)}}
+	b.Reset()
+	r.NoError(WriteChangeSetSQL(&b, "items", "id", "rank", cs))
+	a.Equal(`UPDATE items SET rank = CASE id WHEN 'a\''b' THEN '0|a\' END WHERE id IN ('a\''b');`+"\n", b.String())
+
+	b.Reset()
+	r.NoError(WriteChangeSetSQL(&b, "items", "id", "rank", cs, WithMySQLEscapes()))
+	a.Equal(`UPDATE items SET rank = CASE id WHEN 'a\\''b' THEN '0|a\\' END WHERE id IN ('a\\''b');`+"\n", b.String())
+}
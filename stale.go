@@ -0,0 +1,72 @@
+package lexorank
+
+// InsertChecked is like Insert, but first verifies that the keys the caller
+// believes surround the position still match the list. This lets an API
+// reject an insert based on an outdated view with a precise conflict response.
+// A nil prev or next means the caller expects no neighbour on that side.
+//
+// If the neighbours don't match a *StaleViewError carrying the actual
+// neighbours is returned and the list is left untouched.
+func (l ReorderableList) InsertChecked(position uint, prev, next *Key, config *Config) (*Key, error) {
+	if err := l.checkView(position, prev, next); err != nil {
+		return nil, err
+	}
+	return l.Insert(position, config)
+}
+
+// MoveChecked is like Move, but first verifies that the keys the caller
+// believes will surround the item at index to still match the list. The
+// neighbours are those of the final position, i.e. the items at to-1 and to
+// once the item at from has been taken out of the list. A nil prev or next
+// means the caller expects the item to end up first or last.
+//
+// If the neighbours don't match a *StaleViewError carrying the actual
+// neighbours is returned and the list is left untouched.
+func (l ReorderableList) MoveChecked(from, to uint, prev, next *Key, config *Config) ([]int, error) {
+	if from >= uint(len(l)) || to >= uint(len(l)) {
+		return nil, ErrOutOfBounds
+	}
+
+	rest := make(ReorderableList, 0, len(l)-1)
+	rest = append(append(rest, l[:from]...), l[from+1:]...)
+	if err := rest.checkView(to, prev, next); err != nil {
+		return nil, err
+	}
+	return l.Move(from, to, config)
+}
+
+// checkView returns a *StaleViewError if the neighbours of position are not
+// prev and next.
+func (l ReorderableList) checkView(position uint, prev, next *Key) error {
+	if position > uint(len(l)) {
+		return ErrOutOfBounds
+	}
+
+	var actualPrev, actualNext *Key
+	if position > 0 {
+		k := l[position-1].GetKey()
+		actualPrev = &k
+	}
+	if position < uint(len(l)) {
+		k := l[position].GetKey()
+		actualNext = &k
+	}
+
+	if !sameKey(prev, actualPrev) || !sameKey(next, actualNext) {
+		return &StaleViewError{
+			Position:     position,
+			ExpectedPrev: prev,
+			ExpectedNext: next,
+			ActualPrev:   actualPrev,
+			ActualNext:   actualNext,
+		}
+	}
+	return nil
+}
+
+func sameKey(a, b *Key) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Compare(*b) == 0
+}
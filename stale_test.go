@@ -0,0 +1,91 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorderableList_InsertChecked(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{
+		item(0, "0|a"),
+		item(1, "0|c"),
+		item(2, "0|e"),
+	}
+
+	a0, c0, e0 := mustKey("0|a"), mustKey("0|c"), mustKey("0|e")
+
+	k, err := list.InsertChecked(1, &a0, &c0, DefaultConfig())
+	r.NoError(err)
+	a.True(k.Compare(a0) > 0 && k.Compare(c0) < 0)
+
+	_, err = list.InsertChecked(0, nil, &a0, DefaultConfig())
+	a.NoError(err)
+	_, err = list.InsertChecked(3, &e0, nil, DefaultConfig())
+	a.NoError(err)
+
+	// The client thinks c and e are adjacent at position 1
+	_, err = list.InsertChecked(1, &c0, &e0, DefaultConfig())
+	r.ErrorIs(err, ErrStaleView)
+
+	var stale *StaleViewError
+	r.ErrorAs(err, &stale)
+	a.Equal(uint(1), stale.Position)
+	a.Equal(a0, *stale.ActualPrev)
+	a.Equal(c0, *stale.ActualNext)
+
+	// The client thinks the list ends at position 2
+	_, err = list.InsertChecked(2, &c0, nil, DefaultConfig())
+	r.ErrorAs(err, &stale)
+	a.Equal(e0, *stale.ActualNext)
+
+	_, err = list.InsertChecked(4, &e0, nil, DefaultConfig())
+	a.ErrorIs(err, ErrOutOfBounds)
+}
+
+func TestReorderableList_MoveChecked(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{
+		item(0, "0|a"),
+		item(1, "0|c"),
+		item(2, "0|e"),
+		item(3, "0|g"),
+	}
+
+	a0, c0, e0, g0 := mustKey("0|a"), mustKey("0|c"), mustKey("0|e"), mustKey("0|g")
+
+	// Move a between e and g
+	changed, err := list.MoveChecked(0, 2, &e0, &g0, DefaultConfig())
+	r.NoError(err)
+	a.Equal([]int{2}, changed)
+	a.Equal("1", list[0].(Identifiable).GetID())
+	a.Equal("0", list[2].(Identifiable).GetID())
+	moved := list[2].GetKey()
+	a.True(moved.Compare(e0) > 0 && moved.Compare(g0) < 0)
+
+	// The client still thinks a is first and moves g in front of it
+	before := list.Keys()
+	_, err = list.MoveChecked(3, 0, nil, &a0, DefaultConfig())
+	r.ErrorIs(err, ErrStaleView)
+
+	var stale *StaleViewError
+	r.ErrorAs(err, &stale)
+	a.Equal(uint(0), stale.Position)
+	a.Nil(stale.ActualPrev)
+	a.Equal(c0, *stale.ActualNext)
+	a.Equal(before, list.Keys())
+
+	// Moving to the end expects no next neighbour
+	_, err = list.MoveChecked(0, 3, &g0, nil, DefaultConfig())
+	r.NoError(err)
+	a.Equal("1", list[3].(Identifiable).GetID())
+
+	_, err = list.MoveChecked(0, 4, &g0, nil, DefaultConfig())
+	a.ErrorIs(err, ErrOutOfBounds)
+}
@@ -0,0 +1,135 @@
+package lexorank
+
+import (
+	"math/big"
+)
+
+// ListStats summarises how keys are distributed across a list.
+type ListStats struct {
+	// Count is the number of items in the list
+	Count int
+
+	// Scale is the rank length all gaps are measured at. A gap of 1 is the
+	// smallest possible difference between two keys of that length.
+	Scale int
+
+	// MinGap, MaxGap and AvgGap describe the numeric distance between
+	// adjacent keys. They are nil for lists with fewer than two items.
+	MinGap *big.Int
+	MaxGap *big.Int
+	AvgGap *big.Int
+
+	// TightestIndex is the index of the item after the smallest gap, i.e.
+	// the insert position with the least room. It is -1 if there is no gap.
+	TightestIndex int
+
+	// TightestMidpoints estimates how many inserts still fit at the tightest
+	// gap in the worst case, as counted by Capacity.Midpoints. It is -1 if
+	// there is no gap and 0 if the tightest neighbours are not ordered.
+	TightestMidpoints int
+
+	// RankLengths counts items by the length of their rank
+	RankLengths map[int]int
+
+	// GapHistogram counts gaps by the number of worst case inserts they have
+	// room for, giving a density profile of the list. Gaps between unordered
+	// or duplicate neighbours are counted under 0.
+	GapHistogram map[int]int
+
+	alphabet *Alphabet
+}
+
+// Stats computes gap and rank length statistics for the list. The list is
+// expected to be sorted; gaps between unsorted neighbours are negative.
+func (l ReorderableList) Stats(config *Config) ListStats {
+	stats := ListStats{
+		Count:             len(l),
+		Scale:             max(config.MaxRankLength, 1),
+		TightestIndex:     -1,
+		TightestMidpoints: -1,
+		RankLengths:       make(map[int]int),
+		GapHistogram:      make(map[int]int),
+		alphabet:          config.alphabet(),
+	}
+
+	for _, it := range l {
+		n := len(it.GetKey().rank)
+		stats.RankLengths[n]++
+		stats.Scale = max(stats.Scale, n)
+	}
+
+	if len(l) < 2 {
+		return stats
+	}
+
+	total := new(big.Int)
+	prev := stats.alphabet.scale(l[0].GetKey().rank, stats.Scale)
+	for i := 1; i < len(l); i++ {
+		curr := stats.alphabet.scale(l[i].GetKey().rank, stats.Scale)
+		gap := new(big.Int).Sub(curr, prev)
+		total.Add(total, gap)
+		stats.GapHistogram[gapMidpoints(gap)]++
+
+		if stats.MinGap == nil || gap.Cmp(stats.MinGap) < 0 {
+			stats.MinGap = gap
+			stats.TightestIndex = i
+		}
+		if stats.MaxGap == nil || gap.Cmp(stats.MaxGap) > 0 {
+			stats.MaxGap = gap
+		}
+
+		prev = curr
+	}
+
+	stats.AvgGap = total.Div(total, big.NewInt(int64(len(l)-1)))
+	stats.TightestMidpoints = gapMidpoints(stats.MinGap)
+
+	return stats
+}
+
+// gapMidpoints returns how many times a gap can be halved before no key fits,
+// matching Capacity.Midpoints.
+func gapMidpoints(gap *big.Int) int {
+	if gap.Sign() <= 0 {
+		return 0
+	}
+	return gap.BitLen() - 1
+}
+
+// gapFraction returns gap as a fraction of the whole key space at the scale.
+func (s ListStats) gapFraction(gap *big.Int) float64 {
+	if gap == nil {
+		return 0
+	}
+	alphabet := s.alphabet
+	if alphabet == nil {
+		alphabet = Base75Alphabet
+	}
+	f, _ := new(big.Rat).SetFrac(gap, alphabet.space(s.Scale)).Float64()
+	return f
+}
+
+// NeedsRebalance reports whether any two adjacent keys are closer than danger,
+// measured like ListStats gaps at the configured MaxRankLength, or have no key
+// left between them at all. It is meant for background jobs deciding whether
+// to rebalance a list before user-facing inserts start failing. Unsorted or
+// duplicate neighbours always need a rebalance.
+func (l ReorderableList) NeedsRebalance(danger int64, config *Config) bool {
+	if len(l) < 2 {
+		return false
+	}
+
+	alphabet := config.alphabet()
+	scale := max(config.MaxRankLength, 1)
+	for _, it := range l {
+		scale = max(scale, len(it.GetKey().rank))
+	}
+
+	limit := big.NewInt(max(danger, 2))
+	for i := 1; i < len(l); i++ {
+		if distanceAt(alphabet, l[i-1].GetKey(), l[i].GetKey(), scale).Cmp(limit) < 0 {
+			return true
+		}
+	}
+	return false
+}
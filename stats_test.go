@@ -0,0 +1,60 @@
+package lexorank
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReorderableList_Stats(t *testing.T) {
+	a := assert.New(t)
+
+	list := ReorderableList{
+		item(0, "0|a"),
+		item(1, "0|b"),
+		item(2, "0|bU"),
+		item(3, "0|d"),
+	}
+
+	stats := list.Stats(DefaultConfig().WithMaxRankLength(2))
+	a.Equal(4, stats.Count)
+	a.Equal(2, stats.Scale)
+	a.Equal(map[int]int{1: 3, 2: 1}, stats.RankLengths)
+
+	a.Equal(big.NewInt(37), stats.MinGap)
+	a.Equal(big.NewInt(113), stats.MaxGap)
+	a.Equal(big.NewInt(75), stats.AvgGap)
+	a.Equal(2, stats.TightestIndex)
+	a.Equal(5, stats.TightestMidpoints)
+	a.Equal(map[int]int{5: 1, 6: 2}, stats.GapHistogram)
+}
+
+func TestReorderableList_Stats_Small(t *testing.T) {
+	a := assert.New(t)
+
+	stats := ReorderableList{item(0, "0|a")}.Stats(DefaultConfig())
+	a.Equal(1, stats.Count)
+	a.Nil(stats.MinGap)
+	a.Equal(-1, stats.TightestIndex)
+	a.Equal(-1, stats.TightestMidpoints)
+	a.Empty(stats.GapHistogram)
+}
+
+func TestReorderableList_NeedsRebalance(t *testing.T) {
+	a := assert.New(t)
+
+	config := DefaultConfig().WithMaxRankLength(2)
+	list := ReorderableList{item(0, "0|a"), item(1, "0|b"), item(2, "0|c")}
+	a.False(list.NeedsRebalance(0, config))
+	a.False(list.NeedsRebalance(75, config))
+	a.True(list.NeedsRebalance(76, config))
+
+	tight := ReorderableList{item(0, "0|a"), item(1, "0|a1")}
+	a.True(tight.NeedsRebalance(0, config), "no key fits between a and a1")
+
+	dupes := ReorderableList{item(0, "0|a"), item(1, "0|a")}
+	a.True(dupes.NeedsRebalance(0, config))
+
+	a.False(ReorderableList{item(0, "0|a")}.NeedsRebalance(1000, config))
+}
@@ -0,0 +1,195 @@
+package lexorank
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// RankStore gives the store-backed operations InsertAt, MoveItem and
+// RebalanceRange access to the persisted items of one list, so they can read
+// only the window of rows around a gap instead of the whole list. Create one
+// per operation over a database transaction, so that everything an operation
+// reads and writes is atomic.
+type RankStore interface {
+	// LoadRange returns the items whose keys lie strictly between after and
+	// before, in ascending key order. A zero key leaves that side open. If
+	// limit is positive only the limit items nearest to after are returned,
+	// or nearest to before if fromEnd is set. Items must implement
+	// Identifiable.
+	LoadRange(ctx context.Context, after, before Key, limit int, fromEnd bool) (ReorderableList, error)
+
+	// UpdateKeys sets the key of every item in keys, by ID. Either all keys
+	// must be written or none.
+	UpdateKeys(ctx context.Context, keys map[string]Key) error
+}
+
+// storeWindow is the number of items on each side of a full gap that the
+// store-backed operations rebalance at first. The window doubles until the
+// gap has room.
+const storeWindow = 8
+
+// InsertAt returns a key for a new item between the items with keys prev and
+// next, where a zero key means the start or the end of the list. If the gap
+// is full, the keys of the nearest neighbours are spread out first and
+// written to the store. The new item itself is not stored; insert it with the
+// returned key in the same transaction.
+func InsertAt(ctx context.Context, s RankStore, prev, next Key, config *Config) (Key, error) {
+	k, updates, err := storeKeyBetween(ctx, s, prev, next, "", config)
+	if err != nil {
+		return Key{}, err
+	}
+	if len(updates) > 0 {
+		if err := s.UpdateKeys(ctx, updates); err != nil {
+			return Key{}, err
+		}
+	}
+	return k, nil
+}
+
+// MoveItem gives the item with the given ID a key between prev and next, like
+// InsertAt, and writes it to the store together with any neighbours that had
+// to be moved, in a single UpdateKeys call.
+func MoveItem(ctx context.Context, s RankStore, id string, prev, next Key, config *Config) (Key, error) {
+	k, updates, err := storeKeyBetween(ctx, s, prev, next, id, config)
+	if err != nil {
+		return Key{}, err
+	}
+	if updates == nil {
+		updates = make(map[string]Key, 1)
+	}
+	updates[id] = k
+	if err := s.UpdateKeys(ctx, updates); err != nil {
+		return Key{}, err
+	}
+	return k, nil
+}
+
+// RebalanceRange spreads the keys of every item strictly between lo and hi
+// evenly across the gap and writes them to the store, for example to make
+// room in a crowded region ahead of time. A zero key leaves that side open.
+// It returns the number of items whose key changed.
+func RebalanceRange(ctx context.Context, s RankStore, lo, hi Key, config *Config) (int, error) {
+	items, err := s.LoadRange(ctx, lo, hi, 0, false)
+	if err != nil {
+		return 0, err
+	}
+	if len(items) == 0 {
+		return 0, nil
+	}
+	if err := items.checkSize(config); err != nil {
+		return 0, err
+	}
+
+	keys, err := BetweenN(lo, hi, len(items), config)
+	if err != nil {
+		return 0, err
+	}
+	updates, err := storeUpdates(items, keys)
+	if err != nil {
+		return 0, err
+	}
+	if len(updates) == 0 {
+		return 0, nil
+	}
+	if err := s.UpdateKeys(ctx, updates); err != nil {
+		return 0, err
+	}
+	return len(updates), nil
+}
+
+// storeKeyBetween returns a key between prev and next and the new keys of the
+// neighbours that must move to make room for it. The item with ID skip, if
+// any, is left out of the window, as it is the one being moved.
+func storeKeyBetween(ctx context.Context, s RankStore, prev, next Key, skip string, config *Config) (Key, map[string]Key, error) {
+	k, err := Between(prev, next, config)
+	if err == nil {
+		return *k, nil, nil
+	}
+	if !errors.Is(err, ErrRebalanceRequired) {
+		return Key{}, nil, err
+	}
+	if config.RebalanceStrategy == RebalanceErrorOnly {
+		return Key{}, nil, err
+	}
+
+	for w := storeWindow; ; w *= 2 {
+		// The nearest items on each side, with one more as a fence that
+		// bounds the window and keeps its key
+		below, err := s.LoadRange(ctx, Key{}, next, w+1, true)
+		if err != nil {
+			return Key{}, nil, err
+		}
+		above, err := s.LoadRange(ctx, prev, Key{}, w+1, false)
+		if err != nil {
+			return Key{}, nil, err
+		}
+
+		var lo, hi Key
+		if len(below) > w {
+			lo, below = below[0].GetKey(), below[1:]
+		}
+		if len(above) > w {
+			hi, above = above[w].GetKey(), above[:w]
+		}
+		below, above = withoutID(below, skip), withoutID(above, skip)
+
+		window := make(ReorderableList, 0, len(below)+len(above))
+		window = append(append(window, below...), above...)
+		keys, err := BetweenN(lo, hi, len(window)+1, config)
+		if errors.Is(err, ErrRebalanceRequired) {
+			if !lo.IsZero() || !hi.IsZero() {
+				continue
+			}
+			return Key{}, nil, errors.Wrap(ErrNormalizationRequired, "no room left in the whole list")
+		}
+		if err != nil {
+			return Key{}, nil, err
+		}
+
+		position := len(below)
+		k := keys[position]
+		keys = append(keys[:position:position], keys[position+1:]...)
+
+		updates, err := storeUpdates(window, keys)
+		if err != nil {
+			return Key{}, nil, err
+		}
+		if config.MaxKeysRewritten > 0 && len(updates) > config.MaxKeysRewritten {
+			return Key{}, nil, &RewriteLimitError{Limit: config.MaxKeysRewritten, Required: len(updates)}
+		}
+		return k, updates, nil
+	}
+}
+
+// storeUpdates returns the new keys of the items whose key differs from the
+// one assigned to them, by ID.
+func storeUpdates(items ReorderableList, keys []Key) (map[string]Key, error) {
+	updates := make(map[string]Key)
+	for i, it := range items {
+		id, ok := it.(Identifiable)
+		if !ok {
+			return nil, errors.Errorf("item %d loaded from the store has no ID", i)
+		}
+		if it.GetKey().Compare(keys[i]) != 0 {
+			updates[id.GetID()] = keys[i]
+		}
+	}
+	return updates, nil
+}
+
+// withoutID returns l without the item with the given ID. An empty id keeps
+// every item.
+func withoutID(l ReorderableList, id string) ReorderableList {
+	if id == "" {
+		return l
+	}
+	out := l[:0:0]
+	for _, it := range l {
+		if i, ok := it.(Identifiable); ok && i.GetID() == id {
+			continue
+		}
+		out = append(out, it)
+	}
+	return out
+}
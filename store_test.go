@@ -0,0 +1,214 @@
+package lexorank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRankStore keeps the items of a list sorted by key.
+type memoryRankStore struct {
+	items   []SnapshotItem
+	loads   int
+	writes  []map[string]Key
+	failErr error
+}
+
+func (m *memoryRankStore) LoadRange(_ context.Context, after, before Key, limit int, fromEnd bool) (ReorderableList, error) {
+	m.loads++
+	var l ReorderableList
+	for _, it := range m.items {
+		if (after.IsZero() || it.Key.Compare(after) > 0) && (before.IsZero() || it.Key.Compare(before) < 0) {
+			l = append(l, &SnapshotItem{ID: it.ID, Key: it.Key})
+		}
+	}
+	if limit > 0 && len(l) > limit {
+		if fromEnd {
+			l = l[len(l)-limit:]
+		} else {
+			l = l[:limit]
+		}
+	}
+	return l, nil
+}
+
+func (m *memoryRankStore) UpdateKeys(_ context.Context, keys map[string]Key) error {
+	if m.failErr != nil {
+		return m.failErr
+	}
+	m.writes = append(m.writes, keys)
+	for i := range m.items {
+		if k, ok := keys[m.items[i].ID]; ok {
+			m.items[i].Key = k
+		}
+	}
+	sort.Slice(m.items, func(i, j int) bool { return m.items[i].Key.Compare(m.items[j].Key) < 0 })
+	return nil
+}
+
+func (m *memoryRankStore) add(id string, k Key) {
+	m.items = append(m.items, SnapshotItem{ID: id, Key: k})
+	sort.Slice(m.items, func(i, j int) bool { return m.items[i].Key.Compare(m.items[j].Key) < 0 })
+}
+
+func (m *memoryRankStore) keys() Keys {
+	keys := make(Keys, len(m.items))
+	for i, it := range m.items {
+		keys[i] = it.Key
+	}
+	return keys
+}
+
+func (m *memoryRankStore) ids() []string {
+	ids := make([]string, len(m.items))
+	for i, it := range m.items {
+		ids[i] = it.ID
+	}
+	return ids
+}
+
+// crowdedStore returns a store of n items with two character keys one slot
+// apart, except for the middle two, which leave no room between them under
+// MaxRankLength 2.
+func crowdedStore(n int) *memoryRankStore {
+	m := &memoryRankStore{}
+	for i := range n {
+		c := 2*i + 1
+		if i >= n/2 {
+			c--
+		}
+		m.items = append(m.items, SnapshotItem{
+			ID:  fmt.Sprintf("i%02d", i),
+			Key: mustKey("0|a" + string(defaultAlphabet[c])),
+		})
+	}
+	return m
+}
+
+func TestInsertAt(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+	ctx := context.Background()
+
+	// Room in the gap: nothing is loaded or written
+	s := &memoryRankStore{items: []SnapshotItem{{ID: "a", Key: mustKey("0|a")}, {ID: "c", Key: mustKey("0|c")}}}
+	k, err := InsertAt(ctx, s, mustKey("0|a"), mustKey("0|c"), DefaultConfig())
+	r.NoError(err)
+	a.Equal("0|b", k.String())
+	a.Zero(s.loads)
+	a.Empty(s.writes)
+
+	// A full gap rebalances the nearest neighbours only
+	config := DefaultConfig().WithMaxRankLength(2)
+	s = crowdedStore(30)
+	first, last := s.items[0].Key, s.items[29].Key
+	k, err = InsertAt(ctx, s, s.items[14].Key, s.items[15].Key, config)
+	r.NoError(err)
+	r.Len(s.writes, 1)
+	a.LessOrEqual(len(s.writes[0]), 2*storeWindow)
+	a.Equal(first, s.items[0].Key)
+	a.Equal(last, s.items[29].Key)
+
+	s.add("new", k)
+	a.True(s.keys().strictlySorted())
+	a.Equal("i14", s.items[14].ID)
+	a.Equal("new", s.items[15].ID)
+	a.Equal("i15", s.items[16].ID)
+}
+
+func TestInsertAt_Edges(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+	ctx := context.Background()
+	config := DefaultConfig().WithMaxRankLength(2)
+
+	s := crowdedStore(30)
+	for i := range 3 {
+		k, err := InsertAt(ctx, s, Key{}, s.items[0].Key, config)
+		r.NoError(err)
+		s.add(fmt.Sprintf("first%d", i), k)
+
+		k, err = InsertAt(ctx, s, s.items[len(s.items)-1].Key, Key{}, config)
+		r.NoError(err)
+		s.add(fmt.Sprintf("last%d", i), k)
+	}
+	a.True(s.keys().strictlySorted())
+	a.Equal("first2", s.items[0].ID)
+	a.Equal("last2", s.items[len(s.items)-1].ID)
+
+	// A list without room anywhere has to be normalized with a longer
+	// MaxRankLength
+	full := &memoryRankStore{}
+	for _, c := range defaultAlphabet[1 : len(defaultAlphabet)-1] {
+		full.items = append(full.items, SnapshotItem{ID: string(c), Key: mustKey("0|" + string(c))})
+	}
+	_, err := InsertAt(ctx, full, full.items[5].Key, full.items[6].Key, DefaultConfig().WithMaxRankLength(1))
+	a.ErrorIs(err, ErrNormalizationRequired)
+	a.Empty(full.writes)
+
+	s = crowdedStore(30)
+	_, err = InsertAt(ctx, s, s.items[14].Key, s.items[15].Key, config.WithRebalanceStrategy(RebalanceErrorOnly))
+	a.ErrorIs(err, ErrRebalanceRequired)
+
+	var limit *RewriteLimitError
+	_, err = InsertAt(ctx, s, s.items[14].Key, s.items[15].Key, config.WithMaxKeysRewritten(2))
+	a.ErrorAs(err, &limit)
+}
+
+func TestMoveItem(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+	ctx := context.Background()
+	config := DefaultConfig().WithMaxRankLength(2)
+
+	s := crowdedStore(30)
+	want := append(append(append([]string{}, s.ids()[1:16]...), "i00"), s.ids()[16:]...)
+
+	k, err := MoveItem(ctx, s, "i00", s.items[15].Key, s.items[16].Key, config)
+	r.NoError(err)
+	r.Len(s.writes, 1)
+	a.Equal(k, s.writes[0]["i00"])
+	a.True(s.keys().strictlySorted())
+	a.Equal(want, s.ids())
+
+	// A failed write leaves the store untouched
+	s.failErr = errors.New("conflict")
+	before := s.keys()
+	_, err = MoveItem(ctx, s, "i00", s.items[3].Key, s.items[4].Key, config)
+	a.ErrorIs(err, s.failErr)
+	a.Equal(before, s.keys())
+}
+
+func TestRebalanceRange(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+	ctx := context.Background()
+
+	s := crowdedStore(10)
+	n, err := RebalanceRange(ctx, s, Key{}, Key{}, DefaultConfig())
+	r.NoError(err)
+	a.Equal(10, n)
+
+	want, err := Spread(0, 10, DefaultConfig())
+	r.NoError(err)
+	a.Equal(Keys(want), s.keys())
+
+	// Rebalancing again changes nothing
+	n, err = RebalanceRange(ctx, s, Key{}, Key{}, DefaultConfig())
+	r.NoError(err)
+	a.Zero(n)
+	a.Len(s.writes, 1)
+
+	// Bounds are exclusive
+	lo, hi := s.items[2].Key, s.items[7].Key
+	n, err = RebalanceRange(ctx, s, lo, hi, DefaultConfig().WithMaxRankLength(3))
+	r.NoError(err)
+	a.Equal(lo, s.items[2].Key)
+	a.Equal(hi, s.items[7].Key)
+	a.True(s.keys().strictlySorted())
+}
@@ -0,0 +1,199 @@
+package lexorank
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// RebalanceCheckpoint records how far a streaming rebalance has progressed.
+// The rebalance visits every row twice, once in each direction, and Done
+// counts the visits out of 2*Total. Cursor is the key of the last row visited
+// in the current direction, or nil at the start of a pass.
+type RebalanceCheckpoint struct {
+	Bucket    uint8 `json:"bucket"`
+	Total     int   `json:"total"`
+	Done      int   `json:"done"`
+	Rewritten int   `json:"rewritten"`
+	Cursor    *Key  `json:"cursor,omitempty"`
+}
+
+// Finished reports whether every row has been visited in both passes.
+func (cp *RebalanceCheckpoint) Finished() bool {
+	return cp.Done >= 2*cp.Total
+}
+
+// RebalanceStore gives a StreamingRebalance cursor-style access to the
+// persisted rows of one list and to the checkpoint it uses to resume.
+type RebalanceStore interface {
+	// Count returns the number of rows in the list.
+	Count(ctx context.Context) (int, error)
+
+	// LoadRange returns a page of rows, like RankStore.LoadRange.
+	LoadRange(ctx context.Context, after, before Key, limit int, fromEnd bool) (ReorderableList, error)
+
+	// SaveChunk sets the key of every row in keys, by ID, and stores the
+	// checkpoint covering them. Both must be written atomically, e.g. in one
+	// transaction. keys may be empty when a chunk needed no changes.
+	SaveChunk(ctx context.Context, keys map[string]Key, cp RebalanceCheckpoint) error
+
+	// LoadCheckpoint returns the last saved checkpoint, or nil if no
+	// rebalance has been started.
+	LoadCheckpoint(ctx context.Context) (*RebalanceCheckpoint, error)
+}
+
+// StreamingRebalance spreads the keys of a list evenly across a bucket like
+// Normalize, for tables too large to load into memory. It pages through the
+// rows ChunkSize at a time and only ever holds one chunk.
+//
+// Every row gets the key Normalize would give it, which depends only on its
+// position and the number of rows, and the list stays ordered after every chunk: a first pass in
+// ascending order moves down the rows whose new key is lower than their old
+// one, and a second pass in descending order moves up the rest. A rebalance
+// interrupted by a crash resumes from its last checkpoint. Rows must not be
+// added or removed while it runs.
+type StreamingRebalance struct {
+	Store  RebalanceStore
+	Config *Config
+
+	// ChunkSize is the number of rows read per chunk
+	ChunkSize int
+
+	// OnChunk is called after each chunk has been saved, with the checkpoint
+	// covering it. A nil OnChunk is ignored.
+	OnChunk func(RebalanceCheckpoint)
+}
+
+// NewStreamingRebalance creates a rebalance with a default chunk size of 1000.
+func NewStreamingRebalance(store RebalanceStore, config *Config) *StreamingRebalance {
+	return &StreamingRebalance{
+		Store:     store,
+		Config:    config,
+		ChunkSize: 1000,
+	}
+}
+
+// Run rebalances a list whose keys are all in bucket, resuming from the stored checkpoint if
+// it belongs to an unfinished run. It stops early if ctx is cancelled; a later
+// Run picks up where it stopped.
+func (s *StreamingRebalance) Run(ctx context.Context, bucket uint8) error {
+	if bucket > MaxBucket {
+		return fmt.Errorf("invalid bucket %d (maximum %d)", bucket, MaxBucket)
+	}
+
+	cp, err := s.Store.LoadCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+	if cp != nil && cp.Finished() {
+		// A finished checkpoint of an earlier run
+		cp = nil
+	}
+	if cp == nil {
+		total, err := s.Store.Count(ctx)
+		if err != nil {
+			return err
+		}
+		cp = &RebalanceCheckpoint{Bucket: bucket, Total: total}
+	}
+	if cp.Bucket != bucket {
+		return fmt.Errorf("unfinished rebalance into bucket %d in progress", cp.Bucket)
+	}
+	if cp.Total == 0 {
+		return nil
+	}
+
+	key := normalizedKey(cp.Total, s.Config)
+
+	for !cp.Finished() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		next, keys, err := s.chunk(ctx, cp, key)
+		if err != nil {
+			return err
+		}
+		if err := s.Store.SaveChunk(ctx, keys, next); err != nil {
+			return err
+		}
+		*cp = next
+
+		if s.OnChunk != nil {
+			s.OnChunk(next)
+		}
+	}
+	return nil
+}
+
+// chunk reads the next chunk after cp and returns the new keys of the rows
+// that move in the current pass, with the checkpoint covering them.
+func (s *StreamingRebalance) chunk(ctx context.Context, cp *RebalanceCheckpoint, key func(int, uint8) (Key, error)) (RebalanceCheckpoint, map[string]Key, error) {
+	descending := cp.Done >= cp.Total
+	visited := cp.Done
+	if descending {
+		visited -= cp.Total
+	}
+	limit := min(max(s.ChunkSize, 1), cp.Total-visited)
+
+	var cursor Key
+	if cp.Cursor != nil {
+		cursor = *cp.Cursor
+	}
+	var rows ReorderableList
+	var err error
+	if descending {
+		rows, err = s.Store.LoadRange(ctx, Key{}, cursor, limit, true)
+	} else {
+		rows, err = s.Store.LoadRange(ctx, cursor, Key{}, limit, false)
+	}
+	if err != nil {
+		return RebalanceCheckpoint{}, nil, err
+	}
+	if len(rows) == 0 {
+		return RebalanceCheckpoint{}, nil, fmt.Errorf("list ran out of rows after %d of %d", visited, cp.Total)
+	}
+
+	// Position of the first row in the chunk
+	first := visited
+	if descending {
+		first = cp.Total - visited - len(rows)
+	}
+
+	keys := make([]Key, len(rows))
+	for i, it := range rows {
+		old := it.GetKey()
+		if old.bucket != cp.Bucket {
+			return RebalanceCheckpoint{}, nil, errors.Wrapf(ErrMixedBuckets, "row %d is in bucket %d", first+i, old.bucket)
+		}
+		keys[i] = old
+
+		k, err := key(first+i, cp.Bucket)
+		if err != nil {
+			return RebalanceCheckpoint{}, nil, err
+		}
+		// Only rows that move in the direction of the pass are rewritten, so
+		// that no row overtakes a neighbour that has not been visited yet
+		if c := k.Compare(old); descending && c > 0 || !descending && c < 0 {
+			keys[i] = k
+		}
+	}
+	updates, err := storeUpdates(rows, keys)
+	if err != nil {
+		return RebalanceCheckpoint{}, nil, err
+	}
+
+	next := *cp
+	next.Done += len(rows)
+	next.Rewritten += len(updates)
+	switch {
+	case next.Done == next.Total:
+		next.Cursor = nil
+	case descending:
+		next.Cursor = &keys[0]
+	default:
+		next.Cursor = &keys[len(keys)-1]
+	}
+	return next, updates, nil
+}
@@ -0,0 +1,159 @@
+package lexorank
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryRebalanceStore struct {
+	memoryRankStore
+	t          *testing.T
+	checkpoint *RebalanceCheckpoint
+	crashAfter int
+	saves      int
+}
+
+func (m *memoryRebalanceStore) Count(context.Context) (int, error) {
+	return len(m.items), nil
+}
+
+func (m *memoryRebalanceStore) SaveChunk(ctx context.Context, keys map[string]Key, cp RebalanceCheckpoint) error {
+	if m.crashAfter > 0 && m.saves == m.crashAfter {
+		return errCrash
+	}
+	m.saves++
+
+	// The list must keep its order after every chunk
+	before := m.ids()
+	require.NoError(m.t, m.UpdateKeys(ctx, keys))
+	require.Equal(m.t, before, m.ids())
+	require.True(m.t, m.keys().strictlySorted())
+
+	m.checkpoint = &cp
+	return nil
+}
+
+func (m *memoryRebalanceStore) LoadCheckpoint(context.Context) (*RebalanceCheckpoint, error) {
+	return m.checkpoint, nil
+}
+
+// clusteredRebalanceStore returns a store whose rows are crowded into the
+// middle of the key space, so the first half has to move down and the second
+// half up.
+func clusteredRebalanceStore(t *testing.T, n int) *memoryRebalanceStore {
+	keys, err := BetweenN(mustKey("0|T"), mustKey("0|U"), n, DefaultConfig())
+	require.NoError(t, err)
+
+	m := &memoryRebalanceStore{t: t}
+	for i, k := range keys {
+		m.items = append(m.items, SnapshotItem{ID: fmt.Sprintf("i%02d", i), Key: k})
+	}
+	return m
+}
+
+// normalizedKeys returns the keys Normalize gives the rows of the store.
+func normalizedKeys(t *testing.T, store *memoryRebalanceStore, config *Config) Keys {
+	l := make(ReorderableList, len(store.items))
+	for i, it := range store.items {
+		l[i] = &SnapshotItem{ID: it.ID, Key: it.Key}
+	}
+	_, err := l.Normalize(config)
+	require.NoError(t, err)
+	return l.Keys()
+}
+
+func TestStreamingRebalance_Run(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	store := clusteredRebalanceStore(t, 10)
+	ids := store.ids()
+	s := NewStreamingRebalance(store, DefaultConfig())
+	s.ChunkSize = 4
+
+	var progress []int
+	s.OnChunk = func(cp RebalanceCheckpoint) {
+		a.Equal(10, cp.Total)
+		progress = append(progress, cp.Done)
+	}
+
+	r.NoError(s.Run(context.Background(), 0))
+	a.Equal([]int{4, 8, 10, 14, 18, 20}, progress)
+	a.Equal(10, store.checkpoint.Rewritten)
+	a.Equal(ids, store.ids())
+
+	want := normalizedKeys(t, clusteredRebalanceStore(t, 10), DefaultConfig())
+	a.Equal(want, store.keys())
+
+	// Running again visits every row but rewrites nothing
+	r.NoError(s.Run(context.Background(), 0))
+	a.Zero(store.checkpoint.Rewritten)
+	a.Equal(want, store.keys())
+}
+
+func TestStreamingRebalance_Resume(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	for crash := 1; crash <= 6; crash++ {
+		store := clusteredRebalanceStore(t, 11)
+		store.crashAfter = crash
+		s := NewStreamingRebalance(store, DefaultConfig())
+		s.ChunkSize = 3
+
+		r.ErrorIs(s.Run(context.Background(), 0), errCrash)
+		r.NotNil(store.checkpoint)
+		a.Equal([]int{3, 6, 9, 11, 14, 17}[crash-1], store.checkpoint.Done)
+
+		r.Error(s.Run(context.Background(), 1))
+
+		store.crashAfter = 0
+		r.NoError(s.Run(context.Background(), 0))
+		a.Equal(11, store.checkpoint.Rewritten)
+
+		a.Equal(normalizedKeys(t, clusteredRebalanceStore(t, 11), DefaultConfig()), store.keys())
+	}
+}
+
+func TestStreamingRebalance_MatchesNormalize(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	configs := []*Config{
+		DefaultConfig(),
+		DefaultConfig().WithNormalizeMinGap(1000),
+		// Too large a gap for the key space falls back to the default spacing
+		DefaultConfig().WithNormalizeMinGap(1 << 60),
+		ProductionConfig().WithAutoNormalize(true, 0),
+	}
+	for _, config := range configs {
+		for _, n := range []int{1, 7, 50} {
+			store := clusteredRebalanceStore(t, n)
+			want := normalizedKeys(t, clusteredRebalanceStore(t, n), config)
+
+			s := NewStreamingRebalance(store, config)
+			s.ChunkSize = 8
+			r.NoError(s.Run(context.Background(), 0))
+			a.Equal(want, store.keys(), "%d rows", n)
+		}
+	}
+}
+
+func TestStreamingRebalance_Errors(t *testing.T) {
+	r := require.New(t)
+
+	store := clusteredRebalanceStore(t, 4)
+	s := NewStreamingRebalance(store, DefaultConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r.ErrorIs(s.Run(ctx, 0), context.Canceled)
+	r.Nil(store.checkpoint)
+
+	r.ErrorIs(s.Run(context.Background(), 1), ErrMixedBuckets)
+	r.Error(s.Run(context.Background(), MaxBucket+1))
+}
@@ -0,0 +1,84 @@
+package lexorank
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Succession yields keys spaced by a fixed step after a start key, one at a
+// time, until a boundary key is reached. It is meant for backfilling ranks onto
+// existing rows in order without generating the whole sequence up front:
+//
+//	s, err := lexorank.NewSuccession(start, lexorank.Key{}, 1000, config)
+//	for s.Next() {
+//		assign(s.Key())
+//	}
+//
+// Keys keep the rank length of the start key, so every one of them sorts after
+// the previous one and Between can still be used around them later.
+type Succession struct {
+	bucket uint8
+	config *Config
+	length int
+	step   *big.Int
+	next   *big.Int
+	bound  *big.Int
+	key    Key
+}
+
+// NewSuccession creates a succession of keys after start, each step apart at
+// the rank length of start, that stops before end. A zero end runs to the top
+// of start's bucket. The start key itself is not yielded.
+func NewSuccession(start, end Key, step int64, config *Config) (*Succession, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("step must be positive, got %d", step)
+	}
+	if start.IsZero() {
+		return nil, fmt.Errorf("start key is required")
+	}
+	if end.IsZero() {
+		end = MaxKey(start.bucket, config)
+	}
+	if start.bucket != end.bucket {
+		return nil, fmt.Errorf("keys must be in the same bucket")
+	}
+
+	alphabet := config.alphabet()
+	if !alphabet.Contains(start.rank) || !alphabet.Contains(end.rank) {
+		return nil, fmt.Errorf("keys must only use characters from the alphabet %q", alphabet)
+	}
+
+	length := max(len(start.rank), 1)
+	lo, hi := rankBoundsAt(alphabet, start.rank, end.rank, length)
+	if top := alphabet.space(length); hi.Cmp(top) > 0 {
+		hi = top
+	}
+
+	s := &Succession{
+		bucket: start.bucket,
+		config: config,
+		length: length,
+		step:   big.NewInt(step),
+		next:   lo.Add(lo, big.NewInt(step)),
+		bound:  hi,
+	}
+	return s, nil
+}
+
+// Next advances to the next key, reporting false once it would reach the
+// boundary.
+func (s *Succession) Next() bool {
+	if s.next.Cmp(s.bound) >= 0 {
+		return false
+	}
+
+	alphabet := s.config.alphabet()
+	s.key = s.config.adopt(*makeKey(s.bucket, alphabet.encode(s.next, s.length)))
+	s.next.Add(s.next, s.step)
+	return true
+}
+
+// Key returns the key produced by the last call to Next.
+func (s *Succession) Key() Key {
+	return s.key
+}
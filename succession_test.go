@@ -0,0 +1,44 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuccession(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	s, err := NewSuccession(mustKey("0|a0"), mustKey("0|a5"), 2, DefaultConfig())
+	r.NoError(err)
+
+	var got []string
+	for s.Next() {
+		got = append(got, s.Key().String())
+	}
+	a.Equal([]string{"0|a2", "0|a4"}, got)
+	a.False(s.Next(), "stays exhausted")
+
+	s, err = NewSuccession(mustKey("0|a0"), mustKey("0|a40"), 2, DefaultConfig())
+	r.NoError(err)
+	got = nil
+	for s.Next() {
+		got = append(got, s.Key().String())
+	}
+	a.Equal([]string{"0|a2"}, got, "the bound is taken at the start's length of 2, where a40 has the same value as a4, and is exclusive")
+
+	s, err = NewSuccession(mustKey("0|zx"), Key{}, 1, DefaultConfig())
+	r.NoError(err)
+	got = nil
+	for s.Next() {
+		got = append(got, s.Key().String())
+	}
+	a.Equal([]string{"0|zy", "0|zz"}, got, "runs to the top of the bucket")
+
+	_, err = NewSuccession(mustKey("0|a"), mustKey("1|b"), 1, DefaultConfig())
+	a.Error(err)
+	_, err = NewSuccession(mustKey("0|a"), mustKey("0|b"), 0, DefaultConfig())
+	a.Error(err)
+}
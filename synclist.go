@@ -0,0 +1,124 @@
+package lexorank
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// SyncReorderableList is a ReorderableList that can be shared between
+// goroutines, e.g. one in-memory board served by many web handlers. Every
+// operation runs under a mutex, so a rebalance can never interleave with
+// another writer, and publishes a fresh ListView afterwards. Readers use View,
+// which never blocks.
+//
+// Unlike ReorderableList, Insert, Append and Prepend add the item to the list
+// themselves, since computing the key and inserting the item must happen under
+// the same lock.
+type SyncReorderableList struct {
+	mu     sync.Mutex
+	list   ReorderableList
+	config *Config
+	view   atomic.Pointer[ListView]
+}
+
+// NewSyncReorderableList creates a list that owns items, which must already
+// be ordered. The caller must not use items directly afterwards.
+func NewSyncReorderableList(items []Reorderable, config *Config) *SyncReorderableList {
+	s := &SyncReorderableList{list: ReorderableList(items), config: config}
+	s.view.Store(s.list.View())
+	return s
+}
+
+// View returns the snapshot published by the last operation. Read keys with
+// ListView.Key, as the items themselves may be rewritten by later operations.
+func (s *SyncReorderableList) View() *ListView {
+	return s.view.Load()
+}
+
+// Len returns the number of items in the list.
+func (s *SyncReorderableList) Len() int {
+	return s.view.Load().Len()
+}
+
+// Insert gives it a key that places it at position and adds it to the list
+// there, rebalancing its neighbours if necessary. The indices of every item
+// with a new key, including the inserted one, are returned in ascending order.
+func (s *SyncReorderableList) Insert(position uint, it Reorderable) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insert(position, it)
+}
+
+// Append adds it to the end of the list. It returns the same as Insert.
+func (s *SyncReorderableList) Append(it Reorderable) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insert(uint(len(s.list)), it)
+}
+
+// Prepend adds it to the start of the list. It returns the same as Insert.
+func (s *SyncReorderableList) Prepend(it Reorderable) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insert(0, it)
+}
+
+func (s *SyncReorderableList) insert(position uint, it Reorderable) ([]int, error) {
+	var k *Key
+	changed, err := s.list.Track(func() error {
+		var err error
+		k, err = s.list.Insert(position, s.config)
+		return err
+	})
+	if err != nil {
+		s.publish()
+		return nil, err
+	}
+
+	it.SetKey(*k)
+	s.list = slices.Insert(s.list, int(position), it)
+	s.publish()
+	return insertedIndices(changed, int(position)), nil
+}
+
+// Move moves the item at index from to index to, like ReorderableList.Move.
+func (s *SyncReorderableList) Move(from, to uint) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.publish()
+	return s.list.Move(from, to, s.config)
+}
+
+// Rebalance makes room next to the item at position, like
+// ReorderableList.Rebalance.
+func (s *SyncReorderableList) Rebalance(position uint, direction int) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.publish()
+	return s.list.Rebalance(position, direction, s.config)
+}
+
+// Normalize spreads the keys of all items evenly, like
+// ReorderableList.Normalize.
+func (s *SyncReorderableList) Normalize() ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.publish()
+	return s.list.Normalize(s.config)
+}
+
+// Do runs fn with exclusive access to the underlying list, for operations
+// without a dedicated method. fn must not retain the list or call other
+// methods of s.
+func (s *SyncReorderableList) Do(fn func(l ReorderableList, config *Config) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.publish()
+	return fn(s.list, s.config)
+}
+
+// publish stores a new view of the list. The mutex must be held.
+func (s *SyncReorderableList) publish() {
+	s.view.Store(s.list.View())
+}
@@ -0,0 +1,71 @@
+package lexorank
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncReorderableList_Concurrent(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	s := NewSyncReorderableList([]Reorderable{item(0, "0|a"), item(1, "0|b")}, DefaultConfig())
+
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 25 {
+				switch i % 3 {
+				case 0:
+					_, err := s.Append(&SnapshotItem{ID: "x"})
+					a.NoError(err)
+				case 1:
+					_, err := s.Insert(uint(s.Len()/2), &SnapshotItem{ID: "y"})
+					a.NoError(err)
+				default:
+					_, err := s.Move(0, uint(g%2))
+					a.NoError(err)
+				}
+
+				v := s.View()
+				a.True(v.Keys().strictlySorted())
+			}
+		}()
+	}
+	wg.Wait()
+
+	v := s.View()
+	r.Equal(2+8*17, v.Len())
+	a.True(v.Keys().strictlySorted())
+}
+
+func TestSyncReorderableList_Insert(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	s := NewSyncReorderableList([]Reorderable{item(0, "0|a"), item(1, "0|c")}, DefaultConfig())
+	before := s.View()
+
+	changed, err := s.Insert(1, &SnapshotItem{ID: "b"})
+	r.NoError(err)
+	a.Equal([]int{1}, changed)
+
+	a.Equal(2, before.Len())
+	v := s.View()
+	a.Equal(3, v.Len())
+	a.Equal("0|b", v.Key(1).String())
+
+	_, err = s.Insert(9, &SnapshotItem{ID: "z"})
+	r.ErrorIs(err, ErrOutOfBounds)
+
+	r.NoError(s.Do(func(l ReorderableList, config *Config) error {
+		_, err := l.Normalize(config)
+		return err
+	}))
+	a.True(s.View().Keys().strictlySorted())
+}
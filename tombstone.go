@@ -0,0 +1,68 @@
+package lexorank
+
+// Deletable describes an item that can be soft-deleted. Tombstoned items keep
+// their key and stay in the list, so the spacing around them is preserved and
+// undoing the delete restores the item to its exact prior position, but they
+// are skipped by the visible position helpers below.
+type Deletable interface {
+	IsDeleted() bool
+}
+
+func isDeleted(r Reorderable) bool {
+	d, ok := r.(Deletable)
+	return ok && d.IsDeleted()
+}
+
+// Visible returns the items that are not soft-deleted, in order.
+func (l ReorderableList) Visible() ReorderableList {
+	visible := make(ReorderableList, 0, len(l))
+	for _, it := range l {
+		if !isDeleted(it) {
+			visible = append(visible, it)
+		}
+	}
+	return visible
+}
+
+// VisibleLen returns the number of items that are not soft-deleted.
+func (l ReorderableList) VisibleLen() int {
+	n := 0
+	for _, it := range l {
+		if !isDeleted(it) {
+			n++
+		}
+	}
+	return n
+}
+
+// VisibleIndex translates a position counted over visible items only into an
+// index into the full list. The result is the index of the visible item
+// currently at that position, or len(l) if position equals VisibleLen.
+func (l ReorderableList) VisibleIndex(position uint) (uint, error) {
+	seen := uint(0)
+	for i, it := range l {
+		if isDeleted(it) {
+			continue
+		}
+		if seen == position {
+			return uint(i), nil
+		}
+		seen++
+	}
+
+	if seen == position {
+		return uint(len(l)), nil
+	}
+	return 0, ErrOutOfBounds
+}
+
+// InsertVisible is like Insert, but position is counted over visible items
+// only. The new key is placed directly before the visible item currently at
+// that position, after any tombstones preceding it.
+func (l ReorderableList) InsertVisible(position uint, config *Config) (*Key, error) {
+	i, err := l.VisibleIndex(position)
+	if err != nil {
+		return nil, err
+	}
+	return l.Insert(i, config)
+}
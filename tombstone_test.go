@@ -0,0 +1,62 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type deletableItem struct {
+	Item[struct{}]
+	deleted bool
+}
+
+func (d *deletableItem) IsDeleted() bool { return d.deleted }
+
+func tombstone(id int, s string, deleted bool) Reorderable {
+	it := item(id, s).(*Item[struct{}])
+	return &deletableItem{Item: *it, deleted: deleted}
+}
+
+func TestReorderableList_Visible(t *testing.T) {
+	a := assert.New(t)
+
+	list := ReorderableList{
+		tombstone(0, "0|a", true),
+		tombstone(1, "0|b", false),
+		tombstone(2, "0|c", true),
+		tombstone(3, "0|d", false),
+	}
+
+	a.Equal(2, list.VisibleLen())
+	a.Equal(ReorderableList{list[1], list[3]}, list.Visible())
+
+	for position, want := range []uint{1, 3, 4} {
+		i, err := list.VisibleIndex(uint(position))
+		a.NoError(err)
+		a.Equal(want, i)
+	}
+
+	_, err := list.VisibleIndex(3)
+	a.ErrorIs(err, ErrOutOfBounds)
+}
+
+func TestReorderableList_InsertVisible(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{
+		tombstone(0, "0|b", false),
+		tombstone(1, "0|c", true),
+		tombstone(2, "0|d", false),
+	}
+
+	// Inserting between the two visible items keeps the tombstone next to b,
+	// so undeleting it restores its original place.
+	k, err := list.InsertVisible(1, DefaultConfig())
+	r.NoError(err)
+	a.True(k.Compare(list[1].GetKey()) > 0)
+	a.True(k.Compare(list[2].GetKey()) < 0)
+	a.Equal("0|c", list[1].GetKey().String(), "tombstone keeps its key")
+}
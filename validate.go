@@ -0,0 +1,75 @@
+package lexorank
+
+import "fmt"
+
+// ViolationCode identifies a rule broken by a client-supplied key.
+type ViolationCode string
+
+const (
+	ViolationBucket     ViolationCode = "bucket"
+	ViolationSeparator  ViolationCode = "separator"
+	ViolationAlphabet   ViolationCode = "alphabet"
+	ViolationTooLong    ViolationCode = "too_long"
+	ViolationNotAfter   ViolationCode = "not_after_prev"
+	ViolationNotBefore  ViolationCode = "not_before_next"
+	ViolationCollision  ViolationCode = "collision"
+	ViolationEmptyInput ViolationCode = "empty"
+)
+
+// Violation is a single rule broken by a client-supplied key.
+type Violation struct {
+	Code    ViolationCode
+	Message string
+}
+
+// ValidateProposedKey checks a key proposed by a client, for example one
+// computed in the browser during a drag and drop, before it is persisted. The
+// key must lie strictly between the neighbours the client claims, where nil
+// means there is none on that side, follow the configuration and not collide
+// with any of the existing keys. It returns a *KeyValidationError listing
+// every violation, or nil if the key is acceptable.
+func ValidateProposedKey(k Key, prev, next *Key, existing Keys, config *Config) error {
+	var violations []Violation
+	add := func(code ViolationCode, format string, args ...any) {
+		violations = append(violations, Violation{Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if k.IsZero() {
+		add(ViolationEmptyInput, "key is empty")
+		return &KeyValidationError{Key: k, Violations: violations}
+	}
+
+	if k.Separator() != config.separator() {
+		add(ViolationSeparator, "separator %q, expected %q", k.Separator(), config.separator())
+	}
+	if !config.alphabet().Contains(k.rank) {
+		add(ViolationAlphabet, "rank uses characters outside the alphabet %q", config.alphabet())
+	}
+	if config.MaxRankLength > 0 && len(k.rank) > config.MaxRankLength {
+		add(ViolationTooLong, "rank length %d exceeds %d", len(k.rank), config.MaxRankLength)
+	}
+
+	for _, n := range []*Key{prev, next} {
+		if n != nil && n.bucket != k.bucket {
+			add(ViolationBucket, "bucket %d, neighbour %s is in bucket %d", k.bucket, n, n.bucket)
+		}
+	}
+	if prev != nil && k.Compare(*prev) <= 0 {
+		add(ViolationNotAfter, "key does not sort after %s", prev)
+	}
+	if next != nil && k.Compare(*next) >= 0 {
+		add(ViolationNotBefore, "key does not sort before %s", next)
+	}
+
+	for _, e := range existing {
+		if e.Compare(k) == 0 {
+			add(ViolationCollision, "key is already in use")
+			break
+		}
+	}
+
+	if len(violations) > 0 {
+		return &KeyValidationError{Key: k, Violations: violations}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateProposedKey(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig()
+	prev, next := mustKey("0|a"), mustKey("0|c")
+	existing := Keys{prev, mustKey("0|b"), next}
+
+	a.NoError(ValidateProposedKey(mustKey("0|aU"), &prev, &next, existing, config))
+	a.NoError(ValidateProposedKey(mustKey("0|0"), nil, &prev, existing, config))
+	a.NoError(ValidateProposedKey(mustKey("0|d"), &next, nil, existing, config))
+
+	err := ValidateProposedKey(mustKey("0|b"), &prev, &next, existing, config)
+	a.ErrorIs(err, ErrInvalidKey)
+
+	var invalid *KeyValidationError
+	r.True(errors.As(err, &invalid))
+	a.Equal([]Violation{{Code: ViolationCollision, Message: "key is already in use"}}, invalid.Violations)
+
+	// Every violation is reported
+	err = ValidateProposedKey(mustKey("1|zzzzzzz"), &prev, &next, existing, config)
+	r.True(errors.As(err, &invalid))
+	a.True(invalid.Has(ViolationTooLong))
+	a.True(invalid.Has(ViolationBucket))
+	a.True(invalid.Has(ViolationNotBefore))
+	a.False(invalid.Has(ViolationNotAfter))
+	a.False(invalid.Has(ViolationCollision))
+
+	err = ValidateProposedKey(mustKey("0|aU"), &prev, &next, nil, CaseInsensitiveConfig())
+	r.True(errors.As(err, &invalid))
+	a.True(invalid.Has(ViolationAlphabet))
+
+	err = ValidateProposedKey(mustKey("0|aa"), &prev, &next, nil, config.WithSeparator(':'))
+	r.True(errors.As(err, &invalid))
+	a.True(invalid.Has(ViolationSeparator))
+
+	err = ValidateProposedKey(Key{}, nil, nil, nil, config)
+	r.True(errors.As(err, &invalid))
+	a.True(invalid.Has(ViolationEmptyInput))
+}
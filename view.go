@@ -0,0 +1,46 @@
+package lexorank
+
+import "sort"
+
+// ListView is an immutable snapshot of the order and keys of a list. Writers
+// can rebalance or normalize the list it was taken from without affecting it,
+// so readers holding a view can iterate a consistent ordering without taking
+// any lock for the duration.
+//
+// The items themselves are shared with the list, so their keys may change
+// after the view was taken. Use Key rather than Item(i).GetKey() to read the
+// key as of the snapshot.
+type ListView struct {
+	items []Reorderable
+	keys  Keys
+}
+
+// View takes a snapshot of the list. It copies the item references and keys,
+// which is O(n), so writers should take it once per mutation and share it
+// between readers.
+func (l ReorderableList) View() *ListView {
+	return &ListView{
+		items: append([]Reorderable(nil), l...),
+		keys:  l.Keys(),
+	}
+}
+
+// Len returns the number of items in the view.
+func (v *ListView) Len() int { return len(v.items) }
+
+// Item returns the item at index i.
+func (v *ListView) Item(i int) Reorderable { return v.items[i] }
+
+// Key returns the key the item at index i had when the view was taken.
+func (v *ListView) Key(i int) Key { return v.keys[i] }
+
+// Keys returns a copy of the keys in the view.
+func (v *ListView) Keys() Keys { return append(Keys(nil), v.keys...) }
+
+// Search returns the index of the first key in the view that is greater than
+// or equal to k, using binary search.
+func (v *ListView) Search(k Key) int {
+	return sort.Search(len(v.keys), func(i int) bool {
+		return v.keys[i].Compare(k) >= 0
+	})
+}
@@ -0,0 +1,29 @@
+package lexorank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorderableList_View(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	list := ReorderableList{item(0, "0|a"), item(1, "0|a"), item(2, "0|c")}
+	v := list.View()
+
+	_, err := list.Normalize(DefaultConfig())
+	r.NoError(err)
+	list[0], list[2] = list[2], list[0]
+
+	a.Equal(3, v.Len())
+	a.Equal("0|a", v.Key(1).String(), "keys are as of the snapshot")
+	a.Equal("0", v.Item(0).(Identifiable).GetID(), "order is as of the snapshot")
+	a.Equal(2, v.Search(mustKey("0|b")))
+
+	keys := v.Keys()
+	keys[0] = mustKey("0|z")
+	a.Equal("0|a", v.Key(0).String())
+}
@@ -0,0 +1,81 @@
+package lexorank
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBetween_Watermark(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	config := DefaultConfig().WithWatermark('0')
+
+	k, err := Between(mustKey("0|a"), mustKey("0|c"), config)
+	r.NoError(err)
+	a.Equal("0|b0", k.String())
+
+	// The marker must not push the key past a longer neighbour
+	k, err = Between(mustKey("0|a"), mustKey("0|b0"), config)
+	r.NoError(err)
+	a.Equal("0|aU0", k.String())
+
+	// Full length neighbours are truncated to leave room for the marker
+	k, err = Between(mustKey("0|aaaaa1"), mustKey("0|aaaac1"), config)
+	r.NoError(err)
+	a.Equal("0|aaaab0", k.String())
+
+	_, err = Between(mustKey("0|aaaaa1"), mustKey("0|aaaaa2"), config)
+	a.ErrorIs(err, ErrRebalanceRequired)
+
+	_, err = Between(mustKey("0|a"), mustKey("0|c"), DefaultConfig().WithWatermark('|'))
+	a.Error(err)
+}
+
+func TestScanWatermarks(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	old := DefaultConfig()
+	next := DefaultConfig().WithWatermark('1')
+	rnd := rand.New(rand.NewSource(1))
+
+	list := ReorderableList{}
+	inserted := 0
+	for i := range 300 {
+		config := old
+		if i%3 == 0 {
+			config = next
+		}
+
+		position := uint(rnd.Intn(len(list) + 1))
+		prev, nxt := Key{}, Key{}
+		if position > 0 {
+			prev = list[position-1].GetKey()
+		}
+		if position < uint(len(list)) {
+			nxt = list[position].GetKey()
+		}
+
+		k, err := Between(prev, nxt, config)
+		if err != nil {
+			continue
+		}
+		if config == next {
+			inserted++
+		}
+
+		it := item(i, k.String())
+		list = append(list[:position], append(ReorderableList{it}, list[position:]...)...)
+	}
+	r.True(list.StrictlySorted())
+
+	report := list.ScanWatermarks('1')
+	a.Equal(len(list), report.Total)
+	a.GreaterOrEqual(report.Counts['1'], inserted)
+	a.Equal(report.Total, report.Counts['1']+report.Unmarked)
+	a.InDelta(float64(report.Counts['1'])/float64(report.Total), report.Share('1'), 1e-9)
+}